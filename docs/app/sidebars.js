@@ -100,6 +100,9 @@ const sidebars = {
             }, {
                 type: 'doc',
                 id: 'ide_configuration/eclipse',
+            }, {
+                type: 'doc',
+                id: 'ide_configuration/jetbrains',
             }, {
                 type: 'doc',
                 id: 'ide_configuration/vscode'
@@ -0,0 +1,91 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+)
+
+type cacheLoginCmdFlags struct {
+	login string
+}
+
+func cacheCmd() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage credentials for private binary caches",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	command.AddCommand(cacheLoginCmd())
+	command.AddCommand(cacheLogoutCmd())
+	return command
+}
+
+func cacheLoginCmd() *cobra.Command {
+	flags := cacheLoginCmdFlags{}
+	command := &cobra.Command{
+		Use:   "login <cache-url>",
+		Short: "Store the credentials for a private binary cache in your keychain",
+		Long: "Store the credentials for a private binary cache in your keychain.\n\n" +
+			"Devbox asks for the password or auth token, or reads it from stdin when " +
+			"stdin isn't a terminal. It gives the credentials to nix whenever the cache " +
+			"is one of the project's nix.substituters.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := readCachePassword(cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+			if err := devbox.CacheLogin(args[0], flags.login, password); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Stored the credentials for %s in your keychain.\n", args[0])
+			return nil
+		},
+	}
+	command.Flags().StringVar(
+		&flags.login, "login", "",
+		"login for caches that use basic auth. Leave it empty for auth tokens, like Cachix's.",
+	)
+	return command
+}
+
+func cacheLogoutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout <cache-url>",
+		Short: "Remove the credentials for a private binary cache from your keychain",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := devbox.CacheLogout(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Removed the credentials for %s.\n", args[0])
+			return nil
+		},
+	}
+}
+
+func readCachePassword(stdin io.Reader, cacheURL string) (string, error) {
+	if f, ok := stdin.(*os.File); !ok || !isatty.IsTerminal(f.Fd()) {
+		data, err := io.ReadAll(stdin)
+		return strings.TrimSpace(string(data)), errors.WithStack(err)
+	}
+	var password string
+	prompt := &survey.Password{Message: fmt.Sprintf("Password or auth token for %s:", cacheURL)}
+	err := survey.AskOne(prompt, &password)
+	return password, errors.WithStack(err)
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+type ciCmdFlags struct {
+	config configFlags
+	local  bool
+}
+
+func ciCmd() *cobra.Command {
+	flags := ciCmdFlags{}
+	command := &cobra.Command{
+		Use:   "ci [-- <act flags>]",
+		Short: "List or locally run your project's GitHub Actions workflows",
+		Long: "List the jobs of your project's GitHub Actions workflows. With --local, run " +
+			"them in act's Linux containers with your devbox environment, so you can test " +
+			"a workflow before pushing it. Flags after -- are passed on to act.",
+		Example: "\nRun every job that runs on push:\n\n  devbox ci --local\n\n" +
+			"Run the test job:\n\n  devbox ci --local -- -j test",
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:         flags.config.path,
+				Environment: flags.config.environment,
+				Stderr:      cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return box.RunCI(cmd.Context(), flags.local, args)
+		},
+	}
+
+	flags.config.register(command)
+	command.Flags().BoolVar(
+		&flags.local, "local", false, "run the jobs on this machine with act")
+	return command
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+func exportClosureCmd() *cobra.Command {
+	flags := runCmdFlags{}
+	command := &cobra.Command{
+		Use:   "export-closure <file>",
+		Short: "Export your project's environment to a file for machines without internet access",
+		Long: "Export every store path that your project's environment needs to a file. " +
+			"Copy the file to a machine without internet access, run `devbox import-closure <file>`, " +
+			"and then start the shell with `devbox shell --offline`. Both machines must have the same " +
+			"system, such as x86_64-linux.",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:           flags.config.path,
+				Environment:   flags.config.environment,
+				PackageGroups: flags.groups.PackageGroups(),
+				Stderr:        cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if err := box.ExportClosure(cmd.Context(), args[0]); err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Exported the environment to %s.\n", args[0])
+			return nil
+		},
+	}
+
+	flags.config.register(command)
+	flags.groups.register(command)
+	return command
+}
+
+func importClosureCmd() *cobra.Command {
+	flags := configFlags{}
+	command := &cobra.Command{
+		Use:   "import-closure <file>",
+		Short: "Import an environment that export-closure wrote to a file",
+		Long: "Import the store paths in a file that `devbox export-closure` wrote into the Nix " +
+			"store. Nix only imports them if you're root or a trusted user. Start the shell " +
+			"afterwards with `devbox shell --offline`.",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:         flags.path,
+				Environment: flags.environment,
+				Stderr:      cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if err := box.ImportClosure(cmd.Context(), args[0]); err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "Imported the environment. Start it with `devbox shell --offline`.")
+			return nil
+		},
+	}
+
+	flags.register(command)
+	return command
+}
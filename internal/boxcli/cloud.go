@@ -21,6 +21,8 @@ type cloudShellCmdFlags struct {
 	config configFlags
 
 	githubUsername string
+	host           string
+	forwards       []string
 }
 
 func cloudCmd() *cobra.Command {
@@ -61,6 +63,10 @@ func cloudShellCmd() *cobra.Command {
 	command := &cobra.Command{
 		Use:   "shell",
 		Short: "[Preview] Shell into a cloud environment that matches your local devbox environment",
+		Long: "Shell into a cloud environment that matches your local devbox environment. " +
+			"With --host, it uses a Linux machine that you can ssh into instead of a Devbox Cloud VM: " +
+			"it installs devbox there if it's missing, syncs your project to it, and starts a devbox shell.",
+		Example: "  devbox cloud shell --host me@build-box --forward 8080 --forward 3000:5432",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runCloudShellCmd(cmd, &flags)
 		},
@@ -70,6 +76,12 @@ func cloudShellCmd() *cobra.Command {
 	command.Flags().StringVarP(
 		&flags.githubUsername, "username", "u", "", "Github username to use for ssh",
 	)
+	command.Flags().StringVar(
+		&flags.host, "host", "", "ssh destination of your own machine, such as me@build-box or build-box:2222",
+	)
+	command.Flags().StringSliceVar(
+		&flags.forwards, "forward", nil, "port to forward to the --host machine, such as 8080 or <local-port>:<remote-port>",
+	)
 	return command
 }
 
@@ -148,7 +160,17 @@ func runCloudShellCmd(cmd *cobra.Command, flags *cloudShellCmdFlags) error {
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	return cloud.Shell(cmd.Context(), cmd.ErrOrStderr(), box.ProjectDir(), flags.githubUsername)
+	if flags.host != "" {
+		return cloud.RemoteShell(cmd.Context(), cmd.ErrOrStderr(), box.ProjectDir(), cloud.RemoteOpts{
+			Host:         flags.host,
+			Forwards:     flags.forwards,
+			ForwardAgent: box.Config().ForwardsAgents(),
+		})
+	}
+	if len(flags.forwards) > 0 {
+		return usererr.New("--forward only works with --host. Use devbox cloud forward for Devbox Cloud VMs.")
+	}
+	return cloud.Shell(cmd.Context(), cmd.ErrOrStderr(), box.ProjectDir(), box.Config().FileName(), flags.githubUsername)
 }
 
 func runCloudInit(cmd *cobra.Command, flags *cloudShellCmdFlags) error {
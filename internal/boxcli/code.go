@@ -0,0 +1,53 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+type codeCmdFlags struct {
+	envFlag
+	config configFlags
+	editor string
+}
+
+func codeCmd() *cobra.Command {
+	flags := codeCmdFlags{}
+	command := &cobra.Command{
+		Use:   "code [path]...",
+		Short: "Open VS Code, or another editor, with your devbox environment",
+		Long: "Open VS Code, or another editor, with your devbox environment, so its " +
+			"terminals, debuggers, and language servers use your project's packages. " +
+			"It opens the project directory if you don't pass any paths. The editor is " +
+			"--editor, or shell.editor in devbox.json, or code.",
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			env, err := flags.Env(flags.config.path)
+			if err != nil {
+				return err
+			}
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:         flags.config.path,
+				Environment: flags.config.environment,
+				Env:         env,
+				Stderr:      cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return box.OpenEditor(cmd.Context(), flags.editor, args...)
+		},
+	}
+
+	flags.config.register(command)
+	flags.envFlag.register(command)
+	command.Flags().StringVar(
+		&flags.editor, "editor", "", "command that opens the editor, such as cursor or \"code --new-window\"")
+	return command
+}
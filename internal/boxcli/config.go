@@ -15,7 +15,7 @@ type configFlags struct {
 
 func (flags *configFlags) register(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(
-		&flags.path, "config", "c", "", "path to directory containing a devbox.json config file",
+		&flags.path, "config", "c", "", "path to a devbox.json config file or the directory containing it",
 	)
 	cmd.Flags().StringVar(
 		&flags.environment, "environment", "dev", "environment to use, when supported (e.g.secrets support dev, prod, preview.)",
@@ -24,7 +24,7 @@ func (flags *configFlags) register(cmd *cobra.Command) {
 
 func (flags *configFlags) registerPersistent(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(
-		&flags.path, "config", "c", "", "path to directory containing a devbox.json config file",
+		&flags.path, "config", "c", "", "path to a devbox.json config file or the directory containing it",
 	)
 	cmd.PersistentFlags().StringVar(
 		&flags.environment, "environment", "dev", "environment to use, when supported (e.g. secrets support dev, prod, preview.)",
@@ -0,0 +1,44 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+)
+
+type configMigrateCmdFlags struct {
+	config configFlags
+}
+
+func configCmd() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "config",
+		Short: "Manage your devbox.json",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	command.AddCommand(configMigrateCmd())
+	return command
+}
+
+func configMigrateCmd() *cobra.Command {
+	flags := configMigrateCmdFlags{}
+	command := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade devbox.json to the latest schema version",
+		Long: "Upgrade devbox.json to the latest schema version.\n\n" +
+			"Renamed and restructured fields are updated in place, and the config's " +
+			"\"version\" field is set to the latest version.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return devbox.MigrateConfig(flags.config.path, cmd.ErrOrStderr())
+		},
+	}
+	flags.config.register(command)
+	return command
+}
@@ -20,6 +20,9 @@ type generateCmdFlags struct {
 	printEnvrcContent bool
 	githubUsername    string
 	rootUser          bool
+	image             string
+	feature           bool
+	groups            packageGroupFlags
 }
 
 type GenerateReadmeCmdFlags struct {
@@ -43,6 +46,8 @@ func generateCmd() *cobra.Command {
 	command.AddCommand(debugCmd())
 	command.AddCommand(direnvCmd())
 	command.AddCommand(genReadmeCmd())
+	command.AddCommand(jetbrainsCmd())
+	command.AddCommand(k8sDevPodCmd())
 	command.AddCommand(sshConfigCmd())
 	flags.config.register(command)
 
@@ -77,6 +82,10 @@ func devcontainerCmd() *cobra.Command {
 		&flags.force, "force", "f", false, "force overwrite on existing files")
 	command.Flags().BoolVar(
 		&flags.rootUser, "root-user", false, "Use root as default user inside the container")
+	command.Flags().BoolVar(
+		&flags.feature, "feature", false,
+		"Install devbox with a dev container feature instead of a Dockerfile, for GitHub Codespaces and DevPod")
+	flags.groups.register(command)
 	return command
 }
 
@@ -97,6 +106,7 @@ func dockerfileCmd() *cobra.Command {
 	command.Flags().BoolVar(
 		&flags.rootUser, "root-user", false, "Use root as default user inside the container")
 	flags.config.register(command)
+	flags.groups.register(command)
 	return command
 }
 
@@ -124,6 +134,47 @@ func direnvCmd() *cobra.Command {
 	return command
 }
 
+func jetbrainsCmd() *cobra.Command {
+	flags := &generateCmdFlags{}
+	command := &cobra.Command{
+		Use:   "jetbrains",
+		Short: "Configure JetBrains IDEs, such as GoLand, to use this project's packages",
+		Long: "Point the Go SDK and Node.js interpreter in the project's .idea/ directory at " +
+			"this project's packages, and add a run configuration for each script. " +
+			"Run it again after you add or update packages.\n\n" +
+			"It doesn't configure PyCharm's Python interpreter, since PyCharm keeps interpreters " +
+			"in the IDE's settings instead of the project. It prints the interpreter to add in " +
+			"Settings > Python Interpreter instead.",
+		Args: cobra.MaximumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerateCmd(cmd, flags)
+		},
+	}
+	flags.config.register(command)
+	return command
+}
+
+func k8sDevPodCmd() *cobra.Command {
+	flags := &generateCmdFlags{}
+	command := &cobra.Command{
+		Use:   "k8s-devpod",
+		Short: "Generate a Kubernetes manifest that runs this project as a dev pod",
+		Long: "Generate devbox-devpod.yaml, a Kubernetes Deployment that runs the image built from " +
+			"`devbox generate dockerfile`, with the project on a persistent volume and the env from devbox.json. " +
+			"Start a shell in the pod with kubectl exec.",
+		Args: cobra.MaximumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerateCmd(cmd, flags)
+		},
+	}
+	command.Flags().BoolVarP(
+		&flags.force, "force", "f", false, "force overwrite existing files")
+	command.Flags().StringVar(
+		&flags.image, "image", "", "image that the pod runs, such as registry.example.com/my-app:dev (default \"devbox-<project>:latest\")")
+	flags.config.register(command)
+	return command
+}
+
 func sshConfigCmd() *cobra.Command {
 	flags := &generateCmdFlags{}
 	command := &cobra.Command{
@@ -183,9 +234,10 @@ func genReadmeCmd() *cobra.Command {
 func runGenerateCmd(cmd *cobra.Command, flags *generateCmdFlags) error {
 	// Check the directory exists.
 	box, err := devbox.Open(&devopt.Opts{
-		Dir:         flags.config.path,
-		Environment: flags.config.environment,
-		Stderr:      cmd.ErrOrStderr(),
+		Dir:           flags.config.path,
+		Environment:   flags.config.environment,
+		PackageGroups: flags.groups.PackageGroups(),
+		Stderr:        cmd.ErrOrStderr(),
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -193,6 +245,8 @@ func runGenerateCmd(cmd *cobra.Command, flags *generateCmdFlags) error {
 	generateOpts := devopt.GenerateOpts{
 		Force:    flags.force,
 		RootUser: flags.rootUser,
+		Image:    flags.image,
+		Feature:  flags.feature,
 	}
 	switch cmd.Use {
 	case "debug":
@@ -201,6 +255,10 @@ func runGenerateCmd(cmd *cobra.Command, flags *generateCmdFlags) error {
 		return box.GenerateDevcontainer(cmd.Context(), generateOpts)
 	case "dockerfile":
 		return box.GenerateDockerfile(cmd.Context(), generateOpts)
+	case "k8s-devpod":
+		return box.GenerateK8sDevPod(cmd.Context(), generateOpts)
+	case "jetbrains":
+		return box.GenerateJetBrains(cmd.Context())
 	}
 	return nil
 }
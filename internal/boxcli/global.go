@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
 	"go.jetpack.io/devbox/internal/devbox"
 	"go.jetpack.io/devbox/internal/devbox/devopt"
 	"go.jetpack.io/devbox/internal/ux"
@@ -52,6 +53,7 @@ func globalCmd() *cobra.Command {
 
 	// Create list for non-global? Mike: I want it :)
 	globalCmd.AddCommand(globalListCmd())
+	globalCmd.AddCommand(globalExportCmd())
 
 	return globalCmd
 }
@@ -90,6 +92,37 @@ func listGlobalCmdFunc(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func globalExportCmd() *cobra.Command {
+	format := ""
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Export global packages, env and init hook to another tool",
+		Long: "Export global packages, env and init hook to another tool. With --format home-manager, " +
+			"it prints a home-manager module that installs the same package versions as devbox global.",
+		Example: "  devbox global export --format home-manager > ~/.config/home-manager/devbox.nix",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "home-manager" {
+				return usererr.New("--format can only be home-manager, not %q.", format)
+			}
+			path, err := ensureGlobalConfig(cmd)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:    path,
+				Stderr: cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return box.ExportHomeManager(cmd.OutOrStdout())
+		},
+	}
+	command.Flags().StringVar(&format, "format", "home-manager", "format to export to. Only home-manager is supported")
+	return command
+}
+
 var globalConfigPath string
 
 func ensureGlobalConfig(cmd *cobra.Command) (string, error) {
@@ -0,0 +1,30 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+// to be composed into xyzCmdFlags structs
+type packageGroupFlags struct {
+	only []string
+	skip []string
+}
+
+func (flags *packageGroupFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&flags.only, "only", nil,
+		"only install packages in the given groups (packages without groups are always installed)",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.skip, "skip", nil, "skip installing packages in the given groups",
+	)
+}
+
+func (flags *packageGroupFlags) PackageGroups() devopt.PackageGroups {
+	return devopt.PackageGroups{Only: flags.only, Skip: flags.skip}
+}
@@ -4,31 +4,85 @@
 package boxcli
 
 import (
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
 )
 
+type initCmdFlags struct {
+	format string
+	yes    bool
+	from   string
+}
+
 func initCmd() *cobra.Command {
+	flags := &initCmdFlags{}
 	command := &cobra.Command{
 		Use:   "init [<dir>]",
 		Short: "Initialize a directory as a devbox project",
 		Long: "Initialize a directory as a devbox project. " +
-			"This will create an empty devbox.json in the current directory. " +
-			"You can then add packages using `devbox add`",
+			"This will create a devbox.json in the current directory with the " +
+			"settings and packages devbox recommends for the projects it " +
+			"detects. You can then add more packages using `devbox add`",
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInitCmd(cmd, args)
+			return runInitCmd(cmd, args, flags)
 		},
 	}
 
+	command.Flags().StringVar(
+		&flags.format, "format", "json",
+		"format of the config file to create. One of json (devbox.json) or yaml (devbox.yaml)",
+	)
+	command.Flags().BoolVarP(
+		&flags.yes, "yes", "y", false,
+		"add the recommended packages without asking",
+	)
+	command.Flags().StringVar(
+		&flags.from, "from", "",
+		"migrate the packages and settings of an existing shell.nix, flake.nix or Brewfile",
+	)
+
 	return command
 }
 
-func runInitCmd(cmd *cobra.Command, args []string) error {
+func runInitCmd(cmd *cobra.Command, args []string, flags *initCmdFlags) error {
 	path := pathArg(args)
 
-	_, err := devbox.InitConfig(path, cmd.ErrOrStderr())
+	_, err := devbox.InitConfigWithOpts(path, devopt.InitOpts{
+		Format:         flags.format,
+		SelectPackages: selectPackagesFunc(flags),
+		From:           flags.from,
+	}, cmd.ErrOrStderr())
 	return errors.WithStack(err)
 }
+
+// selectPackagesFunc returns how devbox init chooses the recommended packages
+// to add: all of them with --yes, the ones the user selects in a terminal, or
+// none (they're only suggested) otherwise.
+func selectPackagesFunc(flags *initCmdFlags) func([]string) ([]string, error) {
+	if flags.yes {
+		return func(pkgs []string) ([]string, error) { return pkgs, nil }
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return nil
+	}
+	return func(pkgs []string) ([]string, error) {
+		var selected []string
+		prompt := &survey.MultiSelect{
+			Message: "Select the packages to add to your devbox config:",
+			Options: pkgs,
+			Default: pkgs,
+		}
+		if err := survey.AskOne(prompt, &selected); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return selected, nil
+	}
+}
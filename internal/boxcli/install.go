@@ -13,8 +13,13 @@ import (
 	"go.jetpack.io/devbox/internal/devbox/devopt"
 )
 
+type installCmdFlags struct {
+	runCmdFlags
+	frozen bool
+}
+
 func installCmd() *cobra.Command {
-	flags := runCmdFlags{}
+	flags := installCmdFlags{}
 	command := &cobra.Command{
 		Use:     "install",
 		Short:   "Install all packages mentioned in devbox.json",
@@ -26,20 +31,30 @@ func installCmd() *cobra.Command {
 	}
 
 	flags.config.register(command)
+	flags.groups.register(command)
+	command.Flags().BoolVar(
+		&flags.frozen, "frozen", false,
+		"fail if devbox.lock is out of sync with devbox.json instead of updating it")
 
 	return command
 }
 
-func installCmdFunc(cmd *cobra.Command, flags runCmdFlags) error {
+func installCmdFunc(cmd *cobra.Command, flags installCmdFlags) error {
 	// Check the directory exists.
 	box, err := devbox.Open(&devopt.Opts{
-		Dir:         flags.config.path,
-		Environment: flags.config.environment,
-		Stderr:      cmd.ErrOrStderr(),
+		Dir:           flags.config.path,
+		Environment:   flags.config.environment,
+		PackageGroups: flags.groups.PackageGroups(),
+		Stderr:        cmd.ErrOrStderr(),
 	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	if flags.frozen {
+		if err := box.CheckLockfileInSync(); err != nil {
+			return err
+		}
+	}
 	if err = box.Install(cmd.Context()); err != nil {
 		return errors.WithStack(err)
 	}
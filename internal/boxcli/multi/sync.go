@@ -43,12 +43,14 @@ func SyncLockfiles(pkgs []string) error {
 				}
 				if pkg.LastModified != latestPkg.LastModified {
 					lockFile.Packages[key].AllowInsecure = latestPkg.AllowInsecure
+					lockFile.Packages[key].Channel = latestPkg.Channel
 					lockFile.Packages[key].LastModified = latestPkg.LastModified
 					// PluginVersion is intentionally omitted
 					lockFile.Packages[key].Resolved = latestPkg.Resolved
 					lockFile.Packages[key].Source = latestPkg.Source
 					lockFile.Packages[key].Version = latestPkg.Version
 					lockFile.Packages[key].Systems = latestPkg.Systems
+					lockFile.Packages[key].NarHash = latestPkg.NarHash
 					changed = true
 				}
 			}
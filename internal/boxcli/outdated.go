@@ -0,0 +1,77 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+type outdatedCmdFlags struct {
+	config configFlags
+	json   bool
+}
+
+func outdatedCmd() *cobra.Command {
+	flags := outdatedCmdFlags{}
+	command := &cobra.Command{
+		Use:   "outdated",
+		Short: "List packages that have newer versions",
+		Long: "Compare every package in devbox.lock against the newest available version " +
+			"and list the packages that can be upgraded. Wanted is the newest version that " +
+			"devbox.json allows, which `devbox upgrade` installs, and latest is the newest " +
+			"version of the package.",
+		Args:    cobra.ExactArgs(0),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return outdatedCmdFunc(cmd, flags)
+		},
+	}
+
+	flags.config.register(command)
+	command.Flags().BoolVar(&flags.json, "json", false, "output in json format")
+	return command
+}
+
+func outdatedCmdFunc(cmd *cobra.Command, flags outdatedCmdFlags) error {
+	box, err := devbox.Open(&devopt.Opts{
+		Dir:         flags.config.path,
+		Environment: flags.config.environment,
+		Stderr:      cmd.ErrOrStderr(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	outdated, err := box.Outdated(cmd.Context())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	w := cmd.OutOrStdout()
+	if flags.json {
+		out, err := json.MarshalIndent(outdated, "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Fprintln(w, string(out))
+		return nil
+	}
+	if len(outdated) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "All packages are up to date.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 3, 2, 4, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tCURRENT\tWANTED\tLATEST")
+	for _, pkg := range outdated {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", pkg.Name, pkg.Current, pkg.Wanted, pkg.Latest)
+	}
+	return errors.WithStack(tw.Flush())
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/initrec"
+	"go.jetpack.io/devbox/internal/ux"
+)
+
+type planCmdFlags struct {
+	json bool
+}
+
+func planCmd() *cobra.Command {
+	flags := planCmdFlags{}
+	command := &cobra.Command{
+		Use:   "plan [<dir>]",
+		Short: "Show what devbox detects and recommends for a directory",
+		Long: "Show the projects that devbox detects in a directory, the packages " +
+			"they need, and the env variables, init hook and scripts that " +
+			"`devbox init` adds for them. Nothing is installed or written.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return planCmdFunc(cmd, args, flags)
+		},
+	}
+
+	command.Flags().BoolVar(&flags.json, "json", false, "output in json format")
+	return command
+}
+
+func planCmdFunc(cmd *cobra.Command, args []string, flags planCmdFlags) error {
+	plan, err := devbox.Plan(pathArg(args))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	w := cmd.OutOrStdout()
+	if flags.json {
+		b, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Fprintln(w, string(b))
+		return nil
+	}
+
+	if len(plan.Projects) == 0 {
+		fmt.Fprintln(w, "No projects detected.")
+		return nil
+	}
+	printPlan(w, plan)
+	for _, conflict := range plan.Conflicts {
+		ux.Fwarning(cmd.ErrOrStderr(), "%s\n", conflict)
+	}
+	for _, warning := range plan.Warnings {
+		ux.Fwarning(cmd.ErrOrStderr(), "%s\n", warning)
+	}
+	return nil
+}
+
+func printPlan(w io.Writer, plan *initrec.Recommendation) {
+	fmt.Fprintf(w, "Projects: %s\n", strings.Join(plan.Projects, ", "))
+	fmt.Fprintf(w, "Packages: %s\n", strings.Join(plan.Packages, " "))
+	if len(plan.Env) > 0 {
+		fmt.Fprintln(w, "Env:")
+		for _, k := range sortedKeys(plan.Env) {
+			fmt.Fprintf(w, "  %s=%s\n", k, plan.Env[k])
+		}
+	}
+	if len(plan.InitHook) > 0 {
+		fmt.Fprintln(w, "Init hook:")
+		for _, cmd := range plan.InitHook {
+			fmt.Fprintf(w, "  %s\n", cmd)
+		}
+	}
+	if len(plan.Scripts) > 0 {
+		fmt.Fprintln(w, "Scripts:")
+		for _, name := range sortedKeys(plan.Scripts) {
+			fmt.Fprintf(w, "  %s:\n", name)
+			for _, cmd := range plan.Scripts[name] {
+				fmt.Fprintf(w, "    %s\n", cmd)
+			}
+			if inputs := plan.ScriptInputs[name]; len(inputs) > 0 {
+				fmt.Fprintf(w, "    (inputs: %s)\n", strings.Join(inputs, ", "))
+			}
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.jetpack.io/devbox/internal/envir"
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec"
+)
+
+// runPlanCmd runs devbox plan with args and returns its stdout.
+func runPlanCmd(t *testing.T, args ...string) string {
+	t.Helper()
+	// Keep the user's external recommenders out of the test.
+	t.Setenv(envir.XDGConfigHome, t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	cmd := planCmd()
+	cmd.SetArgs(args)
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("devbox plan %s error = %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestPlanCmd(t *testing.T) {
+	dir := t.TempDir()
+	fileutil.WriteFilesForTest(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+	})
+
+	got := runPlanCmd(t, dir)
+	for _, want := range []string{
+		"Projects: Go\n",
+		"Packages: go@1.21\n",
+		"Scripts:\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got plan:\n%s\nwant it to contain %q", got, want)
+		}
+	}
+}
+
+func TestPlanCmdJSON(t *testing.T) {
+	dir := t.TempDir()
+	fileutil.WriteFilesForTest(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+	})
+
+	out := runPlanCmd(t, "--json", dir)
+	var got initrec.Recommendation
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("got invalid json from devbox plan --json: %v\n%s", err, out)
+	}
+	if diff := cmp.Diff([]string{"Go"}, got.Projects); diff != "" {
+		t.Errorf("wrong projects (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"go@1.21"}, got.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlanCmdNoProjects(t *testing.T) {
+	if got, want := runPlanCmd(t, t.TempDir()), "No projects detected.\n"; got != want {
+		t.Errorf("got plan %q, want %q", got, want)
+	}
+}
+
+func TestPlanCmdTooManyArgs(t *testing.T) {
+	cmd := planCmd()
+	cmd.SetArgs([]string{t.TempDir(), t.TempDir()})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("got nil error for devbox plan with two directories")
+	}
+}
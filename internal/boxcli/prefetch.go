@@ -0,0 +1,48 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+func prefetchCmd() *cobra.Command {
+	flags := runCmdFlags{}
+	command := &cobra.Command{
+		Use:   "prefetch",
+		Short: "Download everything your project's environment needs without starting a shell",
+		Long: "Download or build every package, the shell environment, and the flake inputs that " +
+			"your project needs, without starting a shell. Use it to warm a CI cache or to make " +
+			"sure a project works offline. Unlike install, it fetches everything even if Devbox " +
+			"thinks the project is up to date.",
+		Args:    cobra.ExactArgs(0),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:           flags.config.path,
+				Environment:   flags.config.environment,
+				PackageGroups: flags.groups.PackageGroups(),
+				Stderr:        cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if err := box.Prefetch(cmd.Context()); err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "Finished fetching the environment.")
+			return nil
+		},
+	}
+
+	flags.config.register(command)
+	flags.groups.register(command)
+	return command
+}
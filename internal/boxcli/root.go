@@ -19,6 +19,7 @@ import (
 	"go.jetpack.io/devbox/internal/cloud/openssh/sshshim"
 	"go.jetpack.io/devbox/internal/cmdutil"
 	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/nix"
 	"go.jetpack.io/devbox/internal/telemetry"
 	"go.jetpack.io/devbox/internal/vercheck"
 )
@@ -57,18 +58,27 @@ func RootCmd() *cobra.Command {
 
 	// Stable commands
 	command.AddCommand(addCmd())
+	command.AddCommand(cacheCmd())
+	command.AddCommand(ciCmd())
+	command.AddCommand(codeCmd())
 	if featureflag.Auth.Enabled() {
 		command.AddCommand(authCmd())
 	}
+	command.AddCommand(configCmd())
 	command.AddCommand(createCmd())
 	command.AddCommand(secretsCmd())
+	command.AddCommand(exportClosureCmd())
 	command.AddCommand(generateCmd())
 	command.AddCommand(globalCmd())
+	command.AddCommand(importClosureCmd())
 	command.AddCommand(infoCmd())
 	command.AddCommand(initCmd())
 	command.AddCommand(installCmd())
 	command.AddCommand(integrateCmd())
 	command.AddCommand(logCmd())
+	command.AddCommand(outdatedCmd())
+	command.AddCommand(planCmd())
+	command.AddCommand(prefetchCmd())
 	command.AddCommand(removeCmd())
 	command.AddCommand(runCmd())
 	command.AddCommand(searchCmd())
@@ -77,7 +87,10 @@ func RootCmd() *cobra.Command {
 	command.AddCommand(shellCmd())
 	// True to always recompute environment if needed.
 	command.AddCommand(shellEnvCmd(lo.ToPtr(true)))
+	command.AddCommand(sizeCmd())
 	command.AddCommand(updateCmd())
+	command.AddCommand(upgradeCmd())
+	command.AddCommand(verifyCmd())
 	command.AddCommand(versionCmd())
 	// Preview commands
 	command.AddCommand(cloudCmd())
@@ -105,6 +118,9 @@ func RootCmd() *cobra.Command {
 
 func Execute(ctx context.Context, args []string) int {
 	defer debug.Recover()
+	// Binary cache credentials are only for the nix commands of this
+	// invocation.
+	defer nix.RemoveNetrc()
 	rootCmd := RootCmd()
 	exe := midcobra.New(rootCmd)
 	exe.AddMiddleware(traceMiddleware)
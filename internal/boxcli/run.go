@@ -22,8 +22,11 @@ import (
 type runCmdFlags struct {
 	envFlag
 	config      configFlags
+	groups      packageGroupFlags
 	pure        bool
 	listScripts bool
+	skipDeps    bool
+	offline     bool
 }
 
 func runCmd() *cobra.Command {
@@ -46,10 +49,15 @@ func runCmd() *cobra.Command {
 
 	flags.envFlag.register(command)
 	flags.config.register(command)
+	flags.groups.register(command)
 	command.Flags().BoolVar(
 		&flags.pure, "pure", false, "if this flag is specified, devbox runs the script in an isolated environment inheriting almost no variables from the current environment. A few variables, in particular HOME, USER and DISPLAY, are retained.")
+	command.Flags().BoolVar(
+		&flags.offline, "offline", false, "don't use the network. Packages that aren't in the Nix store are reported instead of downloaded")
 	command.Flags().BoolVarP(
 		&flags.listScripts, "list", "l", false, "list all scripts defined in devbox.json")
+	command.Flags().BoolVar(
+		&flags.skipDeps, "skip-deps", false, "run the script without first running the scripts in its depends_on")
 
 	command.ValidArgs = listScripts(command, flags)
 
@@ -100,11 +108,14 @@ func runScriptCmd(cmd *cobra.Command, args []string, flags runCmdFlags) error {
 
 	// Check the directory exists.
 	box, err := devbox.Open(&devopt.Opts{
-		Dir:         path,
-		Environment: flags.config.environment,
-		Stderr:      cmd.ErrOrStderr(),
-		Pure:        flags.pure,
-		Env:         env,
+		Dir:            path,
+		Environment:    flags.config.environment,
+		PackageGroups:  flags.groups.PackageGroups(),
+		SkipScriptDeps: flags.skipDeps,
+		Stderr:         cmd.ErrOrStderr(),
+		Pure:           flags.pure,
+		Offline:        flags.offline,
+		Env:            env,
 	})
 	if err != nil {
 		return redact.Errorf("error reading devbox.json: %w", err)
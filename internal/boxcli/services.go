@@ -4,10 +4,16 @@
 package boxcli
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"go.jetpack.io/devbox/internal/devbox"
 	"go.jetpack.io/devbox/internal/devbox/devopt"
+	"go.jetpack.io/devbox/internal/services"
 )
 
 type servicesCmdFlags struct {
@@ -18,13 +24,29 @@ type servicesCmdFlags struct {
 
 type serviceUpFlags struct {
 	background         bool
+	detach             bool
 	processComposeFile string
+	wait               bool
+	timeout            time.Duration
 }
 
 type serviceStopFlags struct {
 	allProjects bool
 }
 
+type serviceWaitFlags struct {
+	timeout time.Duration
+}
+
+type serviceRestartFlags struct {
+	timeout time.Duration
+}
+
+type serviceLogsFlags struct {
+	follow bool
+	tail   int
+}
+
 func (flags *serviceUpFlags) register(cmd *cobra.Command) {
 	cmd.Flags().StringVar(
 		&flags.processComposeFile,
@@ -35,6 +57,13 @@ func (flags *serviceUpFlags) register(cmd *cobra.Command) {
 	)
 	cmd.Flags().BoolVarP(
 		&flags.background, "background", "b", false, "run service in background")
+	cmd.Flags().BoolVarP(
+		&flags.detach, "detach", "d", false,
+		"run services in a background daemon that keeps running after you close your terminal (same as --background)")
+	cmd.Flags().BoolVar(
+		&flags.wait, "wait", false, "run services in the background, and wait until they're ready")
+	cmd.Flags().DurationVar(
+		&flags.timeout, "timeout", 2*time.Minute, "how long --wait waits for services to be ready")
 }
 
 func (flags *serviceStopFlags) register(cmd *cobra.Command) {
@@ -42,10 +71,30 @@ func (flags *serviceStopFlags) register(cmd *cobra.Command) {
 		&flags.allProjects, "all-projects", false, "stop all running services across all your projects.\nThis flag cannot be used simultaneously with the [services] argument")
 }
 
+func (flags *serviceLogsFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(
+		&flags.follow, "follow", "f", false, "keep printing new lines as the services write them")
+	cmd.Flags().IntVarP(
+		&flags.tail, "tail", "n", 100, "number of lines to print from the end of each service's logs")
+}
+
+func (flags *serviceWaitFlags) register(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(
+		&flags.timeout, "timeout", 2*time.Minute, "how long to wait for services to be ready")
+}
+
+func (flags *serviceRestartFlags) register(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(
+		&flags.timeout, "timeout", 10*time.Second, "how long to wait for a service to stop before killing it")
+}
+
 func servicesCmd(persistentPreRunE ...cobraFunc) *cobra.Command {
 	flags := servicesCmdFlags{}
 	serviceUpFlags := serviceUpFlags{}
 	serviceStopFlags := serviceStopFlags{}
+	serviceLogsFlags := serviceLogsFlags{}
+	serviceWaitFlags := serviceWaitFlags{}
+	serviceRestartFlags := serviceRestartFlags{}
 	servicesCommand := &cobra.Command{
 		Use:   "services",
 		Short: "Interact with devbox services.",
@@ -75,6 +124,14 @@ func servicesCmd(persistentPreRunE ...cobraFunc) *cobra.Command {
 		},
 	}
 
+	logsCommand := &cobra.Command{
+		Use:   "logs [service]...",
+		Short: "Print the output of services. If no service is specified, prints the output of all services",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serviceLogs(cmd, args, flags, serviceLogsFlags)
+		},
+	}
+
 	startCommand := &cobra.Command{
 		Use:   "start [service]...",
 		Short: "Start service. If no service is specified, starts all services",
@@ -93,11 +150,46 @@ func servicesCmd(persistentPreRunE ...cobraFunc) *cobra.Command {
 		},
 	}
 
+	resetCommand := &cobra.Command{
+		Use:   "reset <service>",
+		Short: "Delete the data of a plugin's service, such as a database, so it starts over",
+		Long: "Delete the data of a plugin's service, such as a database, so it starts over. " +
+			"Plugins keep the data of their services in .devbox/virtenv/<plugin>, so resetting " +
+			"a service doesn't touch the data of other services or projects. The service must be stopped.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resetService(cmd, args[0], flags)
+		},
+	}
+
 	restartCommand := &cobra.Command{
 		Use:   "restart [service]...",
-		Short: "Restart service. If no service is specified, restarts all services",
+		Short: "Restart service. If no service is specified, restarts all running services",
+		Long: "Restart service. If no service is specified, restarts all running services. " +
+			"Each service is stopped gracefully, with the signal or command in its shutdown config, " +
+			"and killed if it's still running after --timeout.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return restartServices(cmd, args, flags)
+			return restartServices(cmd, args, flags, serviceRestartFlags)
+		},
+	}
+
+	statusCommand := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status, health, pid, uptime, and ports of the services",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serviceStatus(cmd, flags)
+		},
+	}
+
+	attachCommand := &cobra.Command{
+		Use:   "attach <service>",
+		Short: "Stream the output of a service until it stops",
+		Long: "Stream the output of a service until it stops. Press Ctrl-C to detach, " +
+			"which leaves the service running.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return attachService(cmd, args[0], flags)
 		},
 	}
 
@@ -109,6 +201,33 @@ func servicesCmd(persistentPreRunE ...cobraFunc) *cobra.Command {
 		},
 	}
 
+	waitCommand := &cobra.Command{
+		Use:   "wait [service]...",
+		Short: "Wait until services are ready. If no service is specified, waits for all services",
+		Long: "Wait until services are ready. If no service is specified, waits for all services. " +
+			"A service with a ready check is ready when its check passes, and other services are " +
+			"ready once they're running. Use it in scripts that need a service, such as a database, " +
+			"to have finished starting.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return waitForServices(cmd, args, flags, serviceWaitFlags)
+		},
+	}
+
+	var schedule string
+	runScheduledCommand := &cobra.Command{
+		Use:    "run-scheduled --schedule <schedule> -- <command>",
+		Short:  "Run a command on a schedule. devbox services uses it to run scheduled services",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return services.RunScheduled(ctx, cmd.OutOrStdout(), schedule, args[0])
+		},
+	}
+	runScheduledCommand.Flags().StringVar(&schedule, "schedule", "", "cron expression or @every interval")
+	_ = runScheduledCommand.MarkFlagRequired("schedule")
+
 	flags.envFlag.register(servicesCommand)
 	flags.config.registerPersistent(servicesCommand)
 	servicesCommand.PersistentFlags().BoolVar(
@@ -120,11 +239,20 @@ func servicesCmd(persistentPreRunE ...cobraFunc) *cobra.Command {
 	servicesCommand.Flag("run-in-current-shell").Hidden = true
 	serviceUpFlags.register(upCommand)
 	serviceStopFlags.register(stopCommand)
+	serviceLogsFlags.register(logsCommand)
+	serviceWaitFlags.register(waitCommand)
+	serviceRestartFlags.register(restartCommand)
+	servicesCommand.AddCommand(attachCommand)
+	servicesCommand.AddCommand(logsCommand)
 	servicesCommand.AddCommand(lsCommand)
 	servicesCommand.AddCommand(upCommand)
+	servicesCommand.AddCommand(resetCommand)
 	servicesCommand.AddCommand(restartCommand)
+	servicesCommand.AddCommand(runScheduledCommand)
 	servicesCommand.AddCommand(startCommand)
+	servicesCommand.AddCommand(statusCommand)
 	servicesCommand.AddCommand(stopCommand)
+	servicesCommand.AddCommand(waitCommand)
 	return servicesCommand
 }
 
@@ -141,6 +269,23 @@ func listServices(cmd *cobra.Command, flags servicesCmdFlags) error {
 	return box.ListServices(cmd.Context(), flags.runInCurrentShell)
 }
 
+func serviceLogs(
+	cmd *cobra.Command,
+	services []string,
+	servicesFlags servicesCmdFlags,
+	flags serviceLogsFlags,
+) error {
+	box, err := devbox.Open(&devopt.Opts{
+		Dir:         servicesFlags.config.path,
+		Environment: servicesFlags.config.environment,
+		Stderr:      cmd.ErrOrStderr(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return box.ServiceLogs(cmd.Context(), cmd.OutOrStdout(), flags.tail, flags.follow, services...)
+}
+
 func startServices(cmd *cobra.Command, services []string, flags servicesCmdFlags) error {
 	env, err := flags.Env(flags.config.path)
 	if err != nil {
@@ -185,10 +330,23 @@ func stopServices(
 		cmd.Context(), servicesFlags.runInCurrentShell, flags.allProjects, services...)
 }
 
+func resetService(cmd *cobra.Command, service string, flags servicesCmdFlags) error {
+	box, err := devbox.Open(&devopt.Opts{
+		Dir:         flags.config.path,
+		Environment: flags.config.environment,
+		Stderr:      cmd.ErrOrStderr(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return box.ResetService(cmd.Context(), service)
+}
+
 func restartServices(
 	cmd *cobra.Command,
 	services []string,
 	flags servicesCmdFlags,
+	restartFlags serviceRestartFlags,
 ) error {
 	env, err := flags.Env(flags.config.path)
 	if err != nil {
@@ -204,7 +362,31 @@ func restartServices(
 		return errors.WithStack(err)
 	}
 
-	return box.RestartServices(cmd.Context(), flags.runInCurrentShell, services...)
+	return box.RestartServices(cmd.Context(), flags.runInCurrentShell, restartFlags.timeout, services...)
+}
+
+func serviceStatus(cmd *cobra.Command, flags servicesCmdFlags) error {
+	box, err := devbox.Open(&devopt.Opts{
+		Dir:         flags.config.path,
+		Environment: flags.config.environment,
+		Stderr:      cmd.ErrOrStderr(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return box.ServiceStatus(cmd.Context(), cmd.OutOrStdout())
+}
+
+func attachService(cmd *cobra.Command, service string, flags servicesCmdFlags) error {
+	box, err := devbox.Open(&devopt.Opts{
+		Dir:         flags.config.path,
+		Environment: flags.config.environment,
+		Stderr:      cmd.ErrOrStderr(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return box.AttachService(cmd.Context(), cmd.OutOrStdout(), service)
 }
 
 func startProcessManager(
@@ -228,11 +410,32 @@ func startProcessManager(
 		return errors.WithStack(err)
 	}
 
-	return box.StartProcessManager(
+	err = box.StartProcessManager(
 		cmd.Context(),
 		servicesFlags.runInCurrentShell,
 		args,
-		flags.background,
+		flags.background || flags.detach || flags.wait,
 		flags.processComposeFile,
 	)
+	if err != nil || !flags.wait {
+		return err
+	}
+	return box.WaitForServices(cmd.Context(), flags.timeout, args...)
+}
+
+func waitForServices(
+	cmd *cobra.Command,
+	services []string,
+	servicesFlags servicesCmdFlags,
+	flags serviceWaitFlags,
+) error {
+	box, err := devbox.Open(&devopt.Opts{
+		Dir:         servicesFlags.config.path,
+		Environment: servicesFlags.config.environment,
+		Stderr:      cmd.ErrOrStderr(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return box.WaitForServices(cmd.Context(), flags.timeout, services...)
 }
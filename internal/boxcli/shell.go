@@ -5,6 +5,8 @@ package boxcli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -18,8 +20,12 @@ import (
 type shellCmdFlags struct {
 	envFlag
 	config   configFlags
+	groups   packageGroupFlags
 	printEnv bool
 	pure     bool
+	offline  bool
+	tmux     bool
+	isolate  string
 }
 
 func shellCmd() *cobra.Command {
@@ -41,24 +47,39 @@ func shellCmd() *cobra.Command {
 		&flags.printEnv, "print-env", false, "print script to setup shell environment")
 	command.Flags().BoolVar(
 		&flags.pure, "pure", false, "if this flag is specified, devbox creates an isolated shell inheriting almost no variables from the current environment. A few variables, in particular HOME, USER and DISPLAY, are retained.")
+	command.Flags().BoolVar(
+		&flags.offline, "offline", false, "don't use the network. Packages that aren't in the Nix store are reported instead of downloaded")
+	command.Flags().BoolVar(
+		&flags.tmux, "tmux", false, "attach to the project's tmux session, with a window for each script and the services")
+	command.Flags().StringVar(
+		&flags.isolate, "isolate", "", "start the shell in isolation. \"container\" starts it in a Docker or Podman container")
 
 	flags.config.register(command)
+	flags.groups.register(command)
 	flags.envFlag.register(command)
 	return command
 }
 
 func runShellCmd(cmd *cobra.Command, flags shellCmdFlags) error {
+	if flags.isolate != "" && flags.isolate != "container" {
+		return usererr.New("--isolate can only be \"container\", not %q.", flags.isolate)
+	}
+	if flags.isolate != "" && (flags.tmux || flags.printEnv) {
+		return usererr.New("--isolate can't be used with --tmux or --print-env.")
+	}
 	env, err := flags.Env(flags.config.path)
 	if err != nil {
 		return err
 	}
 	// Check the directory exists.
 	box, err := devbox.Open(&devopt.Opts{
-		Dir:         flags.config.path,
-		Env:         env,
-		Environment: flags.config.environment,
-		Pure:        flags.pure,
-		Stderr:      cmd.ErrOrStderr(),
+		Dir:           flags.config.path,
+		Env:           env,
+		Environment:   flags.config.environment,
+		PackageGroups: flags.groups.PackageGroups(),
+		Pure:          flags.pure,
+		Offline:       flags.offline,
+		Stderr:        cmd.ErrOrStderr(),
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -80,9 +101,35 @@ func runShellCmd(cmd *cobra.Command, flags shellCmdFlags) error {
 		return shellInceptionErrorMsg("devbox shell")
 	}
 
+	if flags.isolate == "container" {
+		return box.ContainerShell(cmd.Context())
+	}
+	if flags.tmux {
+		shellCmd, err := tmuxShellCmd()
+		if err != nil {
+			return err
+		}
+		return box.TmuxShell(cmd.Context(), shellCmd)
+	}
 	return box.Shell(cmd.Context())
 }
 
+// tmuxShellCmd returns the command that starts a devbox shell in each window
+// of the tmux session, which is this command without --tmux.
+func tmuxShellCmd() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	shellCmd := []string{exe}
+	for _, arg := range os.Args[1:] {
+		if arg != "--tmux" && !strings.HasPrefix(arg, "--tmux=") {
+			shellCmd = append(shellCmd, arg)
+		}
+	}
+	return shellCmd, nil
+}
+
 func shellInceptionErrorMsg(cmdPath string) error {
 	return usererr.New("You are already in an active %[1]s.\nRun `exit` before calling `%[1]s` again."+
 		" Shell inception is not supported.", cmdPath)
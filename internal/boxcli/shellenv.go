@@ -15,7 +15,9 @@ import (
 
 type shellEnvCmdFlags struct {
 	envFlag
+	cache             bool
 	config            configFlags
+	groups            packageGroupFlags
 	install           bool
 	noRefreshAlias    bool
 	preservePathStack bool
@@ -47,6 +49,10 @@ func shellEnvCmd(recomputeEnvIfNeeded *bool) *cobra.Command {
 		&flags.runInitHook, "init-hook", false, "runs init hook after exporting shell environment")
 	command.Flags().BoolVar(
 		&flags.install, "install", false, "install packages before exporting shell environment")
+	command.Flags().BoolVar(
+		&flags.cache, "cache", false,
+		"reuse the environment from the last run with --cache if devbox.json, devbox.lock and "+
+			"the variables it changes are the same. Useful with direnv")
 
 	command.Flags().BoolVar(
 		&flags.pure, "pure", false, "if this flag is specified, devbox creates an isolated environment inheriting almost no variables from the current environment. A few variables, in particular HOME, USER and DISPLAY, are retained.")
@@ -62,6 +68,7 @@ func shellEnvCmd(recomputeEnvIfNeeded *bool) *cobra.Command {
 	_ = command.Flags().MarkHidden("no-refresh-alias")
 
 	flags.config.register(command)
+	flags.groups.register(command)
 	flags.envFlag.register(command)
 
 	return command
@@ -79,6 +86,7 @@ func shellEnvFunc(
 	box, err := devbox.Open(&devopt.Opts{
 		Dir:               flags.config.path,
 		Environment:       flags.config.environment,
+		PackageGroups:     flags.groups.PackageGroups(),
 		Stderr:            cmd.ErrOrStderr(),
 		PreservePathStack: flags.preservePathStack,
 		Pure:              flags.pure,
@@ -88,17 +96,25 @@ func shellEnvFunc(
 		return "", err
 	}
 
+	opts := devopt.EnvExportsOpts{
+		Cache:                    flags.cache,
+		DontRecomputeEnvironment: !recomputeEnvIfNeeded,
+		NoRefreshAlias:           flags.noRefreshAlias,
+		RunHooks:                 flags.runInitHook,
+	}
+	if flags.cache {
+		if envStr, ok := box.CachedEnvExports(opts); ok {
+			return envStr, nil
+		}
+	}
+
 	if flags.install {
 		if err := box.Install(cmd.Context()); err != nil {
 			return "", err
 		}
 	}
 
-	envStr, err := box.EnvExports(cmd.Context(), devopt.EnvExportsOpts{
-		DontRecomputeEnvironment: !recomputeEnvIfNeeded,
-		NoRefreshAlias:           flags.noRefreshAlias,
-		RunHooks:                 flags.runInitHook,
-	})
+	envStr, err := box.EnvExports(cmd.Context(), opts)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,46 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+type sizeCmdFlags struct {
+	config configFlags
+	top    int
+}
+
+func sizeCmd() *cobra.Command {
+	flags := sizeCmdFlags{}
+	command := &cobra.Command{
+		Use:   "size",
+		Short: "Show how much disk space your project's packages use",
+		Long: "Show the closure size of each package in your project, which includes " +
+			"everything the package needs at runtime, the total size of the environment, " +
+			"and the store paths that take up the most space.",
+		Args:    cobra.ExactArgs(0),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:         flags.config.path,
+				Environment: flags.config.environment,
+				Stderr:      cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return box.Size(cmd.Context(), cmd.OutOrStdout(), flags.top)
+		},
+	}
+
+	flags.config.register(command)
+	command.Flags().IntVar(
+		&flags.top, "top", 10, "number of the largest store paths to show, or 0 to hide them")
+	return command
+}
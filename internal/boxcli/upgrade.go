@@ -0,0 +1,39 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+func upgradeCmd() *cobra.Command {
+	flags := configFlags{}
+	command := &cobra.Command{
+		Use:   "upgrade <pkg>...",
+		Short: "Upgrade specific packages and leave everything else locked",
+		Long: "Upgrade the given packages to the newest version that devbox.json allows, " +
+			"such as the newest 1.21.x release for go@1.21, and show their versions before " +
+			"and after. Unlike update, upgrade doesn't change any other package in devbox.lock.",
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:         flags.path,
+				Environment: flags.environment,
+				Stderr:      cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return box.Upgrade(cmd.Context(), args)
+		},
+	}
+
+	flags.register(command)
+	return command
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package boxcli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"go.jetpack.io/devbox/internal/devbox"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+type verifyCmdFlags struct {
+	config  configFlags
+	groups  packageGroupFlags
+	rebuild bool
+}
+
+func verifyCmd() *cobra.Command {
+	flags := verifyCmdFlags{}
+	command := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that your packages match devbox.lock",
+		Long: "Evaluate every package in devbox.lock again, without using Nix's caches, and " +
+			"check that the Nixpkgs sources and store paths match the ones in devbox.lock. " +
+			"With --rebuild, also build each package from source again and check that the " +
+			"result is bit-for-bit identical. Exits with an error if any package doesn't match.",
+		Args:    cobra.ExactArgs(0),
+		PreRunE: ensureNixInstalled,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			box, err := devbox.Open(&devopt.Opts{
+				Dir:           flags.config.path,
+				Environment:   flags.config.environment,
+				PackageGroups: flags.groups.PackageGroups(),
+				Stderr:        cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return box.Verify(cmd.Context(), flags.rebuild)
+		},
+	}
+
+	flags.config.register(command)
+	flags.groups.register(command)
+	command.Flags().BoolVar(
+		&flags.rebuild, "rebuild", false,
+		"build each package from source again and check that the result is identical")
+	return command
+}
@@ -87,7 +87,7 @@ func ensureVMForUser(vmHostname string, w io.Writer, username string, sshCmd *op
 	return vmHostname, nil
 }
 
-func Shell(ctx context.Context, w io.Writer, projectDir, githubUsername string) error {
+func Shell(ctx context.Context, w io.Writer, projectDir, configName, githubUsername string) error {
 	color.New(color.FgMagenta, color.Bold).Fprint(w, "Devbox Cloud\n")
 	fmt.Fprint(w, "Remote development environments powered by Nix\n\n")
 	fmt.Fprint(w, "This is an open developer preview and may have some rough edges. Please report any issues to https://github.com/jetpack-io/devbox/issues\n\n")
@@ -98,7 +98,7 @@ func Shell(ctx context.Context, w io.Writer, projectDir, githubUsername string)
 	}
 	// file sync and shell
 	color.New(color.FgGreen).Fprintln(w, "Starting file syncing...")
-	err = syncFiles(username, vmHostname, projectDir)
+	err = syncFiles(username, vmHostname, projectDir, configName)
 	if err != nil {
 		color.New(color.FgRed).Fprintln(w, "Starting file syncing [FAILED]")
 		return err
@@ -294,7 +294,7 @@ func getVirtualMachine(sshCmd *openssh.Cmd) (vmUser, vmHost, region string, err
 	return resp.VMUsername, resp.VMHost, resp.VMRegion, nil
 }
 
-func syncFiles(username, hostname, projectDir string) error {
+func syncFiles(username, hostname, projectDir, configName string) error {
 	relProjectPathInVM, err := relativeProjectPathInVM(projectDir)
 	if err != nil {
 		return err
@@ -302,7 +302,7 @@ func syncFiles(username, hostname, projectDir string) error {
 	absPathInVM := absoluteProjectPathInVM(username, relProjectPathInVM)
 	debug.Log("absPathInVM: %s", absPathInVM)
 
-	err = copyConfigFileToVM(hostname, username, projectDir, absPathInVM)
+	err = copyConfigFileToVM(hostname, username, projectDir, configName, absPathInVM)
 	if err != nil {
 		return err
 	}
@@ -413,7 +413,7 @@ func getSyncStatus(mutagenSessionName string) (string, error) {
 	return sessions[0].Status, nil
 }
 
-func copyConfigFileToVM(hostname, username, projectDir, pathInVM string) error {
+func copyConfigFileToVM(hostname, username, projectDir, configName, pathInVM string) error {
 	// Ensure the devbox-project's directory exists in the VM
 	mkdirCmd := openssh.Command(username, hostname)
 	// This is the first command we run on the VM. Sometimes is takes fly.io a few seconds
@@ -428,11 +428,11 @@ func copyConfigFileToVM(hostname, username, projectDir, pathInVM string) error {
 
 	// Copy the config file to the devbox-project directory in the VM
 	destServer := fmt.Sprintf("%s@%s", username, hostname)
-	configFilePath := filepath.Join(projectDir, "devbox.json")
+	configFilePath := filepath.Join(projectDir, configName)
 	destPath := fmt.Sprintf("%s:%s", destServer, pathInVM)
 	cmd := exec.Command("scp", configFilePath, destPath)
 	err = cmd.Run()
-	debug.Log("scp devbox.json command: %s with error: %s", cmd, err)
+	debug.Log("scp %s command: %s with error: %s", configName, cmd, err)
 	return errors.WithStack(err)
 }
 
@@ -106,10 +106,10 @@ func Resume(envVars map[string]string, names ...string) error {
 	return execMutagenEnv(args, envVars)
 }
 
-func Flush(names ...string) error {
+func Flush(envVars map[string]string, names ...string) error {
 	args := []string{"sync", "flush"}
 	args = append(args, names...)
-	return execMutagen(args)
+	return execMutagenEnv(args, envVars)
 }
 
 func Reset(envVars map[string]string, names ...string) error {
@@ -14,6 +14,10 @@ import (
 const (
 	// relative to user home i.e. ~
 	dataDirPath = ".config/devbox/mutagen"
+
+	// remoteDataDirPath is the data directory for syncing to machines that
+	// aren't Devbox Cloud VMs, relative to user home.
+	remoteDataDirPath = ".config/devbox/mutagen-remote"
 )
 
 // TerminateSessionsForMachine is a devbox-specific API that calls the generic mutagen terminate API.
@@ -46,7 +50,7 @@ func DefaultEnv() (map[string]string, error) {
 		return nil, err
 	}
 
-	mutagenDir, err := createAndGetDataDir()
+	mutagenDir, err := createAndGetDataDir(dataDirPath)
 	if err != nil {
 		return nil, err
 	}
@@ -57,13 +61,24 @@ func DefaultEnv() (map[string]string, error) {
 	}, nil
 }
 
+// RemoteEnv is like DefaultEnv, but for syncing to a machine that the user
+// manages. It uses its own mutagen daemon, which runs the user's ssh with
+// their ssh config instead of the devbox shim.
+func RemoteEnv() (map[string]string, error) {
+	mutagenDir, err := createAndGetDataDir(remoteDataDirPath)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"MUTAGEN_DATA_DIRECTORY": mutagenDir}, nil
+}
+
 // createAndGetDataDir prepares the data directory for devbox's mutagen instance
-func createAndGetDataDir() (string, error) {
+func createAndGetDataDir(dir string) (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
 
-	path := filepath.Join(home, dataDirPath)
+	path := filepath.Join(home, dir)
 	return path, errors.WithStack(os.MkdirAll(path, 0o700))
 }
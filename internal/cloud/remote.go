@@ -0,0 +1,208 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/cloud/mutagen"
+	"go.jetpack.io/devbox/internal/cloud/mutagenbox"
+	"go.jetpack.io/devbox/internal/debug"
+)
+
+// remoteProjectsDir is where projects are synced to on a remote machine,
+// relative to the remote user's home directory.
+const remoteProjectsDir = "devbox-projects"
+
+// RemoteOpts are the options of a devbox shell on a machine that the user
+// manages, instead of a Devbox Cloud VM.
+type RemoteOpts struct {
+	// Host is an SSH destination, such as "me@build-box", "build-box:2222"
+	// or a Host in ~/.ssh/config.
+	Host string
+
+	// Forwards are the ports to forward from this machine to the remote
+	// one, such as "8080", or "3000:8080" to forward local port 3000 to
+	// remote port 8080.
+	Forwards []string
+
+	// ForwardAgent forwards the SSH agent to the remote shell, so git can
+	// push over SSH from it.
+	ForwardAgent bool
+}
+
+// sshDestination is a remote machine that ssh and mutagen can connect to.
+type sshDestination struct {
+	// host is [user@]hostname.
+	host string
+	port string
+}
+
+// RemoteShell syncs a project to a remote Linux machine, installs devbox on
+// it if it's missing, and starts a devbox shell there over SSH. The project
+// keeps syncing both ways until the shell exits.
+func RemoteShell(ctx context.Context, w io.Writer, projectDir string, opts RemoteOpts) error {
+	if err := ensureProjectDirIsNotSensitive(projectDir); err != nil {
+		return err
+	}
+	dest, err := parseSSHDestination(opts.Host)
+	if err != nil {
+		return err
+	}
+	forwardArgs, err := sshForwardArgs(opts.Forwards)
+	if err != nil {
+		return err
+	}
+	relPath, err := relativeProjectPathInVM(projectDir)
+	if err != nil {
+		return err
+	}
+	remoteDir := path.Join(remoteProjectsDir, filepath.ToSlash(relPath))
+
+	// The first connection is interactive, so ssh can ask to trust the
+	// host key and the installer can ask for a password to use sudo.
+	color.New(color.FgGreen).Fprintf(w, "Setting up devbox on %s...\n", dest.host)
+	if err := dest.run(ctx, []string{"-t"}, remoteSetupScript(remoteDir)); err != nil {
+		return usererr.WithUserMessage(err, "Devbox couldn't set up %s. Check that you can connect to it with ssh.", dest.host)
+	}
+
+	color.New(color.FgGreen).Fprintln(w, "Starting file syncing...")
+	env, sessionName, err := syncToRemote(dest, projectDir, remoteDir)
+	if err != nil {
+		color.New(color.FgRed).Fprintln(w, "Starting file syncing [FAILED]")
+		return err
+	}
+	defer func() {
+		// Sync the changes from the remote machine back before stopping.
+		if err := mutagen.Flush(env, sessionName); err != nil {
+			debug.Log("error flushing sync session %s: %v", sessionName, err)
+		}
+		if err := mutagen.Terminate(env, nil, sessionName); err != nil {
+			debug.Log("error terminating sync session %s: %v", sessionName, err)
+		}
+	}()
+	// Wait for the initial sync, so the shell starts with the project.
+	if err := mutagen.Flush(env, sessionName); err != nil {
+		return err
+	}
+	color.New(color.FgGreen).Fprintln(w, "File syncing started")
+
+	args := append([]string{"-t"}, forwardArgs...)
+	if opts.ForwardAgent {
+		args = append(args, "-A")
+	}
+	return dest.run(ctx, args, fmt.Sprintf("bash -l -c %s", shellescape.Quote("cd "+shellescape.Quote(remoteDir)+" && exec devbox shell")))
+}
+
+// remoteSetupScript creates the project's directory and installs devbox if
+// it isn't installed yet.
+func remoteSetupScript(remoteDir string) string {
+	install := "curl -fsSL https://get.jetpack.io/devbox | bash -s -- -f"
+	script := fmt.Sprintf("mkdir -p %s && { command -v devbox >/dev/null 2>&1 || { %s; }; }", shellescape.Quote(remoteDir), install)
+	return "bash -l -c " + shellescape.Quote(script)
+}
+
+func syncToRemote(dest *sshDestination, projectDir, remoteDir string) (env map[string]string, sessionName string, err error) {
+	env, err = mutagenbox.RemoteEnv()
+	if err != nil {
+		return nil, "", err
+	}
+	ignorePaths, err := gitIgnorePaths(projectDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sessionName = mutagen.SanitizeSessionName(fmt.Sprintf("devbox-remote-%s-%s", dest.host, hyphenatePath(remoteDir)))
+	betaAddress := dest.host
+	if dest.port != "" {
+		betaAddress += ":" + dest.port
+	}
+	_, err = mutagen.Sync(&mutagen.SessionSpec{
+		Name:        sessionName,
+		AlphaPath:   projectDir,
+		BetaAddress: betaAddress,
+		// The path is relative to the remote user's home directory, and
+		// only has the project's files, so that two-way sync doesn't
+		// bring other files back to the local project.
+		BetaPath: remoteDir,
+		EnvVars:  env,
+		Ignore: mutagen.SessionIgnore{
+			VCS:   true,
+			Paths: ignorePaths,
+		},
+		SyncMode: "two-way-resolved",
+		Labels:   map[string]string{"devbox-remote": mutagen.SanitizeSessionName(dest.host)},
+	})
+	return env, sessionName, err
+}
+
+func parseSSHDestination(host string) (*sshDestination, error) {
+	if host == "" || strings.ContainsAny(host, " \t/") {
+		return nil, usererr.New("%q isn't an SSH destination, such as me@build-box or build-box:2222.", host)
+	}
+	dest := &sshDestination{host: host}
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, usererr.New("The port of the SSH destination %q isn't a number.", host)
+		}
+		dest = &sshDestination{host: h, port: port}
+	}
+	// ssh would read a host such as -oProxyCommand=... as an option.
+	if strings.HasPrefix(dest.host, "-") {
+		return nil, usererr.New("%q isn't an SSH destination. Destinations can't start with \"-\".", host)
+	}
+	return dest, nil
+}
+
+// sshForwardArgs returns the ssh flags that forward local ports to the
+// remote machine.
+func sshForwardArgs(forwards []string) ([]string, error) {
+	args := []string{}
+	for _, forward := range forwards {
+		local, remote, found := strings.Cut(forward, ":")
+		if !found {
+			remote = local
+		}
+		for _, port := range []string{local, remote} {
+			if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+				return nil, usererr.New("The port forward %q needs to be a port, such as 8080, or <local-port>:<remote-port>.", forward)
+			}
+		}
+		args = append(args, "-L", fmt.Sprintf("%s:localhost:%s", local, remote))
+	}
+	return args, nil
+}
+
+func (d *sshDestination) run(ctx context.Context, sshArgs []string, remoteCmd string) error {
+	cmd := exec.CommandContext(ctx, "ssh", d.args(sshArgs, remoteCmd)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	debug.Log("running %s", cmd)
+	return errors.WithStack(cmd.Run())
+}
+
+// args returns the arguments of an ssh command that runs remoteCmd on the
+// destination. The "--" ends ssh's options so the host is never read as one.
+func (d *sshDestination) args(sshArgs []string, remoteCmd string) []string {
+	args := append([]string{}, sshArgs...)
+	if d.port != "" {
+		args = append(args, "-p", d.port)
+	}
+	return append(args, "--", d.host, remoteCmd)
+}
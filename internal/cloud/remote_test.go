@@ -0,0 +1,53 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package cloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSSHDestination(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want *sshDestination
+	}{
+		{"build-box", &sshDestination{host: "build-box"}},
+		{"me@build-box", &sshDestination{host: "me@build-box"}},
+		{"me@build-box:2222", &sshDestination{host: "me@build-box", port: "2222"}},
+		{"[::1]:2222", &sshDestination{host: "::1", port: "2222"}},
+	}
+	for _, tc := range testCases {
+		got, err := parseSSHDestination(tc.in)
+		if assert.NoError(t, err, tc.in) {
+			assert.Equal(t, tc.want, got, tc.in)
+		}
+	}
+
+	for _, in := range []string{
+		"", "build box", "build-box:ssh", "build-box:99999",
+		"-oProxyCommand=touch$IFS.pwned", "[-oProxyCommand=x]:22",
+	} {
+		_, err := parseSSHDestination(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestSSHDestinationArgs(t *testing.T) {
+	dest := &sshDestination{host: "me@build-box", port: "2222"}
+	got := dest.args([]string{"-t"}, "devbox shell")
+	assert.Equal(t, []string{"-t", "-p", "2222", "--", "me@build-box", "devbox shell"}, got)
+}
+
+func TestSSHForwardArgs(t *testing.T) {
+	got, err := sshForwardArgs([]string{"8080", "3000:5432"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-L", "8080:localhost:8080", "-L", "3000:localhost:5432"}, got)
+
+	for _, in := range []string{"", "http", "3000:", "1:2:3"} {
+		_, err := sshForwardArgs([]string{in})
+		assert.Error(t, err, in)
+	}
+}
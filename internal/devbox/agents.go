@@ -0,0 +1,96 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+// agentEnvVars are the variables that SSH and GPG use to find their agents.
+// forward_agents keeps them in pure shells.
+var agentEnvVars = []string{
+	"SSH_AUTH_SOCK",
+	"SSH_AGENT_PID",
+	"GPG_AGENT_INFO",
+	"GPG_TTY",
+	"GNUPGHOME",
+}
+
+const (
+	// containerHome is the home directory of the devbox user in the
+	// generated Dockerfile's image.
+	containerHome = "/home/devbox"
+
+	// containerSSHAuthSock is where the SSH agent's socket is mounted in a
+	// container shell.
+	containerSSHAuthSock = "/run/ssh-agent.sock"
+
+	// dockerDesktopSSHAuthSock is the socket that Docker Desktop forwards
+	// the macOS SSH agent to, since macOS sockets can't be mounted.
+	dockerDesktopSSHAuthSock = "/run/host-services/ssh-auth.sock"
+)
+
+// containerAgentArgs returns the docker run flags that forward this
+// machine's SSH and GPG agents to a container shell. uid and gid are the IDs
+// of the devbox user in the container's image.
+func containerAgentArgs(uid, gid int) []string {
+	gpgSocket, gpgHome := "", ""
+	if gpgconf, err := exec.LookPath("gpgconf"); err == nil {
+		gpgSocket = gpgconfDir(gpgconf, "agent-extra-socket")
+		gpgHome = gpgconfDir(gpgconf, "homedir")
+	}
+	return agentArgs(runtime.GOOS, os.Getenv("SSH_AUTH_SOCK"), gpgSocket, gpgHome, uid, gid)
+}
+
+func agentArgs(goos, sshAuthSock, gpgSocket, gpgHome string, uid, gid int) []string {
+	args := []string{}
+	switch {
+	case sshAuthSock == "":
+	case goos == "darwin":
+		args = append(args,
+			"-v", dockerDesktopSSHAuthSock+":"+dockerDesktopSSHAuthSock,
+			"-e", "SSH_AUTH_SOCK="+dockerDesktopSSHAuthSock)
+	default:
+		args = append(args,
+			"-v", sshAuthSock+":"+containerSSHAuthSock,
+			"-e", "SSH_AUTH_SOCK="+containerSSHAuthSock)
+	}
+
+	// GPG in the container uses the agent's extra socket, which is the
+	// one meant for forwarding, and reads the public keys from this
+	// machine. Its home directory is a tmpfs that the devbox user owns,
+	// since gpg writes lock files next to the keyring.
+	if gpgSocket == "" || gpgHome == "" || goos == "darwin" {
+		return args
+	}
+	gnupg := containerHome + "/.gnupg"
+	args = append(args,
+		"--tmpfs", fmt.Sprintf("%s:uid=%d,gid=%d,mode=0700", gnupg, uid, gid),
+		"-v", gpgSocket+":"+gnupg+"/S.gpg-agent")
+	for _, file := range []string{"pubring.kbx", "trustdb.gpg"} {
+		if path := filepath.Join(gpgHome, file); fileutil.Exists(path) {
+			args = append(args, "-v", path+":"+gnupg+"/"+file+":ro")
+		}
+	}
+	return args
+}
+
+func gpgconfDir(gpgconf, name string) string {
+	out, err := exec.Command(gpgconf, "--list-dirs", name).Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(out))
+	if !fileutil.Exists(path) {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+func TestAgentArgs(t *testing.T) {
+	gpgHome := t.TempDir()
+	if err := os.WriteFile(filepath.Join(gpgHome, "pubring.kbx"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := agentArgs("linux", "/tmp/ssh-abc/agent.1", "/run/user/1000/gnupg/S.gpg-agent.extra", gpgHome, 1000, 1000)
+	want := []string{
+		"-v", "/tmp/ssh-abc/agent.1:/run/ssh-agent.sock",
+		"-e", "SSH_AUTH_SOCK=/run/ssh-agent.sock",
+		"--tmpfs", "/home/devbox/.gnupg:uid=1000,gid=1000,mode=0700",
+		"-v", "/run/user/1000/gnupg/S.gpg-agent.extra:/home/devbox/.gnupg/S.gpg-agent",
+		"-v", filepath.Join(gpgHome, "pubring.kbx") + ":/home/devbox/.gnupg/pubring.kbx:ro",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got agent args on Linux %q, want %q", got, want)
+	}
+
+	// The devbox user gets the host's user ID, so the keyring's directory
+	// has to belong to it.
+	got = agentArgs("linux", "", "/run/user/1234/gnupg/S.gpg-agent.extra", gpgHome, 1234, 1000)
+	if !slices.Contains(got, "/home/devbox/.gnupg:uid=1234,gid=1000,mode=0700") {
+		t.Errorf("got agent args for user 1234 %q, want a tmpfs that the user owns", got)
+	}
+
+	got = agentArgs("darwin", "/private/tmp/com.apple.launchd.abc/Listeners", "/Users/me/.gnupg/S.gpg-agent.extra", gpgHome, 501, 1000)
+	want = []string{
+		"-v", "/run/host-services/ssh-auth.sock:/run/host-services/ssh-auth.sock",
+		"-e", "SSH_AUTH_SOCK=/run/host-services/ssh-auth.sock",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got agent args on macOS %q, want %q", got, want)
+	}
+
+	if got := agentArgs("linux", "", "", "", 1000, 1000); len(got) != 0 {
+		t.Errorf("got agent args %q without agents, want none", got)
+	}
+}
+
+func TestPureShellForwardsAgents(t *testing.T) {
+	// Pure shells need nix in PATH.
+	hostEnv := []string{"HOME=/home/me", "PATH=/nix/var/nix/profiles/default/bin", "SSH_AUTH_SOCK=/tmp/agent.sock", "GPG_TTY=/dev/pts/1", "AWS_PROFILE=prod"}
+	for _, forward := range []bool{false, true} {
+		d := &Devbox{projectDir: t.TempDir(), pure: true, cfg: &devconfig.Config{ForwardAgents: forward}}
+		env, err := d.parseEnvAndExcludeSpecialCases(hostEnv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := env["SSH_AUTH_SOCK"] == "/tmp/agent.sock" && env["GPG_TTY"] == "/dev/pts/1"; got != forward {
+			t.Errorf("got agent variables kept = %v with forward_agents = %v, want %v", got, forward, forward)
+		}
+		if _, ok := env["AWS_PROFILE"]; ok {
+			t.Errorf("got AWS_PROFILE in a pure shell with forward_agents = %v", forward)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/keychain"
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+// cacheCredsService is the keychain service that binary cache credentials
+// are stored under. The account is the cache's host.
+const cacheCredsService = "devbox-cache"
+
+// cacheCreds are the credentials for a binary cache. A Cachix auth token is
+// a password without a login.
+type cacheCreds struct {
+	Login    string `json:"login,omitempty"`
+	Password string `json:"password"`
+}
+
+// keychainGet is a var so tests can replace the OS keychain.
+var keychainGet = keychain.Get
+
+// CacheLogin stores the credentials for a private binary cache in the OS
+// keychain. Devbox gives them to nix whenever the cache is one of the
+// project's nix.substituters.
+func CacheLogin(cacheURL, login, password string) error {
+	host, err := cacheHost(cacheURL)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return usererr.New("The password or token for %s is empty.", host)
+	}
+	data, err := json.Marshal(cacheCreds{Login: login, Password: password})
+	if err != nil {
+		return err
+	}
+	return keychain.Set(cacheCredsService, host, string(data))
+}
+
+// CacheLogout removes the credentials for a binary cache from the OS keychain.
+func CacheLogout(cacheURL string) error {
+	host, err := cacheHost(cacheURL)
+	if err != nil {
+		return err
+	}
+	return keychain.Delete(cacheCredsService, host)
+}
+
+func cacheHost(cacheURL string) (string, error) {
+	u, err := url.Parse(cacheURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", usererr.New(
+			"%q isn't an http(s) URL. Devbox can only store credentials for http(s) binary caches. "+
+				"For an S3 cache, add ?profile=<name> to the substituter to use an AWS profile.",
+			cacheURL,
+		)
+	}
+	return u.Host, nil
+}
+
+// setCacheCredentials writes the keychain credentials of the substituters
+// that have them to a temporary netrc file, and tells nix to use it. The file
+// is only readable by the user, and is removed when the devbox command exits.
+func setCacheCredentials(substituters []string) error {
+	return nix.SetNetrc(cacheNetrc(substituters))
+}
+
+// cacheNetrc returns a netrc file with the credentials of the substituters
+// that have them. Substituters without credentials, or whose credentials
+// can't be read, are left out.
+func cacheNetrc(substituters []string) []byte {
+	var buf bytes.Buffer
+	for _, s := range substituters {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		secret, err := keychainGet(cacheCredsService, u.Host)
+		if err != nil {
+			if !errors.Is(err, keychain.ErrNotFound) {
+				debug.Log("couldn't read credentials for %s: %v", u.Host, err)
+			}
+			continue
+		}
+		var creds cacheCreds
+		if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+			debug.Log("couldn't parse credentials for %s: %v", u.Host, err)
+			continue
+		}
+		fmt.Fprintf(&buf, "machine %s", u.Host)
+		if creds.Login != "" {
+			fmt.Fprintf(&buf, " login %s", creds.Login)
+		}
+		fmt.Fprintf(&buf, " password %s\n", creds.Password)
+	}
+	return buf.Bytes()
+}
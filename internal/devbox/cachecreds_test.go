@@ -0,0 +1,41 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"testing"
+
+	"go.jetpack.io/devbox/internal/keychain"
+)
+
+func TestCacheNetrc(t *testing.T) {
+	secrets := map[string]string{
+		"team.cachix.org":    `{"password": "token"}`,
+		"cache.example.com":  `{"login": "ci", "password": "hunter2"}`,
+		"broken.example.com": `not json`,
+	}
+	original := keychainGet
+	t.Cleanup(func() { keychainGet = original })
+	keychainGet = func(service, account string) (string, error) {
+		if service != cacheCredsService {
+			t.Errorf("got keychain service %q, want %q", service, cacheCredsService)
+		}
+		if secret, ok := secrets[account]; ok {
+			return secret, nil
+		}
+		return "", keychain.ErrNotFound
+	}
+
+	got := string(cacheNetrc([]string{
+		"https://team.cachix.org",
+		"https://cache.nixos.org",
+		"https://broken.example.com",
+		"https://cache.example.com/nix?priority=30",
+	}))
+	want := "machine team.cachix.org password token\n" +
+		"machine cache.example.com login ci password hunter2\n"
+	if got != want {
+		t.Errorf("got netrc:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/trace"
+	"slices"
+
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/envir"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+// ciRunnerImages are act's images for the GitHub-hosted Linux runners that
+// devbox ci --local runs jobs in, with the devbox environment added.
+var ciRunnerImages = map[string]string{
+	"ubuntu-latest": "catthehacker/ubuntu:act-latest",
+	"ubuntu-24.04":  "catthehacker/ubuntu:act-24.04",
+	"ubuntu-22.04":  "catthehacker/ubuntu:act-22.04",
+	"ubuntu-20.04":  "catthehacker/ubuntu:act-20.04",
+}
+
+// RunCI lists the jobs of the project's GitHub Actions workflows, or runs them
+// with act if local is true. The local jobs run in act's containers with the
+// devbox environment, so the steps that use the project's packages can be
+// tested before pushing. actArgs are passed on to act, such as ["-j", "test"]
+// to run one job.
+func (d *Devbox) RunCI(ctx context.Context, local bool, actArgs []string) error {
+	ctx, task := trace.NewTask(ctx, "devboxRunCI")
+	defer task.End()
+
+	if !fileutil.IsDir(filepath.Join(d.projectDir, ".github", "workflows")) {
+		return usererr.New("The project doesn't have any GitHub Actions workflows in .github/workflows.")
+	}
+	if local && runtime.GOOS != "linux" {
+		return usererr.New("devbox ci --local only works on Linux, since the jobs' containers use the packages from your Nix store.")
+	}
+	env, err := d.ensureStateIsUpToDateAndComputeEnv(ctx)
+	if err != nil {
+		return err
+	}
+	act, err := lookPathIn("act", env["PATH"])
+	if err != nil {
+		return usererr.New("devbox ci needs act. Run `devbox add act` to add it to your project.")
+	}
+
+	args := []string{"--list"}
+	if local {
+		// Only pass on the project's variables, with the values that
+		// devbox computed. The rest of the env, such as HOME, belongs
+		// to this machine.
+		projectEnv := map[string]string{}
+		for k := range d.cfg.Env {
+			projectEnv[k] = env[k]
+		}
+		args = ciActArgs(d.projectDir, env["PATH"], projectEnv)
+	}
+	cmd := exec.CommandContext(ctx, act, append(args, actArgs...)...)
+	cmd.Dir = d.projectDir
+	cmd.Env = envir.MapToPairs(env)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return errors.WithStack(cmd.Run())
+}
+
+// ciActArgs returns the act flags that run the jobs in act's images, with
+// the Nix store and the project's .devbox directory mounted read-only, so
+// the devbox PATH and the project's env work in them.
+func ciActArgs(projectDir, path string, projectEnv map[string]string) []string {
+	args := []string{}
+	runners := lo.Keys(ciRunnerImages)
+	slices.Sort(runners)
+	for _, runner := range runners {
+		args = append(args, "-P", runner+"="+ciRunnerImages[runner])
+	}
+	devboxDir := shellescape.Quote(filepath.Join(projectDir, ".devbox"))
+	args = append(args,
+		"--container-options", "-v /nix:/nix:ro -v "+devboxDir+":"+devboxDir+":ro",
+		"--env", "PATH="+path,
+	)
+	keys := lo.Keys(projectEnv)
+	slices.Sort(keys)
+	for _, k := range keys {
+		args = append(args, "--env", k+"="+projectEnv[k])
+	}
+	return args
+}
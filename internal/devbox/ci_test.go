@@ -0,0 +1,34 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCIActArgs(t *testing.T) {
+	got := ciActArgs("/project", "/project/.devbox/nix/profile/default/bin:/usr/bin", map[string]string{
+		"NODE_ENV": "test",
+		"BIN_DIR":  "/project/bin",
+	})
+	want := []string{
+		"-P", "ubuntu-20.04=catthehacker/ubuntu:act-20.04",
+		"-P", "ubuntu-22.04=catthehacker/ubuntu:act-22.04",
+		"-P", "ubuntu-24.04=catthehacker/ubuntu:act-24.04",
+		"-P", "ubuntu-latest=catthehacker/ubuntu:act-latest",
+		"--container-options", "-v /nix:/nix:ro -v /project/.devbox:/project/.devbox:ro",
+		"--env", "PATH=/project/.devbox/nix/profile/default/bin:/usr/bin",
+		"--env", "BIN_DIR=/project/bin",
+		"--env", "NODE_ENV=test",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got act args %q, want %q", got, want)
+	}
+
+	got = ciActArgs("/my project", "/usr/bin", nil)
+	if i := slices.Index(got, "--container-options"); got[i+1] != "-v /nix:/nix:ro -v '/my project/.devbox':'/my project/.devbox':ro" {
+		t.Errorf("got container options %q for a project with a space in its path", got[i+1])
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+// ExportClosure writes everything the project's environment needs to the file
+// at path, so ImportClosure can add it to the Nix store of a machine that
+// isn't connected to the internet. That machine must have the same system as
+// this one.
+func (d *Devbox) ExportClosure(ctx context.Context, path string) error {
+	ctx, task := trace.NewTask(ctx, "devboxExportClosure")
+	defer task.End()
+
+	if err := d.Prefetch(ctx); err != nil {
+		return err
+	}
+	storePaths, err := d.environmentStorePaths(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	fmt.Fprintln(d.stderr, "Exporting the environment's store paths.")
+	if err := nix.ExportClosure(ctx, f, storePaths...); err != nil {
+		return err
+	}
+	return errors.WithStack(f.Close())
+}
+
+// environmentStorePaths returns the store paths that devbox needs to create
+// the environment offline: the packages, the standard environment that nix
+// builds the shell environment with, and the sources of the generated flake's
+// inputs, such as nixpkgs.
+func (d *Devbox) environmentStorePaths(ctx context.Context) ([]string, error) {
+	env, err := d.computeEnv(ctx, true /*usePrintDevEnvCache*/)
+	if err != nil {
+		return nil, err
+	}
+	storePaths, err := nix.FlakeInputPaths(ctx, "path:"+d.flakeDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"buildInputs", "nativeBuildInputs", "stdenv"} {
+		for _, path := range strings.Fields(env[key]) {
+			if filepath.IsAbs(path) {
+				storePaths = append(storePaths, path)
+			}
+		}
+	}
+	return storePaths, nil
+}
+
+// ImportClosure adds the store paths in a file that ExportClosure wrote to the
+// Nix store.
+func (d *Devbox) ImportClosure(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	fmt.Fprintln(d.stderr, "Importing store paths. This may take a few minutes.")
+	return nix.ImportClosure(ctx, f)
+}
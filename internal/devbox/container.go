@@ -0,0 +1,252 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/trace"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/cachehash"
+	"go.jetpack.io/devbox/internal/devbox/generate"
+	"go.jetpack.io/devbox/internal/envir"
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/ux"
+)
+
+const (
+	// containerDir is where devbox shell --isolate=container keeps the
+	// Dockerfile of the project's image and its build context.
+	containerDir = ".devbox/gen/container"
+
+	// containerProjectDir is where the project is mounted in the
+	// container. It's the WORKDIR of the generated Dockerfile.
+	containerProjectDir = "/code"
+
+	// containerDefaultUID and containerGID are the IDs of the devbox user
+	// and group in the jetpackio/devbox image.
+	containerDefaultUID = 1000
+	containerGID        = 1000
+)
+
+// ContainerShell starts a devbox shell in a Docker or Podman container. The
+// container's image has the project's packages, and the project directory is
+// mounted in it, so changes to the project show up on both sides. The image
+// is rebuilt when the project's config, lockfile or local plugins change.
+func (d *Devbox) ContainerShell(ctx context.Context) error {
+	ctx, task := trace.NewTask(ctx, "devboxContainerShell")
+	defer task.End()
+
+	runtime, err := containerRuntime()
+	if err != nil {
+		return err
+	}
+	if !fileutil.Exists(filepath.Join(d.projectDir, "devbox.lock")) {
+		return usererr.New("devbox shell --isolate=container needs a devbox.lock. Run `devbox install` to create it.")
+	}
+
+	files, err := d.containerConfigFiles()
+	if err != nil {
+		return err
+	}
+	buildDir := filepath.Join(d.projectDir, containerDir)
+	if err := os.MkdirAll(buildDir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	localFlakes := d.getLocalFlakesDirs()
+	gen := &generate.Options{
+		Path:           buildDir,
+		LocalFlakeDirs: localFlakes,
+		ConfigFiles:    files,
+		UID:            containerUID(),
+	}
+	if err := gen.CreateDockerfile(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+	// The build context only needs the config files, unless the project has
+	// local flakes.
+	buildContext := buildDir
+	if len(localFlakes) > 0 {
+		buildContext = d.projectDir
+	} else {
+		for _, file := range files {
+			if err := copyContainerFile(filepath.Join(d.projectDir, file), filepath.Join(buildDir, file)); err != nil {
+				return err
+			}
+		}
+	}
+
+	image, err := d.containerImage(buildDir, files)
+	if err != nil {
+		return err
+	}
+	if exec.Command(runtime, "image", "inspect", image).Run() != nil {
+		ux.Finfo(d.stderr, "Building the container image %s. This can take a while the first time.\n", image)
+		build := exec.CommandContext(ctx, runtime, "build", "-t", image, "-f", filepath.Join(buildDir, "Dockerfile"), buildContext)
+		build.Stdout = d.stderr
+		build.Stderr = d.stderr
+		if err := build.Run(); err != nil {
+			return usererr.WithUserMessage(err, "Building the container image failed.")
+		}
+	}
+
+	var extraArgs []string
+	if filepath.Base(runtime) == "podman" {
+		// Rootless Podman maps the host user to root in the container.
+		// Keep its ID, which is the devbox user's in the image.
+		extraArgs = append(extraArgs, "--userns=keep-id")
+	}
+	if d.cfg.ForwardsAgents() {
+		extraArgs = append(extraArgs, containerAgentArgs(containerUID(), containerGID)...)
+	}
+	cmd := exec.CommandContext(ctx, runtime, containerRunArgs(d.projectDir, image, d.env, extraArgs)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return errors.WithStack(cmd.Run())
+}
+
+// containerUID returns the ID of the devbox user in the project's image. It's
+// this machine's user ID, so that the user owns the mounted project, unless
+// that's root or the OS doesn't have user IDs.
+func containerUID() int {
+	if uid := os.Getuid(); uid > 0 {
+		return uid
+	}
+	return containerDefaultUID
+}
+
+// containerRuntime returns Docker or Podman, whichever is installed, or the
+// one in DEVBOX_CONTAINER_RUNTIME.
+func containerRuntime() (string, error) {
+	if runtime := os.Getenv(envir.DevboxContainerRuntime); runtime != "" {
+		path, err := exec.LookPath(runtime)
+		if err != nil {
+			return "", usererr.New("Devbox couldn't find %s, which is the container runtime in %s.", runtime, envir.DevboxContainerRuntime)
+		}
+		return path, nil
+	}
+	for _, runtime := range []string{"docker", "podman"} {
+		if path, err := exec.LookPath(runtime); err == nil {
+			return path, nil
+		}
+	}
+	return "", usererr.New("devbox shell --isolate=container needs Docker or Podman. Install one of them and try again.")
+}
+
+// containerConfigFiles returns the files, relative to the project, that the
+// container's packages are installed from: the config, the lockfile,
+// devbox.local.json and the directories of local plugins.
+func (d *Devbox) containerConfigFiles() ([]string, error) {
+	files := []string{d.cfg.FileName(), "devbox.lock"}
+	if local := d.cfg.LocalFileName(); local != "" {
+		files = append(files, local)
+	}
+	for _, include := range d.cfg.Include {
+		path, ok := strings.CutPrefix(include, "path:")
+		if !ok {
+			continue
+		}
+		rel := filepath.Clean(path)
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, usererr.New("devbox shell --isolate=container can't include %s, since it's outside the project directory.", path)
+		}
+		// Plugins read the files they create from their directory. A
+		// plugin in the project's root only gets its own file, instead
+		// of the whole project.
+		if dir := filepath.Dir(rel); dir != "." {
+			rel = dir
+		}
+		if !slices.Contains(files, rel) {
+			files = append(files, rel)
+		}
+	}
+	return files, nil
+}
+
+// copyContainerFile copies a file or directory into the image's build
+// context, replacing the copy from a previous build.
+func copyContainerFile(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	if out, err := exec.Command("cp", "-R", src, dst).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "copy %s: %s", src, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// containerImage returns the tag of the project's image, which changes when
+// the files that the image is built from change.
+func (d *Devbox) containerImage(buildDir string, files []string) (string, error) {
+	sb := strings.Builder{}
+	h, err := cachehash.File(filepath.Join(buildDir, "Dockerfile"))
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(h)
+	for _, file := range files {
+		err := filepath.WalkDir(filepath.Join(d.projectDir, file), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			h, err := cachehash.File(path)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(h)
+			return nil
+		})
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	h, err = cachehash.Bytes([]byte(sb.String()))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("devbox-%s:%s", strings.ToLower(projectSlug(d.projectDir)), h[:12]), nil
+}
+
+// containerRunArgs returns the arguments of the docker run command that
+// starts the shell. extra are more docker run flags, such as the ones that
+// forward the agents.
+func containerRunArgs(projectDir, image string, env map[string]string, extra []string) []string {
+	name := strings.ToLower(projectSlug(projectDir))
+	// Projects in directories with the same name get different volumes.
+	dirHash, _ := cachehash.Bytes([]byte(projectDir))
+	args := []string{
+		"run", "--rm", "-it",
+		"--hostname", name,
+		"-v", projectDir + ":" + containerProjectDir,
+		// The container has its own .devbox directory, since the one in
+		// the project points at the host's Nix store. It's a volume, so
+		// the shell doesn't recompute the environment every time.
+		"-v", fmt.Sprintf("devbox-%s-%.8s:%s/.devbox", name, dirHash, containerProjectDir),
+		"-w", containerProjectDir,
+	}
+	if term := os.Getenv("TERM"); term != "" {
+		args = append(args, "-e", "TERM="+term)
+	}
+	keys := lo.Keys(env)
+	slices.Sort(keys)
+	for _, k := range keys {
+		args = append(args, "-e", k+"="+env[k])
+	}
+	args = append(args, extra...)
+	return append(args, image, "devbox", "shell")
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestContainerRunArgs(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	args := containerRunArgs("/home/me/My.App", "devbox-my-app:abc", map[string]string{"B": "2", "A": "1"}, nil)
+	got := strings.Join(args, " ")
+
+	assert.True(t, strings.HasPrefix(got, "run --rm -it --hostname my-app -v /home/me/My.App:/code -v devbox-my-app-"), got)
+	assert.True(t, strings.HasSuffix(got, ":/code/.devbox -w /code -e TERM=xterm-256color -e A=1 -e B=2 devbox-my-app:abc devbox shell"), got)
+
+	other := strings.Join(containerRunArgs("/tmp/My.App", "devbox-my-app:abc", nil, nil), " ")
+	assert.NotEqual(t, args[8], strings.Fields(other)[8], "want projects in different directories to get different volumes")
+}
+
+func TestContainerConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	fileutil.WriteFilesForTest(t, dir, map[string]string{
+		"devbox.yaml":             "include:\n  - path:plugins/db/plugin.json\n  - path:root-plugin.json\n",
+		"devbox.local.json":       `{"include": ["path:plugins/db/plugin.json"]}`,
+		"plugins/db/plugin.json":  `{"name": "db"}`,
+		"root-plugin.json":        `{"name": "root"}`,
+		"plugins/db/process.yaml": "",
+	})
+	d, err := Open(&devopt.Opts{Dir: dir, Stderr: io.Discard})
+	require.NoError(t, err)
+
+	files, err := d.containerConfigFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"devbox.yaml", "devbox.lock", "devbox.local.json", "plugins/db", "root-plugin.json"}, files)
+}
+
+func TestContainerConfigFilesOutsideProject(t *testing.T) {
+	dir := t.TempDir()
+	fileutil.WriteFilesForTest(t, dir, map[string]string{
+		"devbox.json": `{"include": ["path:../shared/plugin.json"]}`,
+	})
+	d, err := Open(&devopt.Opts{Dir: dir, Stderr: io.Discard})
+	require.NoError(t, err)
+
+	_, err = d.containerConfigFiles()
+	assert.ErrorContains(t, err, "outside the project directory")
+}
@@ -9,10 +9,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"runtime/trace"
 	"slices"
 	"strconv"
@@ -21,6 +23,7 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"go.jetpack.io/devbox/internal/cachehash"
@@ -40,6 +43,7 @@ import (
 	"go.jetpack.io/devbox/internal/devconfig"
 	"go.jetpack.io/devbox/internal/envir"
 	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec"
 	"go.jetpack.io/devbox/internal/lock"
 	"go.jetpack.io/devbox/internal/nix"
 	"go.jetpack.io/devbox/internal/plugin"
@@ -67,6 +71,11 @@ type Devbox struct {
 	preservePathStack        bool
 	pure                     bool
 	customProcessComposeFile string
+	packageGroups            devopt.PackageGroups
+	skipScriptDeps           bool
+
+	// secretEnv has the values of the config's secrets once they're read.
+	secretEnv map[string]string
 
 	// This is needed because of the --quiet flag.
 	stderr io.Writer
@@ -78,6 +87,43 @@ func InitConfig(dir string, writer io.Writer) (bool, error) {
 	return devconfig.Init(dir, writer)
 }
 
+// InitConfigWithOpts is like InitConfig, but lets the caller choose the format
+// of the config file and the recommended packages to add to it.
+func InitConfigWithOpts(dir string, opts devopt.InitOpts, writer io.Writer) (bool, error) {
+	return devconfig.InitWithOpts(dir, devconfig.InitOpts{
+		Format:         opts.Format,
+		SelectPackages: opts.SelectPackages,
+		From:           opts.From,
+	}, writer)
+}
+
+// Plan returns what devbox recommends for the projects it detects in dir:
+// the packages they need and the settings that devbox init adds for them.
+func Plan(dir string) (*initrec.Recommendation, error) {
+	return initrec.Get(dir)
+}
+
+// MigrateConfig upgrades the config of the project in dir (or one of its
+// parents) to the latest schema version.
+func MigrateConfig(dir string, writer io.Writer) error {
+	projectDir, err := findProjectDir(dir)
+	if err != nil {
+		return err
+	}
+	changes, err := devconfig.Migrate(projectDir)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		ux.Finfo(writer, "Your devbox.json is already up to date.\n")
+		return nil
+	}
+	for _, change := range changes {
+		ux.Fsuccess(writer, "%s\n", change)
+	}
+	return nil
+}
+
 func Open(opts *devopt.Opts) (*Devbox, error) {
 	projectDir, err := findProjectDir(opts.Dir)
 	if err != nil {
@@ -94,6 +140,27 @@ func Open(opts *devopt.Opts) (*Devbox, error) {
 		return nil, err
 	}
 
+	if err := validatePackageGroups(cfg, opts.PackageGroups); err != nil {
+		return nil, err
+	}
+
+	nixSettings := cfg.NixSettings()
+	nix.SetListOption("extra-substituters", nixSettings.Substituters)
+	nix.SetListOption("extra-trusted-public-keys", nixSettings.TrustedPublicKeys)
+	if err := setCacheCredentials(nixSettings.Substituters); err != nil {
+		return nil, err
+	}
+	nix.SetOffline(opts.Offline || nixSettings.Offline)
+	nix.SetAllowUnfree(cfg.AllowUnfree())
+	nix.SetBuilders(lo.Map(nixSettings.Builders, func(b devconfig.NixBuilder, _ int) nix.Builder {
+		return nix.Builder(b)
+	}))
+	nix.SetTuning(nix.Tuning{
+		MaxJobs:         nixSettings.MaxJobs,
+		Cores:           nixSettings.Cores,
+		HTTPConnections: nixSettings.HTTPConnections,
+	})
+
 	box := &Devbox{
 		cfg:                      cfg,
 		env:                      opts.Env,
@@ -105,6 +172,8 @@ func Open(opts *devopt.Opts) (*Devbox, error) {
 		preservePathStack:        opts.PreservePathStack,
 		pure:                     opts.Pure,
 		customProcessComposeFile: opts.CustomProcessComposeFile,
+		packageGroups:            opts.PackageGroups,
+		skipScriptDeps:           opts.SkipScriptDeps,
 	}
 
 	lock, err := lock.GetFile(box)
@@ -150,6 +219,14 @@ func Open(opts *devopt.Opts) (*Devbox, error) {
 		)
 	}
 
+	if !opts.IgnoreWarnings && cfg.NeedsMigration() {
+		ux.Fwarning(
+			os.Stderr,
+			"Your devbox.json uses fields from an older version of Devbox. "+
+				"Please run `devbox config migrate` to update it.\n",
+		)
+	}
+
 	return box, nil
 }
 
@@ -175,6 +252,29 @@ func (d *Devbox) ConfigHash() (string, error) {
 	for _, inc := range d.Includes() {
 		buf.WriteString(inc.Hash())
 	}
+	// Editing an overlay file changes the environment without changing
+	// devbox.json.
+	for _, overlay := range d.cfg.NixpkgsOverlays() {
+		if !devconfig.IsOverlayFile(overlay) {
+			continue
+		}
+		if !filepath.IsAbs(overlay) {
+			overlay = filepath.Join(d.projectDir, overlay)
+		}
+		h, err := cachehash.File(overlay)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(h)
+	}
+	// Selecting different package groups changes the environment even if
+	// devbox.json stays the same.
+	for _, g := range d.packageGroups.Only {
+		buf.WriteString("only:" + g)
+	}
+	for _, g := range d.packageGroups.Skip {
+		buf.WriteString("skip:" + g)
+	}
 	return cachehash.Bytes(buf.Bytes())
 }
 
@@ -210,10 +310,16 @@ func (d *Devbox) Shell(ctx context.Context) error {
 		return err
 	}
 
+	pluginFragments, err := d.pluginManager.ShellFragments(d.InstallablePackages(), d.cfg.Include)
+	if err != nil {
+		return err
+	}
+
 	opts := []ShellOption{
 		WithHistoryFile(filepath.Join(d.projectDir, shellHistoryFile)),
 		WithProjectDir(d.projectDir),
 		WithEnvVariables(envs),
+		WithPluginShellFragments(pluginFragments),
 		WithShellStartTime(telemetry.ShellStart()),
 	}
 
@@ -250,9 +356,16 @@ func (d *Devbox) RunScript(ctx context.Context, cmdName string, cmdArgs []string
 	}
 
 	var cmdWithArgs []string
+	dir := d.projectDir
 	if _, ok := d.cfg.Scripts()[cmdName]; ok {
+		if err := d.runScriptDependencies(cmdName, env); err != nil {
+			return err
+		}
 		// it's a script, so replace the command with the script file's path.
 		cmdWithArgs = append([]string{shellgen.ScriptPath(d.ProjectDir(), cmdName)}, cmdArgs...)
+		if env, dir, err = d.scriptEnvAndDir(cmdName, env); err != nil {
+			return err
+		}
 	} else {
 		// Arbitrary commands should also run the hooks, so we write them to a file as well. However, if the
 		// command args include env variable evaluations, then they'll be evaluated _before_ the hooks run,
@@ -271,7 +384,60 @@ func (d *Devbox) RunScript(ctx context.Context, cmdName string, cmdArgs []string
 		env["DEVBOX_RUN_CMD"] = strings.Join(append([]string{cmdName}, cmdArgs...), " ")
 	}
 
-	return nix.RunScript(d.projectDir, strings.Join(cmdWithArgs, " "), env)
+	return nix.RunScript(dir, strings.Join(cmdWithArgs, " "), env)
+}
+
+// runScriptDependencies runs the scripts that the named script depends on, in
+// order, stopping at the first one that fails.
+func (d *Devbox) runScriptDependencies(name string, env map[string]string) error {
+	if d.skipScriptDeps {
+		return nil
+	}
+	deps, err := d.cfg.ScriptDependencies(name)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		depEnv, dir, err := d.scriptEnvAndDir(dep, env)
+		if err != nil {
+			return err
+		}
+		ux.Finfo(d.stderr, "Running script %q (required by %q)\n", dep, name)
+		if err := nix.RunScript(dir, shellgen.ScriptPath(d.ProjectDir(), dep), depEnv); err != nil {
+			return errors.Wrapf(err, "script %q failed", dep)
+		}
+	}
+	return nil
+}
+
+// scriptEnvAndDir returns the environment and working directory for the named
+// script. The script's env is applied on top of the project environment, but
+// variables passed with --env still take precedence.
+func (d *Devbox) scriptEnvAndDir(name string, env map[string]string) (map[string]string, string, error) {
+	script := d.cfg.Scripts()[name]
+	dir := d.projectDir
+	if script.Dir != "" {
+		dir = script.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(d.projectDir, dir)
+		}
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			return nil, "", usererr.New("The dir %q of script %q is not a directory", script.Dir, name)
+		}
+	}
+	if len(script.Env) == 0 {
+		return env, dir, nil
+	}
+
+	vars := d.TemplateVars()
+	scriptEnv := make(map[string]string, len(script.Env))
+	for k, v := range script.Env {
+		scriptEnv[k] = vars.Expand(v)
+	}
+	result := maps.Clone(env)
+	maps.Copy(result, conf.OSExpandEnvMap(scriptEnv, env, d.projectDir))
+	maps.Copy(result, d.env)
+	return result, dir, nil
 }
 
 // Install ensures that all the packages in the config are installed
@@ -280,7 +446,10 @@ func (d *Devbox) Install(ctx context.Context) error {
 	ctx, task := trace.NewTask(ctx, "devboxInstall")
 	defer task.End()
 
-	return d.ensureStateIsUpToDate(ctx, ensure)
+	if err := d.ensureStateIsUpToDate(ctx, ensure); err != nil {
+		return err
+	}
+	return d.installGitHooks(ctx)
 }
 
 func (d *Devbox) ListScripts() []string {
@@ -327,6 +496,12 @@ func (d *Devbox) EnvExports(ctx context.Context, opts devopt.EnvExportsOpts) (st
 		return "", err
 	}
 
+	// The cache only has the variables that devbox changes, so it doesn't
+	// reset the others to the values they had when it was saved.
+	var hostVars []string
+	if opts.Cache {
+		envs, hostVars = changedEnv(envs)
+	}
 	envStr := exportify(envs)
 
 	if opts.RunHooks {
@@ -338,6 +513,14 @@ func (d *Devbox) EnvExports(ctx context.Context, opts devopt.EnvExportsOpts) (st
 		envStr += "\n" + d.refreshAlias()
 	}
 
+	if opts.Cache && len(d.cfg.Secrets) > 0 {
+		// The cache is a file, and secrets are never written to disk.
+		debug.Log("not saving the shellenv cache, since the project has secrets")
+	} else if opts.Cache {
+		if err := d.saveShellenvCache(opts, hostVars, envStr); err != nil {
+			debug.Log("failed to save shellenv cache: %v", err)
+		}
+	}
 	return envStr, nil
 }
 
@@ -413,11 +596,17 @@ func (d *Devbox) GenerateDevcontainer(ctx context.Context, generateOpts devopt.G
 	dockerfilePath := filepath.Join(devContainerPath, "Dockerfile")
 
 	// check if devcontainer.json or Dockerfile exist
+	files := "devcontainer.json or Dockerfile"
 	filesExist := fileutil.Exists(devContainerJSONPath) || fileutil.Exists(dockerfilePath)
+	if generateOpts.Feature {
+		// The Dockerfile can stay, since devcontainer.json doesn't use it.
+		files = "devcontainer.json or devbox/"
+		filesExist = fileutil.Exists(devContainerJSONPath) || fileutil.Exists(filepath.Join(devContainerPath, "devbox"))
+	}
 	if !generateOpts.Force && filesExist {
 		return usererr.New(
-			"Files devcontainer.json or Dockerfile are already present in .devcontainer/. " +
-				"Remove the files or use --force to overwrite them.",
+			"Files %s are already present in .devcontainer/. "+
+				"Remove the files or use --force to overwrite them.", files,
 		)
 	}
 
@@ -433,15 +622,26 @@ func (d *Devbox) GenerateDevcontainer(ctx context.Context, generateOpts devopt.G
 		Path:           devContainerPath,
 		RootUser:       generateOpts.RootUser,
 		IsDevcontainer: true,
-		Pkgs:           d.PackageNames(),
+		Feature:        generateOpts.Feature,
+		Pkgs:           d.selectedPackageNames(),
 		LocalFlakeDirs: d.getLocalFlakesDirs(),
+		PackageGroups:  d.packageGroups,
 	}
 
-	// generate dockerfile
-	err = gen.CreateDockerfile(ctx)
-	if err != nil {
-		return redact.Errorf("error generating dev container Dockerfile in <project>/%s: %w",
-			redact.Safe(filepath.Base(devContainerPath)), err)
+	if generateOpts.Feature {
+		// generate the feature that installs devbox
+		err = gen.CreateDevcontainerFeature(ctx)
+		if err != nil {
+			return redact.Errorf("error generating dev container feature in <project>/%s: %w",
+				redact.Safe(filepath.Base(devContainerPath)), err)
+		}
+	} else {
+		// generate dockerfile
+		err = gen.CreateDockerfile(ctx)
+		if err != nil {
+			return redact.Errorf("error generating dev container Dockerfile in <project>/%s: %w",
+				redact.Safe(filepath.Base(devContainerPath)), err)
+		}
 	}
 	// generate devcontainer.json
 	err = gen.CreateDevcontainer(ctx)
@@ -472,14 +672,68 @@ func (d *Devbox) GenerateDockerfile(ctx context.Context, generateOpts devopt.Gen
 		Path:           d.projectDir,
 		RootUser:       generateOpts.RootUser,
 		IsDevcontainer: false,
-		Pkgs:           d.PackageNames(),
+		Pkgs:           d.selectedPackageNames(),
 		LocalFlakeDirs: d.getLocalFlakesDirs(),
+		PackageGroups:  d.packageGroups,
+	}
+	scripts := d.cfg.Scripts()
+	for _, name := range dockerfileStageScripts {
+		if script, ok := scripts[name]; ok {
+			gen.Stages = append(gen.Stages, generate.Stage{Script: name, Inputs: script.Inputs})
+		}
+	}
+	if _, ok := scripts[dockerfileStartScript]; ok {
+		gen.StartScript = dockerfileStartScript
 	}
 
 	// generate dockerfile
 	return errors.WithStack(gen.CreateDockerfile(ctx))
 }
 
+// devPodManifestFile is the manifest that devbox generate k8s-devpod creates
+// in the project directory.
+const devPodManifestFile = "devbox-devpod.yaml"
+
+// GenerateK8sDevPod generates a Kubernetes manifest that runs the project's
+// image as a dev pod.
+func (d *Devbox) GenerateK8sDevPod(ctx context.Context, generateOpts devopt.GenerateOpts) error {
+	ctx, task := trace.NewTask(ctx, "devboxGenerateK8sDevPod")
+	defer task.End()
+
+	path := filepath.Join(d.projectDir, devPodManifestFile)
+	if !generateOpts.Force && fileutil.Exists(path) {
+		return usererr.New(
+			"%s is already present in the current directory. "+
+				"Remove it or use --force to overwrite it.",
+			devPodManifestFile,
+		)
+	}
+
+	// Kubernetes names are lowercase, and the longest name in the
+	// manifest, <name>-devpod-project, can't be longer than 63 characters.
+	name := strings.ToLower(strings.ReplaceAll(projectSlug(d.projectDir), "_", "-"))
+	name = strings.Trim(name[:min(len(name), 40)], "-")
+	image := generateOpts.Image
+	if image == "" {
+		image = "devbox-" + name + ":latest"
+	}
+	// The manifest is shared with the team, so it leaves out the personal
+	// values from devbox.local.json and the user-level defaults.
+	env, err := d.cfg.ProjectEnv()
+	if err != nil {
+		return err
+	}
+	pod := &generate.DevPod{Path: path, Name: name, Image: image, Env: env}
+	return errors.WithStack(pod.CreateManifest(ctx))
+}
+
+// dockerfileStageScripts are the scripts that a generated Dockerfile runs,
+// in order, to build the image. dockerfileStartScript is the script that the
+// image runs.
+var dockerfileStageScripts = []string{"install", "build"}
+
+const dockerfileStartScript = "start"
+
 func PrintEnvrcContent(w io.Writer, envFlags devopt.EnvFlags) error {
 	return generate.EnvrcContent(w, envFlags)
 }
@@ -531,9 +785,14 @@ func (d *Devbox) Services() (services.Services, error) {
 		return nil, err
 	}
 
+	configSvcs, err := services.FromConfig(d.projectDir, d.cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	userSvcs := services.FromUserProcessCompose(d.projectDir, d.customProcessComposeFile)
 
-	svcSet := lo.Assign(pluginSvcs, userSvcs)
+	svcSet := lo.Assign(pluginSvcs, configSvcs, userSvcs)
 	keys := make([]string, 0, len(svcSet))
 	for k := range svcSet {
 		keys = append(keys, k)
@@ -670,13 +929,16 @@ func (d *Devbox) ListServices(ctx context.Context, runInCurrentShell bool) error
 	return nil
 }
 
+// RestartServices restarts the services, or all the running services if
+// serviceNames is empty. Each service is stopped gracefully, and killed if
+// it's still running after timeout.
 func (d *Devbox) RestartServices(
-	ctx context.Context, runInCurrentShell bool, serviceNames ...string,
+	ctx context.Context, runInCurrentShell bool, timeout time.Duration, serviceNames ...string,
 ) error {
 	if !runInCurrentShell {
 		return d.RunScript(ctx, "devbox",
 			append(
-				[]string{"services", "restart", "--run-in-current-shell"},
+				[]string{"services", "restart", "--run-in-current-shell", "--timeout", timeout.String()},
 				serviceNames...,
 			),
 		)
@@ -688,8 +950,6 @@ func (d *Devbox) RestartServices(
 		return d.StartProcessManager(ctx, runInCurrentShell, serviceNames, true, "")
 	}
 
-	// TODO: Restart with no services should restart the _currently running_ services. This means we should get the list of running services from the process-compose, then restart them all.
-
 	svcSet, err := d.Services()
 	if err != nil {
 		return err
@@ -699,11 +959,23 @@ func (d *Devbox) RestartServices(
 		if _, ok := svcSet[s]; !ok {
 			return usererr.New(fmt.Sprintf("Service %s not found in your project", s))
 		}
-		err := services.RestartServices(ctx, s, d.projectDir, d.stderr)
+	}
+	if len(serviceNames) == 0 {
+		procs, err := services.ListServices(ctx, d.projectDir, d.stderr)
 		if err != nil {
-			fmt.Printf("Error restarting service %s: %s", s, err)
-		} else {
-			fmt.Printf("Service %s restarted", s)
+			return err
+		}
+		for _, p := range procs {
+			if p.IsActive() {
+				serviceNames = append(serviceNames, p.Name)
+			}
+		}
+		slices.Sort(serviceNames)
+	}
+
+	for _, s := range serviceNames {
+		if err := services.RestartServices(ctx, s, d.projectDir, timeout, d.stderr); err != nil {
+			fmt.Fprintf(d.stderr, "Error restarting service %s: %s\n", s, err)
 		}
 	}
 	return nil
@@ -743,19 +1015,9 @@ func (d *Devbox) StartProcessManager(
 		}
 	}
 
-	processComposePath, err := utilityLookPath("process-compose")
+	processComposePath, err := d.ensureProcessCompose(ctx)
 	if err != nil {
-		fmt.Fprintln(d.stderr, "Installing process-compose. This may take a minute but will only happen once.")
-		if err = d.addDevboxUtilityPackage(ctx, "github:F1bonacc1/process-compose/v0.43.1"); err != nil {
-			return err
-		}
-
-		// re-lookup the path to process-compose
-		processComposePath, err = utilityLookPath("process-compose")
-		if err != nil {
-			fmt.Fprintln(d.stderr, "failed to find process-compose after installing it.")
-			return err
-		}
+		return err
 	}
 
 	// Start the process manager
@@ -771,6 +1033,27 @@ func (d *Devbox) StartProcessManager(
 	)
 }
 
+// ensureProcessCompose installs process-compose in the devbox utility profile,
+// if it isn't there already, and returns its path.
+func (d *Devbox) ensureProcessCompose(ctx context.Context) (string, error) {
+	processComposePath, err := utilityLookPath("process-compose")
+	if err == nil {
+		return processComposePath, nil
+	}
+	fmt.Fprintln(d.stderr, "Installing process-compose. This may take a minute but will only happen once.")
+	if err = d.addDevboxUtilityPackage(ctx, "github:F1bonacc1/process-compose/v0.43.1"); err != nil {
+		return "", err
+	}
+
+	// re-lookup the path to process-compose
+	processComposePath, err = utilityLookPath("process-compose")
+	if err != nil {
+		fmt.Fprintln(d.stderr, "failed to find process-compose after installing it.")
+		return "", err
+	}
+	return processComposePath, nil
+}
+
 // computeEnv computes the set of environment variables that define a Devbox
 // environment. The "devbox run" and "devbox shell" commands source these
 // variables into a shell before executing a command or showing an interactive
@@ -948,6 +1231,14 @@ func (d *Devbox) computeEnv(ctx context.Context, usePrintDevEnvCache bool) (map[
 		debug.Log("PATH after glibc-patch hack is: %s", devboxEnvPath)
 	}
 
+	// Plugins' PATH entries, such as node_modules/.bin, go first so that the
+	// project's own tools take precedence over the packages.
+	pluginPaths, err := d.pluginManager.PathEntries(d.InstallablePackages(), d.cfg.Include)
+	if err != nil {
+		return nil, err
+	}
+	devboxEnvPath = envpath.JoinPathLists(append(pluginPaths, devboxEnvPath)...)
+
 	runXPaths, err := d.RunXPaths(ctx)
 	if err != nil {
 		return nil, err
@@ -970,6 +1261,20 @@ func (d *Devbox) computeEnv(ctx context.Context, usePrintDevEnvCache bool) (map[
 		env[k] = v
 	}
 
+	// Connection strings, such as DATABASE_URL, are composed last so they
+	// use the ports and credentials that the user overrode.
+	connectionEnv, err := d.pluginManager.ConnectionEnv(d.InstallablePackages(), d.cfg.Include)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range connectionEnv {
+		_, inConfig := configEnv[k]
+		_, inFlags := d.env[k]
+		if !inConfig && !inFlags {
+			env[k] = os.Expand(v, func(name string) string { return env[name] })
+		}
+	}
+
 	return env, d.addHashToEnv(env)
 }
 
@@ -1011,6 +1316,28 @@ func (d *Devbox) PackageNames() []string {
 	return d.cfg.Packages.VersionedNames()
 }
 
+// LocalPackageNames returns the names of the packages from devbox.local.json
+// and the user-level defaults that aren't in devbox.json.
+func (d *Devbox) LocalPackageNames() []string {
+	names := []string{}
+	for _, pkg := range d.cfg.LocalPackages() {
+		names = append(names, pkg.VersionedName())
+	}
+	return names
+}
+
+// selectedPackageNames returns the names of the devbox.json packages that
+// are selected by the --only and --skip package group flags.
+func (d *Devbox) selectedPackageNames() []string {
+	names := []string{}
+	for _, pkg := range d.ConfigPackages() {
+		if !pkg.IsLocal && d.isInSelectedGroups(pkg) {
+			names = append(names, pkg.Raw)
+		}
+	}
+	return names
+}
+
 // ConfigPackages returns the packages that are defined in devbox.json
 // NOTE: the return type is different from devconfig.Packages
 func (d *Devbox) ConfigPackages() []*devpkg.Package {
@@ -1020,10 +1347,34 @@ func (d *Devbox) ConfigPackages() []*devpkg.Package {
 // InstallablePackages returns the packages that are to be installed
 func (d *Devbox) InstallablePackages() []*devpkg.Package {
 	return lo.Filter(d.ConfigPackages(), func(pkg *devpkg.Package, _ int) bool {
-		return pkg.IsInstallable()
+		return pkg.IsInstallable() && d.isInSelectedGroups(pkg)
 	})
 }
 
+// isInSelectedGroups returns whether the package is selected by the --only and
+// --skip package group flags. Packages without groups are always selected. A
+// package that belongs to a skipped group is never selected, even if it also
+// belongs to a group passed to --only.
+func (d *Devbox) isInSelectedGroups(pkg *devpkg.Package) bool {
+	if len(pkg.Groups) == 0 {
+		return true
+	}
+	for _, g := range pkg.Groups {
+		if slices.Contains(d.packageGroups.Skip, g) {
+			return false
+		}
+	}
+	if len(d.packageGroups.Only) == 0 {
+		return true
+	}
+	for _, g := range pkg.Groups {
+		if slices.Contains(d.packageGroups.Only, g) {
+			return true
+		}
+	}
+	return false
+}
+
 // AllInstallablePackages returns installable user packages and plugin
 // packages concatenated in correct order
 func (d *Devbox) AllInstallablePackages() ([]*devpkg.Package, error) {
@@ -1140,17 +1491,77 @@ func (d *Devbox) configEnvs(
 				}
 			}
 		}
-	} else if d.cfg.EnvFrom != "" {
-		return nil, usererr.New(
-			"unknown from_env value: %s. Supported value is: %q.",
-			d.cfg.EnvFrom,
-			"jetpack-cloud",
-		)
 	}
+	vars := d.TemplateVars()
 	for k, v := range d.cfg.Env {
-		env[k] = v
+		env[k] = vars.Expand(v)
 	}
-	return conf.OSExpandEnvMap(env, existingEnv, d.ProjectDir()), nil
+	env = conf.OSExpandEnvMap(env, existingEnv, d.ProjectDir())
+
+	// Dotenv files override the env map, and later files override earlier
+	// ones. Variables passed with --env or --env-file override both since
+	// they're applied last in computeEnv.
+	fileEnv, err := d.envFileEnvs()
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(env, fileEnv)
+
+	// Secrets override the env map and the dotenv files.
+	secretEnv, err := d.secretEnvs(ctx, existingEnv)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(env, secretEnv)
+	return env, nil
+}
+
+// envFileEnvs reads the dotenv files listed in env_from. Files that don't
+// exist are skipped with a warning so that optional files like .env.local can
+// be listed. Only variable names are logged since dotenv files often contain
+// secrets.
+func (d *Devbox) envFileEnvs() (map[string]string, error) {
+	env := map[string]string{}
+	for _, file := range d.cfg.EnvFiles() {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(d.projectDir, path)
+		}
+		b, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			if !isEnvFilePath(file) {
+				return nil, usererr.New(
+					"unknown env_from value: %s. Supported values are %q or the path of a .env file.",
+					file,
+					"jetpack-cloud",
+				)
+			}
+			ux.Fwarning(d.stderr, "env_from file %s doesn't exist, skipping it.\n", file)
+			continue
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		vars, err := godotenv.UnmarshalBytes(b)
+		if err != nil {
+			// Parse errors quote the surrounding text, which might be a
+			// secret, so don't show them.
+			return nil, usererr.New("Failed to parse env_from file %s. Each line should be in the form KEY=VALUE.", file)
+		}
+		names := lo.Keys(vars)
+		slices.Sort(names)
+		debug.Log("env_from: loaded %s from %s", strings.Join(names, ", "), path)
+		maps.Copy(env, vars)
+	}
+	return env, nil
+}
+
+// isEnvFilePath reports whether an env_from value that doesn't exist looks
+// like the path of a dotenv file, as opposed to a typo of "jetpack-cloud".
+func isEnvFilePath(source string) bool {
+	if strings.ContainsRune(source, '/') || strings.ContainsRune(source, filepath.Separator) {
+		return true
+	}
+	return source == ".env" || strings.HasPrefix(source, ".env.") || strings.HasSuffix(source, ".env")
 }
 
 // ignoreCurrentEnvVar contains environment variables that Devbox should remove
@@ -1199,6 +1610,18 @@ var ignoreDevEnvVar = map[string]bool{
 	"UID":                true,
 }
 
+// TemplateVars returns the values of the template variables that can be used in
+// devbox.json env values, init hooks and scripts.
+func (d *Devbox) TemplateVars() devconfig.TemplateVars {
+	return devconfig.TemplateVars{
+		ProjectDir: d.projectDir,
+		DevboxDir:  filepath.Join(d.projectDir, ".devbox"),
+		Virtenv:    filepath.Join(d.projectDir, plugin.VirtenvPath),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
 func (d *Devbox) ProjectDirHash() string {
 	h, _ := cachehash.Bytes([]byte(d.projectDir))
 	return h
@@ -1228,7 +1651,11 @@ func (d *Devbox) parseEnvAndExcludeSpecialCases(currentEnv []string) (map[string
 		// - HOME required for devbox binary to work
 		// - PATH to find the nix installation. It is cleaned for pure mode below.
 		// - TERM to enable colored text in the pure shell
-		if !d.pure || key == "HOME" || key == "PATH" || key == "TERM" {
+		// - anything the user asked to keep with keep_env
+		// - the SSH and GPG agents' variables, with forward_agents
+		if !d.pure || key == "HOME" || key == "PATH" || key == "TERM" ||
+			slices.Contains(d.cfg.KeepEnv, key) ||
+			(d.cfg.ForwardsAgents() && slices.Contains(agentEnvVars, key)) {
 			env[key] = val
 		}
 	}
@@ -1295,6 +1722,22 @@ func (d *Devbox) RunXPaths(ctx context.Context) (string, error) {
 	return runxBinPath, nil
 }
 
+func validatePackageGroups(cfg *devconfig.Config, groups devopt.PackageGroups) error {
+	known := cfg.Packages.Groups()
+	for _, g := range slices.Concat(groups.Only, groups.Skip) {
+		if !slices.Contains(known, g) {
+			if len(known) == 0 {
+				return usererr.New("unknown package group %q. No packages in devbox.json have groups.", g)
+			}
+			return usererr.New(
+				"unknown package group %q. Package groups in devbox.json are: %s",
+				g, strings.Join(known, ", "),
+			)
+		}
+	}
+	return nil
+}
+
 func validateEnvironment(environment string) (string, error) {
 	if environment == "" {
 		return "dev", nil
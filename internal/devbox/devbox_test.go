@@ -4,6 +4,7 @@
 package devbox
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -18,6 +19,7 @@ import (
 
 	"go.jetpack.io/devbox/internal/devbox/devopt"
 	"go.jetpack.io/devbox/internal/devconfig"
+	"go.jetpack.io/devbox/internal/devpkg"
 	"go.jetpack.io/devbox/internal/envir"
 	"go.jetpack.io/devbox/internal/nix"
 )
@@ -121,6 +123,148 @@ func TestComputeDevboxPathWhenRemoving(t *testing.T) {
 	assert.NotEqual(t, path, path2, "path should not be the same")
 }
 
+func TestIsInSelectedGroups(t *testing.T) {
+	testCases := []struct {
+		name   string
+		groups devopt.PackageGroups
+		pkg    []string
+		want   bool
+	}{
+		{name: "no groups selected", pkg: []string{"dev"}, want: true},
+		{name: "ungrouped package", groups: devopt.PackageGroups{Only: []string{"prod"}}, want: true},
+		{name: "only matching group", groups: devopt.PackageGroups{Only: []string{"prod"}}, pkg: []string{"prod"}, want: true},
+		{name: "only other group", groups: devopt.PackageGroups{Only: []string{"prod"}}, pkg: []string{"dev", "test"}, want: false},
+		{name: "skip matching group", groups: devopt.PackageGroups{Skip: []string{"test"}}, pkg: []string{"dev", "test"}, want: false},
+		{name: "skip other group", groups: devopt.PackageGroups{Skip: []string{"test"}}, pkg: []string{"dev"}, want: true},
+		{
+			name:   "skip wins over only",
+			groups: devopt.PackageGroups{Only: []string{"dev"}, Skip: []string{"test"}},
+			pkg:    []string{"dev", "test"},
+			want:   false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &Devbox{packageGroups: tc.groups}
+			pkg := devpkg.PackageFromStringWithDefaults("hello", nil)
+			pkg.Groups = tc.pkg
+			assert.Equal(t, tc.want, d.isInSelectedGroups(pkg))
+		})
+	}
+}
+
+func TestOpenUnknownPackageGroup(t *testing.T) {
+	path := t.TempDir()
+	err := os.WriteFile(filepath.Join(path, "devbox.json"), []byte(`{
+  "packages": {
+    "go": "latest",
+    "golangci-lint": {"version": "latest", "groups": ["dev"]}
+  }
+}`), 0o644)
+	require.NoError(t, err)
+
+	_, err = Open(&devopt.Opts{
+		Dir:           path,
+		Stderr:        os.Stderr,
+		PackageGroups: devopt.PackageGroups{Only: []string{"prod"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown package group "prod"`)
+}
+
+func TestConfigEnvsFromEnvFiles(t *testing.T) {
+	path := t.TempDir()
+	err := os.WriteFile(filepath.Join(path, "devbox.json"), []byte(`{
+  "packages": [],
+  "env": {"FROM_CONFIG": "config", "OVERRIDDEN": "config"},
+  "env_from": [".env", ".env.local", ".env.missing"]
+}`), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(path, ".env"), []byte("OVERRIDDEN=env\nFROM_ENV=env\n"), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(path, ".env.local"), []byte("FROM_ENV=local\n"), 0o644)
+	require.NoError(t, err)
+
+	stderr := &bytes.Buffer{}
+	d, err := Open(&devopt.Opts{Dir: path, Stderr: stderr})
+	require.NoError(t, err)
+	env, err := d.configEnvs(context.Background(), map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"FROM_CONFIG": "config",
+		"OVERRIDDEN":  "env",
+		"FROM_ENV":    "local",
+	}, env)
+	assert.Contains(t, stderr.String(), "env_from file .env.missing doesn't exist")
+}
+
+func TestConfigEnvsUnknownEnvFrom(t *testing.T) {
+	for _, source := range []string{"jetpack_cloud", ".evn"} {
+		t.Run(source, func(t *testing.T) {
+			path := t.TempDir()
+			err := os.WriteFile(filepath.Join(path, "devbox.json"), []byte(`{
+  "packages": [],
+  "env_from": "`+source+`"
+}`), 0o644)
+			require.NoError(t, err)
+
+			d, err := Open(&devopt.Opts{Dir: path, Stderr: os.Stderr})
+			require.NoError(t, err)
+			_, err = d.configEnvs(context.Background(), map[string]string{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "unknown env_from value: "+source)
+		})
+	}
+}
+
+func TestScriptEnvAndDir(t *testing.T) {
+	path := t.TempDir()
+	err := os.WriteFile(filepath.Join(path, "devbox.json"), []byte(`{
+  "packages": [],
+  "shell": {
+    "scripts": {
+      "test": "go test ./...",
+      "migrate": {
+        "command": "migrate up",
+        "env": {"DATABASE_URL": "postgres://$DB_HOST/app", "FROM_CLI": "script"},
+        "dir": "db"
+      },
+      "missing": {"command": "true", "dir": "missing"}
+    }
+  }
+}`), 0o644)
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(path, "db"), 0o755))
+
+	d, err := Open(&devopt.Opts{
+		Dir:    path,
+		Stderr: os.Stderr,
+		Env:    map[string]string{"FROM_CLI": "cli"},
+	})
+	require.NoError(t, err)
+	env := map[string]string{"DB_HOST": "localhost", "FROM_CLI": "cli"}
+
+	gotEnv, gotDir, err := d.scriptEnvAndDir("test", env)
+	require.NoError(t, err)
+	assert.Equal(t, env, gotEnv)
+	assert.Equal(t, d.projectDir, gotDir)
+
+	gotEnv, gotDir, err = d.scriptEnvAndDir("migrate", env)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_HOST":      "localhost",
+		"DATABASE_URL": "postgres://localhost/app",
+		"FROM_CLI":     "cli",
+	}, gotEnv)
+	assert.Equal(t, filepath.Join(d.projectDir, "db"), gotDir)
+	assert.Equal(t, "cli", env["FROM_CLI"], "project env was modified")
+	_, ok := env["DATABASE_URL"]
+	assert.False(t, ok, "project env was modified")
+
+	_, _, err = d.scriptEnvAndDir("missing", env)
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
 func devboxForTesting(t *testing.T) *Devbox {
 	path := t.TempDir()
 	_, err := devconfig.Init(path, os.Stdout)
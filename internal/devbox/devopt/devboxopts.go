@@ -16,12 +16,39 @@ type Opts struct {
 	Pure                     bool
 	IgnoreWarnings           bool
 	CustomProcessComposeFile string
-	Stderr                   io.Writer
+	PackageGroups            PackageGroups
+	SkipScriptDeps           bool
+	// Offline stops nix from using the network. Packages that aren't in
+	// the Nix store are reported instead of downloaded.
+	Offline bool
+	Stderr  io.Writer
+}
+
+// PackageGroups selects which groups of packages from devbox.json are
+// installed. Packages without groups are always installed.
+type PackageGroups struct {
+	Only []string
+	Skip []string
+}
+
+type InitOpts struct {
+	// Format is the format of the config file: "json" or "yaml".
+	Format string
+	// SelectPackages chooses which of the recommended packages are added to
+	// the config. If it's nil, they're only suggested.
+	SelectPackages func(pkgs []string) ([]string, error)
+	// From is an existing environment file, such as shell.nix, to migrate.
+	From string
 }
 
 type GenerateOpts struct {
 	Force    bool
 	RootUser bool
+	// Image is the image that a generated Kubernetes manifest runs.
+	Image string
+	// Feature makes devbox generate devcontainer use a dev container
+	// feature instead of a Dockerfile.
+	Feature bool
 }
 
 type EnvFlags struct {
@@ -57,6 +84,9 @@ type UpdateOpts struct {
 }
 
 type EnvExportsOpts struct {
+	// Cache saves the exports, so the next call to CachedEnvExports can
+	// return them without computing the environment again.
+	Cache                    bool
 	DontRecomputeEnvironment bool
 	NoRefreshAlias           bool
 	RunHooks                 bool
@@ -6,6 +6,7 @@ package devbox
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"go.jetpack.io/devbox/internal/boxcli/usererr"
@@ -51,6 +52,16 @@ func findProjectDirAtPath(absPath string) (string, error) {
 		if !fileutil.Exists(filepath.Clean(absPath)) {
 			return "", missingConfigError(absPath, false /*didCheckParents*/)
 		}
+		// The config is loaded by name from the returned directory, so a
+		// file with any other name would be silently ignored.
+		if !devconfig.IsConfigName(filepath.Base(absPath)) {
+			return "", usererr.New(
+				"%s is not a devbox config file. The config path must be a "+
+					"directory or a file named %s.",
+				absPath,
+				strings.Join(devconfig.ValidConfigNames(), ", "),
+			)
+		}
 		// we return a directory from this function
 		return filepath.Dir(absPath), nil
 	}
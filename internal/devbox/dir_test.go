@@ -140,12 +140,25 @@ func TestFindParentDirAtPath(t *testing.T) {
 	}
 }
 
+func TestFindParentDirAtPathNotConfigName(t *testing.T) {
+	root := t.TempDir()
+	err := os.WriteFile(filepath.Join(root, devconfig.ValidConfigNames()[0]), []byte("{}"), 0o666)
+	assert.NoError(t, err)
+	other := filepath.Join(root, "other.json")
+	err = os.WriteFile(other, []byte("{}"), 0o666)
+	assert.NoError(t, err)
+
+	_, err = findProjectDirAtPath(other)
+	assert.ErrorContains(t, err, "is not a devbox config file")
+}
+
 func TestNixpkgsValidation(t *testing.T) {
 	testCases := map[string]struct {
 		commit   string
 		isErrant bool
 	}{
 		"invalid_nixpkg_commit": {"1234545", true},
+		"non_hex_nixpkg_commit": {"refs/heads/nixpkgs-unstable-2023-10-25-x", true},
 		"valid_nixpkg_commit":   {"af9e00071d0971eb292fd5abef334e66eda3cb69", false},
 	}
 
@@ -0,0 +1,90 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/envir"
+)
+
+const defaultEditor = "code"
+
+// OpenEditor starts an editor with the devbox environment, so the terminals,
+// debuggers and language servers that it starts find the project's packages.
+// editor is a command, such as "cursor" or "code --new-window". If it's
+// empty, OpenEditor uses the editor in devbox.json, or VS Code. It opens the
+// project directory if paths is empty.
+func (d *Devbox) OpenEditor(ctx context.Context, editor string, paths ...string) error {
+	ctx, task := trace.NewTask(ctx, "devboxOpenEditor")
+	defer task.End()
+
+	if editor == "" {
+		editor = d.cfg.Editor()
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return usererr.New("The editor command is empty.")
+	}
+
+	env, err := d.ensureStateIsUpToDateAndComputeEnv(ctx)
+	if err != nil {
+		return err
+	}
+	// An editor that inherits DEVBOX_OG_PATH_<hash> makes the devbox global
+	// shellenv in its terminals reset PATH, which hides the project's
+	// packages.
+	for k := range env {
+		if strings.HasPrefix(k, "DEVBOX_OG_PATH") {
+			delete(env, k)
+		}
+	}
+
+	// Look the editor up in the devbox environment, so it can be one of the
+	// project's packages.
+	program, err := lookPathIn(args[0], env["PATH"])
+	if err != nil {
+		return usererr.New(
+			"Devbox couldn't find the editor %q. Install it, or choose another one with --editor "+
+				"or shell.editor in devbox.json.", args[0],
+		)
+	}
+	if len(paths) == 0 {
+		paths = []string{d.projectDir}
+	}
+	cmd := exec.CommandContext(ctx, program, append(args[1:], paths...)...)
+	cmd.Env = envir.MapToPairs(env)
+	cmd.Stdout = d.stderr
+	cmd.Stderr = d.stderr
+	return errors.WithStack(cmd.Run())
+}
+
+// lookPathIn is like exec.LookPath, but searches the directories in path
+// instead of the current PATH.
+func lookPathIn(file, path string) (string, error) {
+	if strings.Contains(file, "/") {
+		return exec.LookPath(file)
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
+		}
+		candidate := filepath.Join(dir, file)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			return candidate, nil
+		}
+	}
+	return "", errors.WithStack(exec.ErrNotFound)
+}
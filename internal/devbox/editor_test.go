@@ -0,0 +1,26 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookPathIn(t *testing.T) {
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir1, "code"), []byte{}, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir2, "code"), []byte{}, 0o755))
+
+	got, err := lookPathIn("code", dir1+string(filepath.ListSeparator)+dir2)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir2, "code"), got, "want the first executable code")
+
+	_, err = lookPathIn("cursor", dir1+string(filepath.ListSeparator)+dir2)
+	assert.Error(t, err)
+}
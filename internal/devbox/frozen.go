@@ -0,0 +1,40 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"strings"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+)
+
+// CheckLockfileInSync returns an error if devbox.lock doesn't match
+// devbox.json, such as when someone added a package to devbox.json but didn't
+// commit the updated devbox.lock. It doesn't resolve or install anything.
+func (d *Devbox) CheckLockfileInSync() error {
+	missing, extra := d.lockfile.OutOfSync()
+	for _, pkg := range d.ConfigPackages() {
+		if pkg.IsLocal || pkg.Channel() == "" {
+			continue
+		}
+		if entry := d.lockfile.Get(pkg.Raw); entry != nil && entry.Channel != pkg.Channel() {
+			missing = append(missing, pkg.Raw+" (channel "+pkg.Channel()+")")
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil
+	}
+
+	msg := "devbox.lock is out of sync with devbox.json.\n"
+	if len(missing) > 0 {
+		msg += "\nThese packages aren't locked, or are locked to a different channel:\n\n  " +
+			strings.Join(missing, "\n  ") + "\n"
+	}
+	if len(extra) > 0 {
+		msg += "\nThese packages are in devbox.lock, but not in devbox.json:\n\n  " +
+			strings.Join(extra, "\n  ") + "\n"
+	}
+	msg += "\nRun `devbox install` and commit devbox.lock."
+	return usererr.New("%s", msg)
+}
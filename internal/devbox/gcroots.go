@@ -0,0 +1,77 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/internal/ux"
+)
+
+// gcRootsDir is the directory, relative to the project, with a garbage
+// collector root for each store path that the project's environment uses.
+const gcRootsDir = ".devbox/gcroots"
+
+// syncGCRoots registers the project's packages and the sources of its flake
+// inputs, such as nixpkgs, as garbage collector roots so nix-collect-garbage
+// doesn't delete them. It removes the roots of store paths the project no
+// longer uses. Nix forgets the roots on its own when the project is deleted.
+//
+// The roots are a cache, so syncGCRoots warns instead of failing when it
+// can't register them.
+func (d *Devbox) syncGCRoots(ctx context.Context, packageStorePaths []string) {
+	if err := d.writeGCRoots(ctx, packageStorePaths); err != nil {
+		debug.Log("error registering gc roots: %v", err)
+		ux.Fwarning(
+			d.stderr,
+			"Devbox couldn't protect this project's packages from Nix garbage collection. "+
+				"Running nix-collect-garbage may delete them.\n",
+		)
+	}
+}
+
+func (d *Devbox) writeGCRoots(ctx context.Context, packageStorePaths []string) error {
+	inputPaths, err := nix.FlakeInputPaths(ctx, "path:"+d.flakeDir())
+	if err != nil {
+		return err
+	}
+
+	// Name each root after its store path's base name (<hash>-<name>) so an
+	// existing root is up to date if its name matches.
+	want := map[string]string{}
+	for _, path := range append(packageStorePaths, inputPaths...) {
+		want[filepath.Base(path)] = path
+	}
+
+	dir := filepath.Join(d.projectDir, gcRootsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, entry := range entries {
+		if _, ok := want[entry.Name()]; ok {
+			delete(want, entry.Name())
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, entry.Name()))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errors.WithStack(err)
+		}
+	}
+	for name, path := range want {
+		if err := nix.AddGCRoot(ctx, filepath.Join(dir, name), path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
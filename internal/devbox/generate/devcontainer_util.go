@@ -6,6 +6,7 @@ package generate
 // package generate has functionality to implement the `devbox generate` command
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"encoding/json"
@@ -29,13 +30,45 @@ type Options struct {
 	Path           string
 	RootUser       bool
 	IsDevcontainer bool
+	// Feature makes devcontainer.json install devbox with the feature that
+	// CreateDevcontainerFeature writes, instead of building a Dockerfile.
+	Feature        bool
 	Pkgs           []string
 	LocalFlakeDirs []string
+	// ConfigFiles are the files and directories, relative to the
+	// project, that a generated Dockerfile copies before installing the
+	// packages. They default to devbox.json and devbox.lock.
+	ConfigFiles []string
+	// UID is the user ID that a generated Dockerfile gives the devbox
+	// user, so that it owns the files of a project mounted from the host.
+	// The image's ID is kept if it's 0.
+	UID int
+	// PackageGroups selects the package groups that the generated files
+	// install, with the same --only and --skip flags as devbox install.
+	PackageGroups devopt.PackageGroups
+
+	// Stages are the scripts that a generated Dockerfile runs, in order,
+	// after installing the packages.
+	Stages []Stage
+	// StartScript is the script that the image runs. The image starts a
+	// devbox shell if it's empty.
+	StartScript string
+}
+
+// Stage is a script that a generated Dockerfile runs to build the image. Each
+// stage is its own layer. A stage with inputs only copies those files, so the
+// layer stays cached until they change. A stage without inputs copies the
+// whole project.
+type Stage struct {
+	Script string
+	Inputs []string
 }
 
 type devcontainerObject struct {
 	Name           string          `json:"name"`
-	Build          *build          `json:"build"`
+	Image          string          `json:"image,omitempty"`
+	Build          *build          `json:"build,omitempty"`
+	Features       map[string]any  `json:"features,omitempty"`
 	Customizations *customizations `json:"customizations"`
 	RemoteUser     string          `json:"remoteUser"`
 }
@@ -58,6 +91,27 @@ type dockerfileData struct {
 	IsDevcontainer bool
 	RootUser       bool
 	LocalFlakeDirs []string
+	ConfigFiles    []string
+	UID            int
+	Stages         []dockerfileStage
+	// CopyProject is true when the project needs to be copied after the
+	// stages run, because none of them copied it.
+	CopyProject bool
+	StartScript string
+	// GroupFlags are the --only and --skip flags that the devbox commands
+	// in the Dockerfile run with.
+	GroupFlags []string
+}
+
+type dockerfileStage struct {
+	Script      string
+	Copies      []dockerfileCopy
+	CopyProject bool
+}
+
+type dockerfileCopy struct {
+	Src  string
+	Dest string
 }
 
 // CreateDockerfile creates a Dockerfile in path and writes devcontainerDockerfile.tmpl's content into it
@@ -74,11 +128,57 @@ func (g *Options) CreateDockerfile(ctx context.Context) error {
 	tmplName := "devcontainerDockerfile.tmpl"
 	t := template.Must(template.ParseFS(tmplFS, "tmpl/"+tmplName))
 	// write content into file
-	return t.Execute(file, &dockerfileData{
+	return t.Execute(file, g.dockerfileData())
+}
+
+func (g *Options) dockerfileData() *dockerfileData {
+	data := &dockerfileData{
 		IsDevcontainer: g.IsDevcontainer,
 		RootUser:       g.RootUser,
 		LocalFlakeDirs: g.LocalFlakeDirs,
-	})
+		ConfigFiles:    g.ConfigFiles,
+		UID:            g.UID,
+		StartScript:    g.StartScript,
+		GroupFlags:     g.groupFlags(),
+	}
+	if len(data.ConfigFiles) == 0 {
+		data.ConfigFiles = []string{"devbox.json", "devbox.lock"}
+	}
+	copiedProject := false
+	for _, stage := range g.Stages {
+		s := dockerfileStage{Script: stage.Script}
+		switch {
+		case copiedProject:
+			// The files are already in the image.
+		case len(stage.Inputs) == 0:
+			s.CopyProject = true
+			copiedProject = true
+		default:
+			for _, input := range stage.Inputs {
+				// Docker requires a directory destination that ends in a
+				// slash when the source is a glob.
+				s.Copies = append(s.Copies, dockerfileCopy{
+					Src:  input,
+					Dest: filepath.ToSlash(filepath.Dir(input)) + "/",
+				})
+			}
+		}
+		data.Stages = append(data.Stages, s)
+	}
+	data.CopyProject = !copiedProject && (len(g.Stages) > 0 || g.StartScript != "")
+	return data
+}
+
+// groupFlags returns the devbox flags that select g.PackageGroups.
+func (g *Options) groupFlags() []string {
+	flags := []string{}
+	if len(g.PackageGroups.Only) > 0 {
+		flags = append(flags, "--only="+strings.Join(g.PackageGroups.Only, ","))
+	}
+	if len(g.PackageGroups.Skip) > 0 {
+		flags = append(flags, "--skip="+strings.Join(g.PackageGroups.Skip, ","))
+	}
+	return flags
 }
 
 // CreateDevcontainer creates a devcontainer.json in path and writes getDevcontainerContent's output into it
@@ -102,6 +202,53 @@ func (g *Options) CreateDevcontainer(ctx context.Context) error {
 	return err
 }
 
+// devcontainerFeatureDir is the directory of the dev container feature, next
+// to devcontainer.json. Local features are referenced by their path relative
+// to devcontainer.json.
+const devcontainerFeatureDir = "devbox"
+
+// devcontainerFeatureImage is the image that devcontainer.json installs the
+// feature in.
+const devcontainerFeatureImage = "mcr.microsoft.com/devcontainers/base:debian"
+
+// CreateDevcontainerFeature writes a dev container feature that installs Nix
+// and devbox, and runs devbox install when the container is created. The
+// feature's directory can also be published on its own with
+// `devcontainer features publish`.
+func (g *Options) CreateDevcontainerFeature(ctx context.Context) error {
+	defer trace.StartRegion(ctx, "createDevcontainerFeature").End()
+
+	dir := filepath.Join(g.Path, devcontainerFeatureDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	t := template.Must(template.ParseFS(tmplFS, "tmpl/devcontainerFeature/devcontainer-feature.json"))
+	var featureJSON bytes.Buffer
+	if err := t.Execute(&featureJSON, struct{ GroupFlags []string }{g.groupFlags()}); err != nil {
+		return err
+	}
+	installScript, err := tmplFS.ReadFile("tmpl/devcontainerFeature/install.sh")
+	if err != nil {
+		return err
+	}
+	for name, file := range map[string]struct {
+		content []byte
+		mode    os.FileMode
+	}{
+		"devcontainer-feature.json": {featureJSON.Bytes(), 0o644},
+		"install.sh":                {installScript, 0o755},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), file.content, file.mode); err != nil {
+			return err
+		}
+		// WriteFile doesn't change the mode of a file that exists.
+		if err := os.Chmod(filepath.Join(dir, name), file.mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func CreateEnvrc(ctx context.Context, path string, envFlags devopt.EnvFlags) error {
 	defer trace.StartRegion(ctx, "createEnvrc").End()
 
@@ -154,6 +301,14 @@ func (g *Options) getDevcontainerContent() *devcontainerObject {
 		},
 		RemoteUser: "devbox",
 	}
+	projectDir := "/code"
+	if g.Feature {
+		devcontainerContent.Build = nil
+		devcontainerContent.Image = devcontainerFeatureImage
+		devcontainerContent.Features = map[string]any{"./" + devcontainerFeatureDir: map[string]any{}}
+		devcontainerContent.RemoteUser = "vscode"
+		projectDir = "${containerWorkspaceFolder}"
+	}
 	if g.RootUser {
 		devcontainerContent.RemoteUser = "root"
 	}
@@ -168,7 +323,7 @@ func (g *Options) getDevcontainerContent() *devcontainerObject {
 		if py3pattern.MatchString(pkg) {
 			// Setup python3 interpreter path to devbox in the container
 			devcontainerContent.Customizations.Vscode.Settings = map[string]any{
-				"python.defaultInterpreterPath": "/code/.devbox/nix/profile/default/bin/python3",
+				"python.defaultInterpreterPath": projectDir + "/.devbox/nix/profile/default/bin/python3",
 			}
 			// add python extension if a python3 package is installed
 			devcontainerContent.Customizations.Vscode.Extensions = append(devcontainerContent.Customizations.Vscode.Extensions, "ms-python.python")
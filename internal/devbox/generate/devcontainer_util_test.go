@@ -0,0 +1,197 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+func TestCreateDockerfileStages(t *testing.T) {
+	dir := t.TempDir()
+	g := &Options{
+		Path:     dir,
+		RootUser: true,
+		Stages: []Stage{
+			{Script: "install", Inputs: []string{"package.json", "web/yarn.lock"}},
+			{Script: "build"},
+		},
+		StartScript: "start",
+	}
+	if err := g.CreateDockerfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+
+	want := `RUN devbox run -- echo "Installed Packages."
+
+# Running the install script
+COPY package.json ./
+COPY web/yarn.lock web/
+RUN devbox run install
+
+# Running the build script
+COPY . .
+RUN devbox run build
+
+CMD ["devbox", "run", "start"]`
+	if !strings.Contains(got, want) {
+		t.Errorf("got Dockerfile:\n%s\nwant it to contain:\n%s", got, want)
+	}
+}
+
+func TestCreateDockerfileWithoutStages(t *testing.T) {
+	dir := t.TempDir()
+	g := &Options{Path: dir}
+	if err := g.CreateDockerfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	if strings.Contains(got, "COPY --chown=${DEVBOX_USER}:${DEVBOX_USER} . .") {
+		t.Errorf("got Dockerfile that copies the project without any stages:\n%s", got)
+	}
+	if !strings.Contains(got, `CMD ["devbox", "shell"]`) {
+		t.Errorf("got Dockerfile that doesn't start a shell:\n%s", got)
+	}
+}
+
+func TestCreateDockerfileConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	g := &Options{Path: dir, ConfigFiles: []string{"devbox.yaml", "devbox.lock", "plugins/db"}, UID: 501}
+	if err := g.CreateDockerfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+
+	want := `USER root
+RUN usermod -u 501 ${DEVBOX_USER} && chown -R 501 /home/${DEVBOX_USER} /nix
+USER ${DEVBOX_USER}:${DEVBOX_USER}
+COPY --chown=${DEVBOX_USER}:${DEVBOX_USER} devbox.yaml devbox.yaml
+COPY --chown=${DEVBOX_USER}:${DEVBOX_USER} devbox.lock devbox.lock
+COPY --chown=${DEVBOX_USER}:${DEVBOX_USER} plugins/db plugins/db
+`
+	if !strings.Contains(got, want) {
+		t.Errorf("got Dockerfile:\n%s\nwant it to contain:\n%s", got, want)
+	}
+	if strings.Contains(got, "devbox.json") {
+		t.Errorf("got Dockerfile that copies devbox.json:\n%s", got)
+	}
+}
+
+func TestCreateDockerfilePackageGroups(t *testing.T) {
+	dir := t.TempDir()
+	g := &Options{
+		Path:          dir,
+		RootUser:      true,
+		PackageGroups: devopt.PackageGroups{Only: []string{"prod"}, Skip: []string{"docs", "lint"}},
+		Stages:        []Stage{{Script: "build"}},
+		StartScript:   "start",
+	}
+	if err := g.CreateDockerfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+
+	for _, want := range []string{
+		`RUN devbox run --only=prod --skip=docs,lint -- echo "Installed Packages."`,
+		`RUN devbox run --only=prod --skip=docs,lint build`,
+		`CMD ["devbox", "run", "--only=prod", "--skip=docs,lint", "start"]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got Dockerfile:\n%s\nwant it to contain:\n%s", got, want)
+		}
+	}
+}
+
+func TestCreateDevcontainerFeature(t *testing.T) {
+	dir := t.TempDir()
+	g := &Options{Path: dir, IsDevcontainer: true, Feature: true, Pkgs: []string{"python3"}}
+	if err := g.CreateDevcontainerFeature(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CreateDevcontainer(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "devbox", "devcontainer-feature.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature := map[string]any{}
+	if err := json.Unmarshal(b, &feature); err != nil {
+		t.Fatalf("got invalid devcontainer-feature.json: %v", err)
+	}
+	if feature["id"] != "devbox" || feature["onCreateCommand"] != "devbox install" {
+		t.Errorf("got devcontainer-feature.json:\n%s\nwant id devbox and onCreateCommand devbox install", b)
+	}
+	info, err := os.Stat(filepath.Join(dir, "devbox", "install.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&0o111 == 0 {
+		t.Errorf("got install.sh mode %v, want it to be executable", info.Mode())
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, "devcontainer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]any{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("got invalid devcontainer.json: %v", err)
+	}
+	if _, ok := got["build"]; ok {
+		t.Errorf("got devcontainer.json with a build:\n%s", b)
+	}
+	if got["image"] != devcontainerFeatureImage {
+		t.Errorf("got image %v, want %s", got["image"], devcontainerFeatureImage)
+	}
+	if features, _ := got["features"].(map[string]any); features["./devbox"] == nil {
+		t.Errorf("got devcontainer.json without the devbox feature:\n%s", b)
+	}
+	if !strings.Contains(string(b), "${containerWorkspaceFolder}/.devbox/nix/profile/default/bin/python3") {
+		t.Errorf("got devcontainer.json without the workspace's python interpreter:\n%s", b)
+	}
+}
+
+func TestCreateDevcontainerFeaturePackageGroups(t *testing.T) {
+	dir := t.TempDir()
+	g := &Options{Path: dir, IsDevcontainer: true, Feature: true, PackageGroups: devopt.PackageGroups{Skip: []string{"ci"}}}
+	if err := g.CreateDevcontainerFeature(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "devbox", "devcontainer-feature.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature := map[string]any{}
+	if err := json.Unmarshal(b, &feature); err != nil {
+		t.Fatalf("got invalid devcontainer-feature.json: %v", err)
+	}
+	if got, want := feature["onCreateCommand"], "devbox install --skip=ci"; got != want {
+		t.Errorf("got onCreateCommand %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package generate
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+)
+
+// JetBrains is the configuration that devbox generate jetbrains writes to a
+// project's .idea directory.
+type JetBrains struct {
+	// IdeaDir is the project's .idea directory.
+	IdeaDir string
+
+	// GoRoot is the GOROOT of the project's Go package, for GoLand and the
+	// Go plugin.
+	GoRoot string
+
+	// NodePath is the project's node, for WebStorm and the Node.js plugin.
+	NodePath string
+
+	// Scripts are the devbox.json scripts to create run configurations for.
+	Scripts []string
+}
+
+// runConfigPrefix starts the names of the run configuration files that
+// devbox creates, so it can remove the ones for scripts that are gone.
+const runConfigPrefix = "devbox_"
+
+// Create writes the toolchains to workspace.xml and a run configuration for
+// each script. It keeps the rest of workspace.xml, which is where the IDE
+// saves its own state.
+func (j *JetBrains) Create() error {
+	if err := os.MkdirAll(j.IdeaDir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := j.updateWorkspace(); err != nil {
+		return err
+	}
+	return j.createRunConfigs()
+}
+
+func (j *JetBrains) updateWorkspace() error {
+	path := filepath.Join(j.IdeaDir, "workspace.xml")
+	root := newXMLNode("project", "version", "4")
+	if b, err := os.ReadFile(path); err == nil {
+		root = &xmlNode{}
+		if err := xml.Unmarshal(b, root); err != nil {
+			return errors.Wrapf(err, "parsing %s", path)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return errors.WithStack(err)
+	}
+
+	if j.GoRoot != "" {
+		root.component("GOROOT").setAttr("url", "file://"+j.GoRoot)
+	}
+	if j.NodePath != "" {
+		if err := root.component("PropertiesComponent").setProperty("nodejs_interpreter_path", j.NodePath); err != nil {
+			return err
+		}
+	}
+
+	b, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	b = append([]byte(xml.Header), b...)
+	return errors.WithStack(os.WriteFile(path, append(b, '\n'), 0o644))
+}
+
+func (j *JetBrains) createRunConfigs() error {
+	dir := filepath.Join(j.IdeaDir, "runConfigurations")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	stale, err := filepath.Glob(filepath.Join(dir, runConfigPrefix+"*.xml"))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	for _, script := range j.Scripts {
+		b, err := xml.MarshalIndent(shellRunConfig(script), "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		path := filepath.Join(dir, runConfigFileName(script))
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+func runConfigFileName(script string) string {
+	return runConfigPrefix + unsafeFileNameChars.ReplaceAllString(script, "_") + ".xml"
+}
+
+// shellRunConfig returns a Shell Script run configuration that runs a devbox
+// script in the IDE's terminal.
+func shellRunConfig(script string) *xmlNode {
+	config := newXMLNode("configuration",
+		"default", "false", "name", "devbox run "+script, "type", "ShConfigurationType")
+	options := []string{
+		"SCRIPT_TEXT", "devbox run " + shellescape.Quote(script),
+		"INDEPENDENT_SCRIPT_PATH", "true",
+		"SCRIPT_PATH", "",
+		"SCRIPT_OPTIONS", "",
+		"INDEPENDENT_SCRIPT_WORKING_DIRECTORY", "true",
+		"SCRIPT_WORKING_DIRECTORY", "$PROJECT_DIR$",
+		"INDEPENDENT_INTERPRETER_PATH", "true",
+		"INTERPRETER_PATH", "/bin/sh",
+		"INTERPRETER_OPTIONS", "",
+		"EXECUTE_IN_TERMINAL", "true",
+		"EXECUTE_SCRIPT_FILE", "false",
+	}
+	for i := 0; i < len(options); i += 2 {
+		config.Nodes = append(config.Nodes, newXMLNode("option", "name", options[i], "value", options[i+1]))
+	}
+	config.Nodes = append(config.Nodes, newXMLNode("envs"), newXMLNode("method", "v", "2"))
+
+	manager := newXMLNode("component", "name", "ProjectRunConfigurationManager")
+	manager.Nodes = []*xmlNode{config}
+	return manager
+}
+
+// xmlNode is an XML element that keeps the attributes and children that
+// devbox doesn't know about, so it can edit the IDE's files in place.
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []*xmlNode `xml:",any"`
+}
+
+// newXMLNode returns an element with attrs, which are pairs of names and
+// values.
+func newXMLNode(name string, attrs ...string) *xmlNode {
+	n := &xmlNode{XMLName: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		n.Attrs = append(n.Attrs, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+	}
+	return n
+}
+
+func (n *xmlNode) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	// Drop the indentation that the file had, since MarshalIndent adds its
+	// own.
+	type plain xmlNode
+	p := plain(*n)
+	if strings.TrimSpace(p.Content) == "" {
+		p.Content = ""
+	}
+	start.Name = n.XMLName
+	return e.EncodeElement(p, start)
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) setAttr(name, value string) {
+	for i, a := range n.Attrs {
+		if a.Name.Local == name {
+			n.Attrs[i].Value = value
+			return
+		}
+	}
+	n.Attrs = append(n.Attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+// component returns the child component with the name, and adds it if it
+// doesn't exist.
+func (n *xmlNode) component(name string) *xmlNode {
+	for _, c := range n.Nodes {
+		if c.XMLName.Local == "component" && c.attr("name") == name {
+			return c
+		}
+	}
+	c := newXMLNode("component", "name", name)
+	n.Nodes = append(n.Nodes, c)
+	return c
+}
+
+// setProperty sets a property of a PropertiesComponent. Recent IDEs keep
+// the properties as JSON in the component's text, and older ones as
+// property elements.
+func (n *xmlNode) setProperty(key, value string) error {
+	if text := strings.TrimSpace(n.Content); strings.HasPrefix(text, "{") {
+		props := map[string]any{}
+		if err := json.Unmarshal([]byte(text), &props); err != nil {
+			return errors.Wrap(err, "parsing the properties in workspace.xml")
+		}
+		keyToString, _ := props["keyToString"].(map[string]any)
+		if keyToString == nil {
+			keyToString = map[string]any{}
+		}
+		keyToString[key] = value
+		props["keyToString"] = keyToString
+		b, err := json.MarshalIndent(props, "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		n.Content = string(b)
+		return nil
+	}
+
+	for _, c := range n.Nodes {
+		if c.XMLName.Local == "property" && c.attr("name") == key {
+			c.setAttr("value", value)
+			return nil
+		}
+	}
+	n.Nodes = append(n.Nodes, newXMLNode("property", "name", key, "value", value))
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJetBrainsUpdatesWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	workspace := `<?xml version="1.0" encoding="UTF-8"?>
+<project version="4">
+  <component name="ChangeListManager">
+    <list default="true" id="1" name="Changes" comment="" />
+  </component>
+  <component name="GOROOT" url="file:///usr/local/go" />
+  <component name="PropertiesComponent"><![CDATA[{
+  "keyToString": {
+    "RunOnceActivity.ShowReadmeOnStart": "true"
+  }
+}]]></component>
+</project>
+`
+	if err := os.WriteFile(filepath.Join(dir, "workspace.xml"), []byte(workspace), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	j := &JetBrains{
+		IdeaDir:  dir,
+		GoRoot:   "/nix/store/abc-go-1.22.1/share/go",
+		NodePath: "/nix/store/def-nodejs-20.11.1/bin/node",
+		Scripts:  []string{"test", "build web"},
+	}
+	if err := j.Create(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "workspace.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		`<list default="true" id="1" name="Changes" comment=""></list>`,
+		`<component name="GOROOT" url="file:///nix/store/abc-go-1.22.1/share/go"></component>`,
+		`&#34;RunOnceActivity.ShowReadmeOnStart&#34;: &#34;true&#34;`,
+		`&#34;nodejs_interpreter_path&#34;: &#34;/nix/store/def-nodejs-20.11.1/bin/node&#34;`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got workspace.xml:\n%s\nwant it to contain:\n%s", got, want)
+		}
+	}
+	if strings.Contains(got, "/usr/local/go") {
+		t.Errorf("got workspace.xml:\n%s\nwant the old GOROOT to be replaced", got)
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, "runConfigurations", "devbox_build_web.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<option name="SCRIPT_TEXT" value="devbox run &#39;build web&#39;"></option>`; !strings.Contains(string(b), want) {
+		t.Errorf("got run configuration:\n%s\nwant it to contain:\n%s", b, want)
+	}
+
+	// Removing a script removes its run configuration.
+	j.Scripts = []string{"test"}
+	if err := j.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "runConfigurations", "devbox_build_web.xml")); !os.IsNotExist(err) {
+		t.Errorf("got err %v, want the run configuration of a removed script to be deleted", err)
+	}
+}
+
+func TestJetBrainsPropertyElements(t *testing.T) {
+	root := newXMLNode("project", "version", "4")
+	props := root.component("PropertiesComponent")
+	if err := props.setProperty("nodejs_interpreter_path", "/old/node"); err != nil {
+		t.Fatal(err)
+	}
+	if err := props.setProperty("nodejs_interpreter_path", "/nix/store/abc/bin/node"); err != nil {
+		t.Fatal(err)
+	}
+	if len(props.Nodes) != 1 || props.Nodes[0].attr("value") != "/nix/store/abc/bin/node" {
+		t.Errorf("got properties %+v, want one nodejs_interpreter_path", props.Nodes)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package generate
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"runtime/trace"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// DevPod is a Kubernetes Deployment that runs a project's image, with the
+// project on a persistent volume, so a team can use devbox on a shared
+// cluster.
+type DevPod struct {
+	// Path is the manifest file to create.
+	Path string
+
+	// Name is the project's name, which must be a valid Kubernetes name.
+	Name  string
+	Image string
+
+	// Env is the env from the project's config. Variables that refer to other ones,
+	// such as "$PWD/bin", are left out, since only devbox shell can expand
+	// them.
+	Env map[string]string
+}
+
+// envReferenceRegex matches references to other variables, such as $PWD and
+// ${HOME}, but not a $ or braces on their own, as in JSON values.
+var envReferenceRegex = regexp.MustCompile(`\$(\{[A-Za-z_]|[A-Za-z_])`)
+
+type devPodEnv struct {
+	Name  string
+	Value string
+}
+
+// CreateManifest writes the dev pod's manifest to Path.
+func (p *DevPod) CreateManifest(ctx context.Context) error {
+	defer trace.StartRegion(ctx, "createDevPodManifest").End()
+
+	data := struct {
+		Name, Image string
+		Env         []devPodEnv
+		SkippedEnv  []string
+	}{Name: p.Name, Image: p.Image}
+	keys := lo.Keys(p.Env)
+	slices.Sort(keys)
+	for _, k := range keys {
+		if v := p.Env[k]; envReferenceRegex.MatchString(v) {
+			data.SkippedEnv = append(data.SkippedEnv, k)
+		} else {
+			data.Env = append(data.Env, devPodEnv{Name: k, Value: v})
+		}
+	}
+
+	t := template.Must(template.New("k8sDevpod.yaml.tmpl").Funcs(template.FuncMap{
+		"join": strings.Join,
+		// A Go quoted string is a valid YAML double-quoted string.
+		"quote": strconv.Quote,
+	}).ParseFS(tmplFS, "tmpl/k8sDevpod.yaml.tmpl"))
+	file, err := os.Create(p.Path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer file.Close()
+	return errors.WithStack(t.Execute(file, data))
+}
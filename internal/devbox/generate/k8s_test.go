@@ -0,0 +1,71 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCreateDevPodManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devbox-devpod.yaml")
+	pod := &DevPod{
+		Path:  path,
+		Name:  "my-app",
+		Image: "registry.example.com/my-app:dev",
+		Env: map[string]string{
+			"LOG_LEVEL": "debug",
+			"GREETING":  `say "hi"`,
+			"BIN_DIR":   "$PWD/bin",
+			"FLAGS":     `{"debug": true}`,
+		},
+	}
+	if err := pod.CreateManifest(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The manifest is a PersistentVolumeClaim and a Deployment.
+	decoder := yaml.NewDecoder(strings.NewReader(string(b)))
+	var kinds []string
+	var deployment map[string]any
+	for {
+		doc := map[string]any{}
+		if err := decoder.Decode(&doc); err != nil {
+			break
+		}
+		kinds = append(kinds, doc["kind"].(string))
+		if doc["kind"] == "Deployment" {
+			deployment = doc
+		}
+	}
+	if strings.Join(kinds, ",") != "PersistentVolumeClaim,Deployment" {
+		t.Fatalf("got kinds %v in manifest:\n%s", kinds, b)
+	}
+
+	spec := deployment["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)
+	container := spec["containers"].([]any)[0].(map[string]any)
+	if got := container["image"]; got != pod.Image {
+		t.Errorf("got image %v, want %s", got, pod.Image)
+	}
+	env := map[string]any{}
+	for _, e := range container["env"].([]any) {
+		e := e.(map[string]any)
+		env[e["name"].(string)] = e["value"]
+	}
+	if len(env) != 3 || env["LOG_LEVEL"] != "debug" || env["GREETING"] != `say "hi"` || env["FLAGS"] != `{"debug": true}` {
+		t.Errorf("got env %v, want LOG_LEVEL, GREETING and FLAGS", env)
+	}
+	if !strings.Contains(string(b), "# devbox shell in the pod sets the env variables that refer to other ones:\n# BIN_DIR.") {
+		t.Errorf("got manifest:\n%s\nwant it to list BIN_DIR as skipped", b)
+	}
+}
@@ -0,0 +1,40 @@
+#!/usr/bin/env bash
+# Installs Nix and Devbox for the dev container's user. The packages in
+# devbox.json are installed by the feature's onCreateCommand, once the
+# project is in the container.
+set -euo pipefail
+
+username="${_REMOTE_USER:-root}"
+
+if ! command -v curl >/dev/null 2>&1 || ! command -v xz >/dev/null 2>&1 || ! command -v git >/dev/null 2>&1; then
+  if ! command -v apt-get >/dev/null 2>&1; then
+    echo "The devbox feature needs curl, git and xz in the image." >&2
+    exit 1
+  fi
+  apt-get update
+  DEBIAN_FRONTEND=noninteractive apt-get -y install --no-install-recommends ca-certificates curl git xz-utils
+  rm -rf /var/lib/apt/lists/*
+fi
+
+as_user() {
+  if [ "$username" = "root" ]; then
+    bash -c "$1"
+  else
+    su - "$username" -c "$1"
+  fi
+}
+
+# Install Nix in single-user mode, since containers usually don't run the
+# Nix daemon.
+if [ ! -d /nix ]; then
+  mkdir -m 0755 /nix
+  chown "$username" /nix
+  as_user "curl -fsSL https://nixos.org/nix/install | sh -s -- --no-daemon"
+fi
+
+if ! command -v devbox >/dev/null 2>&1; then
+  curl -fsSL https://get.jetpack.io/devbox | bash -s -- -f
+fi
+# Download devbox itself now, so it's in the image instead of being
+# downloaded every time a container is created.
+as_user "devbox version"
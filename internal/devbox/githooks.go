@@ -0,0 +1,132 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/ux"
+)
+
+// gitHookMarker is in every git hook that devbox writes, so it can tell them
+// apart from the hooks that the user or other tools wrote. It's followed by
+// a line with gitHookProjectPrefix and the directory of the project that
+// wrote the hook, since several projects can share a repository.
+const (
+	gitHookMarker        = "# Written by devbox install from the git_hooks in devbox.json."
+	gitHookProjectPrefix = "# Project: "
+)
+
+// installGitHooks writes a hook for each of the config's git_hooks, which
+// runs its script with devbox run, so the hook uses the project's packages.
+// It removes the hooks that it wrote before for hooks that aren't in the
+// config anymore, and leaves the other hooks alone, including the ones that
+// other projects in the repository wrote.
+func (d *Devbox) installGitHooks(ctx context.Context) error {
+	hooks := d.cfg.GitHooks
+	dir, err := gitHooksDir(ctx, d.projectDir)
+	if err != nil {
+		if len(hooks) > 0 {
+			ux.Fwarning(d.stderr, "Devbox couldn't install the git_hooks in devbox.json, because the project isn't in a git repository.\n")
+		}
+		debug.Log("not installing git hooks: %v", err)
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.WithStack(err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if _, ok := hooks[entry.Name()]; !ok && gitHookProject(path) == d.projectDir {
+			if err := os.Remove(path); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	names := lo.Keys(hooks)
+	slices.Sort(names)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			if project := gitHookProject(path); project == "" {
+				ux.Fwarning(d.stderr, "Skipping the %s git hook, because %s already exists and devbox didn't write it. Remove it to let devbox manage the hook.\n", name, path)
+				continue
+			} else if project != d.projectDir {
+				ux.Fwarning(d.stderr, "Skipping the %s git hook, because the devbox project in %s already installed it. Remove it from that project's git_hooks to let this project manage the hook.\n", name, project)
+				continue
+			}
+		}
+		script := gitHookScript(d.projectDir, hooks[name])
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			return errors.WithStack(err)
+		}
+		// WriteFile doesn't change the mode of a file that exists.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// gitHooksDir returns the directory that git runs the hooks of the repository
+// at dir from. It's .git/hooks, unless core.hooksPath is set.
+func gitHooksDir(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	// The path is relative to dir, unless it's outside the repository.
+	hooksDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	return hooksDir, nil
+}
+
+// gitHookProject returns the directory of the project that wrote the git
+// hook at path, or an empty string if devbox didn't write it.
+func gitHookProject(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(b), "\n")
+	i := slices.Index(lines, gitHookMarker)
+	if i < 0 || i+1 >= len(lines) {
+		return ""
+	}
+	if project, ok := strings.CutPrefix(lines[i+1], gitHookProjectPrefix); ok {
+		return project
+	}
+	return ""
+}
+
+// gitHookScript returns a hook that runs script, or a command, with devbox run
+// in the project at projectDir. The hook's arguments are passed on to it.
+func gitHookScript(projectDir, script string) string {
+	return fmt.Sprintf("#!/bin/sh\n%s\n%s%s\nexec devbox run --config %s %s \"$@\"\n",
+		gitHookMarker, gitHookProjectPrefix, projectDir, shellescape.Quote(projectDir), script)
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alessio/shellescape"
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+func TestInstallGitHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	projectDir := filepath.Join(repo, "app")
+	if err := os.Mkdir(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	hooksDir := filepath.Join(repo, ".git", "hooks")
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A hook that devbox didn't write, which it needs to keep.
+	userHook := "#!/bin/sh\necho mine\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte(userHook), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A hook that another project in the repository wrote, which it needs
+	// to keep too.
+	otherHook := gitHookScript(filepath.Join(repo, "app-docs"), "check")
+	if err := os.WriteFile(filepath.Join(hooksDir, "commit-msg"), []byte(otherHook), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Devbox{
+		projectDir: projectDir,
+		stderr:     io.Discard,
+		cfg: &devconfig.Config{GitHooks: map[string]string{
+			"pre-commit": "lint",
+			"pre-push":   "test",
+			"commit-msg": "lint-message",
+		}},
+	}
+	if err := d.installGitHooks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(hooksDir, "pre-commit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "exec devbox run --config " + shellescape.Quote(projectDir) + " lint \"$@\"\n"
+	if !strings.HasSuffix(string(b), want) {
+		t.Errorf("got pre-commit hook:\n%s\nwant it to end with:\n%s", b, want)
+	}
+	if b, _ := os.ReadFile(filepath.Join(hooksDir, "pre-push")); string(b) != userHook {
+		t.Errorf("got pre-push hook:\n%s\nwant the user's hook:\n%s", b, userHook)
+	}
+	if b, _ := os.ReadFile(filepath.Join(hooksDir, "commit-msg")); string(b) != otherHook {
+		t.Errorf("got commit-msg hook:\n%s\nwant the other project's hook:\n%s", b, otherHook)
+	}
+
+	// Hooks that devbox wrote are removed when they leave the config.
+	d.cfg.GitHooks = nil
+	if err := d.installGitHooks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "pre-commit")); !os.IsNotExist(err) {
+		t.Errorf("got pre-commit hook after removing it from git_hooks, want it removed (stat error %v)", err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "pre-push")); err != nil {
+		t.Errorf("got error %v for the user's pre-push hook, want it kept", err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(hooksDir, "commit-msg")); string(b) != otherHook {
+		t.Errorf("got commit-msg hook:\n%s\nwant the other project's hook kept", b)
+	}
+}
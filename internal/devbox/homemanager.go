@@ -0,0 +1,154 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/devconfig"
+	"go.jetpack.io/devbox/internal/devpkg"
+	"go.jetpack.io/devbox/internal/lock"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// ExportHomeManager writes a home-manager module with the packages, env and
+// init hook of the config. Each package comes from the nixpkgs revision in
+// devbox.lock, so the module installs the same versions as devbox.
+func (d *Devbox) ExportHomeManager(w io.Writer) error {
+	module := homeManagerModule{}
+	for _, pkg := range d.ConfigPackages() {
+		module.addPackage(pkg, d.lockfile.Get(pkg.Raw))
+	}
+	module.env = d.cfg.Env
+	if hook := d.cfg.InitHook(); hook != nil {
+		module.initHook = strings.TrimSpace(strings.ReplaceAll(hook.String(), devconfig.DefaultInitHook, ""))
+	}
+	_, err := io.WriteString(w, module.String())
+	return err
+}
+
+type homeManagerModule struct {
+	// flakes are the let bindings of the flakes that the packages come
+	// from, keyed by flake reference.
+	flakes   map[string]string
+	packages []string
+	env      map[string]string
+	initHook string
+}
+
+func (m *homeManagerModule) addPackage(pkg *devpkg.Package, locked *lock.Package) {
+	raw := pkg.Raw
+	if pkg.IsRunX() {
+		m.packages = append(m.packages, "# "+raw+" is a runx package, which home-manager can't install.")
+		return
+	}
+	if pkg.IsDevboxPackage {
+		if locked == nil || locked.Resolved == "" {
+			// Use home-manager's nixpkgs, since devbox hasn't pinned a
+			// revision yet.
+			m.packages = append(m.packages, fmt.Sprintf("pkgs.%s # %s isn't in devbox.lock", pkg.CanonicalName(), raw))
+			return
+		}
+		raw = locked.Resolved
+	}
+	installable, err := flake.ParseInstallable(raw)
+	if err != nil {
+		m.packages = append(m.packages, fmt.Sprintf("# %s isn't a flake installable: %v", pkg.Raw, err))
+		return
+	}
+
+	ref := installable.Ref.String()
+	if locked != nil && locked.NarHash != "" && installable.Ref.Type == flake.TypeGitHub {
+		sep := "?"
+		if strings.Contains(ref, "?") {
+			sep = "&"
+		}
+		ref += sep + "narHash=" + locked.NarHash
+	}
+	if m.flakes == nil {
+		m.flakes = map[string]string{}
+	}
+	name, ok := m.flakes[ref]
+	if !ok {
+		name = fmt.Sprintf("flake%d", len(m.flakes))
+		m.flakes[ref] = name
+	}
+
+	// Nixpkgs keeps its packages in legacyPackages, and other flakes in
+	// packages.
+	attrs := "packages"
+	if pkg.IsDevboxPackage || installable.Ref.ID == "nixpkgs" || installable.Ref.Repo == "nixpkgs" {
+		attrs = "legacyPackages"
+	}
+	attrPath := installable.AttrPath
+	if attrPath == "" {
+		attrPath = "default"
+	}
+	m.packages = append(m.packages, fmt.Sprintf("%s.%s.${pkgs.system}.%s # %s", name, attrs, attrPath, pkg.Raw))
+}
+
+func (m *homeManagerModule) String() string {
+	sb := &strings.Builder{}
+	sb.WriteString("# Generated by `devbox global export --format home-manager`.\n")
+	sb.WriteString("{ pkgs, ... }:\n\n")
+
+	if len(m.flakes) > 0 {
+		sb.WriteString("let\n")
+		refs := lo.Keys(m.flakes)
+		slices.SortFunc(refs, func(a, b string) int { return strings.Compare(m.flakes[a], m.flakes[b]) })
+		for _, ref := range refs {
+			fmt.Fprintf(sb, "  %s = builtins.getFlake %s;\n", m.flakes[ref], nixString(ref))
+		}
+		sb.WriteString("in\n")
+	}
+	sb.WriteString("{\n")
+	sb.WriteString("  home.packages = [\n")
+	for _, pkg := range m.packages {
+		fmt.Fprintf(sb, "    %s\n", pkg)
+	}
+	sb.WriteString("  ];\n")
+
+	if len(m.env) > 0 {
+		sb.WriteString("\n  home.sessionVariables = {\n")
+		keys := lo.Keys(m.env)
+		slices.Sort(keys)
+		for _, k := range keys {
+			fmt.Fprintf(sb, "    %s = %s;\n", nixString(k), nixString(m.env[k]))
+		}
+		sb.WriteString("  };\n")
+	}
+
+	if m.initHook != "" {
+		hook := nixIndentedString(m.initHook, "    ")
+		sb.WriteString("\n  # The init hook of devbox global.\n")
+		fmt.Fprintf(sb, "  programs.bash.initExtra = %s;\n", hook)
+		fmt.Fprintf(sb, "  programs.zsh.initExtra = %s;\n", hook)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// nixString returns s as a Nix string literal.
+func nixString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// nixIndentedString returns s as a Nix indented string, with each line
+// indented by indent.
+func nixIndentedString(s, indent string) string {
+	r := strings.NewReplacer("''", "'''", "${", "''${")
+	lines := strings.Split(r.Replace(s), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return "''\n" + strings.Join(lines, "\n") + "\n" + indent[:max(len(indent)-2, 0)] + "''"
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHomeManagerModule(t *testing.T) {
+	m := homeManagerModule{
+		flakes: map[string]string{
+			"github:NixOS/nixpkgs/75a5?narHash=sha256-abc": "flake0",
+			"github:numtide/treefmt":                       "flake1",
+		},
+		packages: []string{
+			"flake0.legacyPackages.${pkgs.system}.ripgrep # ripgrep@14",
+			"flake1.packages.${pkgs.system}.default # github:numtide/treefmt",
+		},
+		env:      map[string]string{"EDITOR": "vim", "PROMPT": `"${USER}"`},
+		initHook: "alias ll='ls -l'\necho ''",
+	}
+	want := `# Generated by ` + "`devbox global export --format home-manager`" + `.
+{ pkgs, ... }:
+
+let
+  flake0 = builtins.getFlake "github:NixOS/nixpkgs/75a5?narHash=sha256-abc";
+  flake1 = builtins.getFlake "github:numtide/treefmt";
+in
+{
+  home.packages = [
+    flake0.legacyPackages.${pkgs.system}.ripgrep # ripgrep@14
+    flake1.packages.${pkgs.system}.default # github:numtide/treefmt
+  ];
+
+  home.sessionVariables = {
+    "EDITOR" = "vim";
+    "PROMPT" = "\"\${USER}\"";
+  };
+
+  # The init hook of devbox global.
+  programs.bash.initExtra = ''
+    alias ll='ls -l'
+    echo '''
+  '';
+  programs.zsh.initExtra = ''
+    alias ll='ls -l'
+    echo '''
+  '';
+}
+`
+	if diff := cmp.Diff(want, m.String()); diff != "" {
+		t.Errorf("wrong module (-want +got):\n%s", diff)
+	}
+}
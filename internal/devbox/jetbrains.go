@@ -0,0 +1,81 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"path/filepath"
+	"runtime/trace"
+	"slices"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/devbox/generate"
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/ux"
+)
+
+// GenerateJetBrains points a JetBrains IDE's Go SDK and Node.js interpreter
+// at the project's packages, and adds a run configuration for each script.
+// The IDE doesn't let a project choose a Python interpreter by path, so it
+// prints the interpreter to add instead.
+func (d *Devbox) GenerateJetBrains(ctx context.Context) error {
+	ctx, task := trace.NewTask(ctx, "devboxGenerateJetBrains")
+	defer task.End()
+
+	env, err := d.ensureStateIsUpToDateAndComputeEnv(ctx)
+	if err != nil {
+		return err
+	}
+	scripts := d.ListScripts()
+	slices.Sort(scripts)
+	gen := &generate.JetBrains{
+		IdeaDir: filepath.Join(d.projectDir, ".idea"),
+		Scripts: scripts,
+	}
+	// Go's bin directory is in its GOROOT.
+	if goBin := nixStoreTool("go", env["PATH"]); goBin != "" {
+		gen.GoRoot = filepath.Dir(filepath.Dir(goBin))
+	}
+	gen.NodePath = nixStoreTool("node", env["PATH"])
+	if err := gen.Create(); err != nil {
+		return err
+	}
+
+	python := ""
+	if venv := filepath.Join(env["VENV_DIR"], "bin", "python"); env["VENV_DIR"] != "" && fileutil.Exists(venv) {
+		python = venv
+	} else {
+		python = nixStoreTool("python3", env["PATH"])
+	}
+
+	ux.Fsuccess(d.stderr, "Updated the JetBrains configuration in .idea/\n")
+	if gen.GoRoot != "" {
+		ux.Finfo(d.stderr, "Go SDK: %s\n", gen.GoRoot)
+	}
+	if gen.NodePath != "" {
+		ux.Finfo(d.stderr, "Node.js interpreter: %s\n", gen.NodePath)
+	}
+	if python != "" {
+		ux.Finfo(
+			d.stderr,
+			"To use the project's Python, add %s as an existing interpreter in Settings > Python Interpreter.\n",
+			python,
+		)
+	}
+	return nil
+}
+
+// nixStoreTool returns the path in the Nix store of a program in the devbox
+// environment, or an empty string if the program isn't a package's.
+func nixStoreTool(name, path string) string {
+	bin, err := lookPathIn(name, path)
+	if err != nil {
+		return ""
+	}
+	resolved, err := filepath.EvalSymlinks(bin)
+	if err != nil || !strings.HasPrefix(resolved, "/nix/store/") {
+		return ""
+	}
+	return resolved
+}
@@ -82,5 +82,6 @@ func (d *Devbox) syncNixProfileFromFlake(ctx context.Context) error {
 			}
 		}
 	}
+	d.syncGCRoots(ctx, wantStorePaths)
 	return nil
 }
@@ -0,0 +1,53 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"fmt"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+// checkOfflinePackages returns an error that lists the packages devbox can't
+// install without the network: packages that aren't locked yet, and
+// packages whose store path isn't in the Nix store. Checking them up front
+// fails fast, instead of waiting for nix to fail on each one.
+//
+// Packages that devbox.lock has no store path for, such as flakes, can't be
+// checked. Nix reports them if they're missing.
+func (d *Devbox) checkOfflinePackages() error {
+	var missing []string
+	for _, pkg := range d.InstallablePackages() {
+		if !pkg.IsDevboxPackage {
+			continue
+		}
+		entry := d.lockfile.Get(pkg.Raw)
+		if entry == nil {
+			lockfile := "devbox.lock"
+			if pkg.IsLocal {
+				lockfile = "devbox.local.lock"
+			}
+			missing = append(missing, fmt.Sprintf("%s (not in %s)", pkg.Raw, lockfile))
+			continue
+		}
+		sysInfo := entry.Systems[nix.System()]
+		if sysInfo == nil || sysInfo.StorePath == "" {
+			continue
+		}
+		if !fileutil.Exists(sysInfo.StorePath) {
+			missing = append(missing, fmt.Sprintf("%s (%s)", sysInfo.StorePath, pkg.Raw))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return usererr.New(
+		"Devbox is offline, and can't install these packages:\n\n  %s\n\n"+
+			"Run `devbox install` while you're online to download them.",
+		strings.Join(missing, "\n  "),
+	)
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+	"go.jetpack.io/devbox/internal/lock"
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+func TestCheckOfflinePackages(t *testing.T) {
+	t.Setenv("__DEVBOX_NIX_SYSTEM", "x86_64-linux")
+	path := t.TempDir()
+	err := os.WriteFile(filepath.Join(path, "devbox.json"), []byte(`{
+  "packages": ["hello@2.12", "go@1.21", "ripgrep@14"]
+}`), 0o644)
+	require.NoError(t, err)
+
+	d, err := Open(&devopt.Opts{Dir: path, Stderr: os.Stderr})
+	require.NoError(t, err)
+	sys := nix.System()
+	d.lockfile.Packages = map[string]*lock.Package{
+		"hello@2.12": {
+			Resolved: "github:NixOS/nixpkgs/0123456789abcdef0123456789abcdef01234567#hello",
+			Systems: map[string]*lock.SystemInfo{
+				sys: {StorePath: "/nix/store/00000000000000000000000000000000-hello-2.12"},
+			},
+		},
+		"ripgrep@14": {
+			Resolved: "github:NixOS/nixpkgs/0123456789abcdef0123456789abcdef01234567#ripgrep",
+			Systems: map[string]*lock.SystemInfo{
+				sys: {StorePath: path},
+			},
+		},
+	}
+
+	err = d.checkOfflinePackages()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "/nix/store/00000000000000000000000000000000-hello-2.12 (hello@2.12)")
+	require.Contains(t, err.Error(), "go@1.21 (not in devbox.lock)")
+	require.NotContains(t, err.Error(), "ripgrep")
+}
+
+func TestCheckOfflineLocalPackages(t *testing.T) {
+	t.Setenv("__DEVBOX_NIX_SYSTEM", "x86_64-linux")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := t.TempDir()
+	err := os.WriteFile(filepath.Join(path, "devbox.json"), []byte(`{"packages": []}`), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(path, "devbox.local.json"), []byte(`{
+  "packages": ["ripgrep@14", "jq@1.7"]
+}`), 0o644)
+	require.NoError(t, err)
+	// ripgrep was locked by an earlier devbox install, in a new process.
+	err = os.WriteFile(filepath.Join(path, "devbox.local.lock"), []byte(`{
+  "lockfile_version": "1",
+  "packages": {
+    "ripgrep@14": {
+      "resolved": "github:NixOS/nixpkgs/0123456789abcdef0123456789abcdef01234567#ripgrep",
+      "systems": {"x86_64-linux": {"store_path": "`+path+`"}}
+    }
+  }
+}`), 0o644)
+	require.NoError(t, err)
+
+	d, err := Open(&devopt.Opts{Dir: path, Stderr: os.Stderr})
+	require.NoError(t, err)
+
+	err = d.checkOfflinePackages()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "jq@1.7 (not in devbox.local.lock)")
+	require.NotContains(t, err.Error(), "ripgrep")
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"runtime/trace"
+
+	"go.jetpack.io/devbox/internal/devpkg"
+	"go.jetpack.io/devbox/internal/lock"
+	"go.jetpack.io/devbox/internal/searcher"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// OutdatedPackage is a package in devbox.lock that has a newer version.
+type OutdatedPackage struct {
+	// Name is the package's name in devbox.json.
+	Name string `json:"name"`
+
+	// Current is the version in devbox.lock. For flakes and packages from a
+	// nixpkgs channel it's the locked revision.
+	Current string `json:"current"`
+
+	// Wanted is the newest version that devbox.json allows, which
+	// `devbox upgrade` or `devbox update` installs.
+	Wanted string `json:"wanted"`
+
+	// Latest is the newest version of the package, which may need a change
+	// to devbox.json, such as go@1.21 to go@1.22.
+	Latest string `json:"latest"`
+}
+
+// Outdated compares every package in devbox.lock against the newest versions
+// available and returns the packages that have a newer version. It doesn't
+// change devbox.lock.
+func (d *Devbox) Outdated(ctx context.Context) ([]OutdatedPackage, error) {
+	_, task := trace.NewTask(ctx, "devboxOutdated")
+	defer task.End()
+
+	outdated := []OutdatedPackage{}
+	for _, pkg := range d.ConfigPackages() {
+		existing := d.lockfile.Get(pkg.Raw)
+		if existing == nil {
+			continue
+		}
+		result, err := d.outdatedPackage(pkg, existing)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil && (result.Current != result.Wanted || result.Current != result.Latest) {
+			outdated = append(outdated, *result)
+		}
+	}
+	return outdated, nil
+}
+
+// outdatedPackage returns the current, wanted, and latest versions of a
+// package, or nil if devbox can't upgrade it, such as a local flake or a
+// legacy package without a version.
+func (d *Devbox) outdatedPackage(pkg *devpkg.Package, existing *lock.Package) (*OutdatedPackage, error) {
+	if pkg.Channel() != "" || existing.IsFlake() {
+		var resolved *lock.Package
+		var err error
+		if pkg.Channel() != "" {
+			resolved, err = d.lockfile.FetchResolvedChannel(pkg.Raw, pkg.Channel())
+		} else {
+			resolved, err = d.lockfile.FetchResolvedFlake(pkg.Raw)
+		}
+		if err != nil {
+			return nil, err
+		}
+		latest := lockedRevision(resolved)
+		return &OutdatedPackage{
+			Name:    pkg.Raw,
+			Current: lockedRevision(existing),
+			Wanted:  latest,
+			Latest:  latest,
+		}, nil
+	}
+
+	name, _, isVersioned := searcher.ParseVersionedPackage(pkg.Raw)
+	if !isVersioned {
+		return nil, nil
+	}
+	wanted, err := d.lockfile.FetchResolvedPackage(pkg.Raw)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := d.lockfile.FetchResolvedPackage(name + "@latest")
+	if err != nil {
+		return nil, err
+	}
+	return &OutdatedPackage{
+		Name:    pkg.Raw,
+		Current: existing.Version,
+		Wanted:  wanted.Version,
+		Latest:  latest.Version,
+	}, nil
+}
+
+// lockedRevision returns the short commit hash that a flake in devbox.lock is
+// pinned to.
+func lockedRevision(entry *lock.Package) string {
+	installable, err := flake.ParseInstallable(entry.Resolved)
+	if err != nil || installable.Ref.Rev == "" {
+		return entry.Resolved
+	}
+	return installable.Ref.Rev[:min(len(installable.Ref.Rev), 7)]
+}
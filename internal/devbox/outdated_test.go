@@ -0,0 +1,23 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"testing"
+
+	"go.jetpack.io/devbox/internal/lock"
+)
+
+func TestLockedRevision(t *testing.T) {
+	cases := map[string]string{
+		"github:NixOS/nixpkgs/5233fd2ba76a3accb5aaa999c00509a11fd0793c#hello": "5233fd2",
+		"github:numtide/devshell/4ad8492f6d1d8fb1b4c1a1c3e0e1e5c8a9f7b6d5":    "4ad8492",
+		"github:numtide/devshell": "github:numtide/devshell",
+	}
+	for resolved, want := range cases {
+		if got := lockedRevision(&lock.Package{Resolved: resolved}); got != want {
+			t.Errorf("got lockedRevision(%q) = %q, want %q", resolved, got, want)
+		}
+	}
+}
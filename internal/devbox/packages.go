@@ -44,6 +44,20 @@ func (d *Devbox) Add(ctx context.Context, pkgsNames []string, opts devopt.AddOpt
 	// Track which packages had no changes so we can report that to the user.
 	unchangedPackageNames := []string{}
 
+	// Names like openssl.dev add the package with the dev output selected.
+	outputsByName := map[string][]string{}
+	pkgsNames = slices.Clone(pkgsNames)
+	for i, name := range pkgsNames {
+		pkg, outputs := devconfig.SplitOutputSuffix(name)
+		if outputs != nil {
+			pkgsNames[i] = pkg
+			outputsByName[pkg] = outputs
+		}
+	}
+	// selectedOutputs are the outputs selected with names like openssl.dev,
+	// keyed by the name the package is added to devbox.json with.
+	selectedOutputs := map[string][]string{}
+
 	// Only add packages that are not already in config. If same canonical exists,
 	// replace it.
 	pkgs := devpkg.PackagesFromStringsWithOptions(lo.Uniq(pkgsNames), d.lockfile, opts)
@@ -59,8 +73,12 @@ func (d *Devbox) Add(ctx context.Context, pkgsNames []string, opts devopt.AddOpt
 		if slices.Contains(existingPackageNames, pkg.Versioned()) {
 			// But we still need to add to addedPackageNames. See its comment.
 			addedPackageNames = append(addedPackageNames, pkg.Versioned())
-			unchangedPackageNames = append(unchangedPackageNames, pkg.Versioned())
 			ux.Finfo(d.stderr, "Package %q already in devbox.json\n", pkg.Versioned())
+			if outputs := outputsByName[pkg.Raw]; outputs != nil {
+				selectedOutputs[pkg.Versioned()] = outputs
+			} else {
+				unchangedPackageNames = append(unchangedPackageNames, pkg.Versioned())
+			}
 			continue
 		}
 
@@ -100,12 +118,21 @@ func (d *Devbox) Add(ctx context.Context, pkgsNames []string, opts devopt.AddOpt
 		ux.Finfo(d.stderr, "Adding package %q to devbox.json\n", packageNameForConfig)
 		d.cfg.Packages.Add(packageNameForConfig)
 		addedPackageNames = append(addedPackageNames, packageNameForConfig)
+		selectedOutputs[packageNameForConfig] = outputsByName[pkg.Raw]
 	}
 
 	// Options must be set before ensureStateIsUpToDate. See comment in function
 	if err := d.setPackageOptions(addedPackageNames, opts); err != nil {
 		return err
 	}
+	for name, outputs := range selectedOutputs {
+		if len(outputs) == 0 {
+			continue
+		}
+		if err := d.cfg.Packages.SetOutputs(d.stderr, name, outputs); err != nil {
+			return err
+		}
+	}
 
 	if err := d.ensureStateIsUpToDate(ctx, install); err != nil {
 		return usererr.WithUserMessage(err, "There was an error installing nix packages")
@@ -260,6 +287,12 @@ func (d *Devbox) ensureStateIsUpToDate(ctx context.Context, mode installMode) er
 		fmt.Fprintln(d.stderr, "Ensuring packages are installed.")
 	}
 
+	if nix.IsOffline() && !upToDate {
+		if err := d.checkOfflinePackages(); err != nil {
+			return err
+		}
+	}
+
 	if mode == install || mode == update || mode == ensure {
 		if err := d.installPackages(ctx); err != nil {
 			return err
@@ -338,7 +371,13 @@ func (d *Devbox) recomputeState(ctx context.Context) error {
 		return err
 	}
 
-	return d.syncNixProfileFromFlake(ctx)
+	if err := d.syncNixProfileFromFlake(ctx); err != nil {
+		return err
+	}
+
+	// Evaluating the flake locked its inputs, so devbox.lock can record
+	// their narHashes. updateLockfile saves them.
+	return d.lockfile.SetNarHashes(filepath.Join(d.flakeDir(), "flake.lock"))
 }
 
 func (d *Devbox) profilePath() (string, error) {
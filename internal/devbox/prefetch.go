@@ -0,0 +1,45 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"runtime/trace"
+
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+// Prefetch downloads or builds everything the project's environment needs,
+// without starting a shell: the packages, the shell environment, the sources
+// of the generated flake's inputs, and process-compose if the project has
+// services. Unlike Install, it doesn't skip anything when devbox thinks the
+// environment is up to date, so it also repairs a store that was garbage
+// collected or a .devbox directory that was restored from a cache.
+func (d *Devbox) Prefetch(ctx context.Context) error {
+	ctx, task := trace.NewTask(ctx, "devboxPrefetch")
+	defer task.End()
+
+	if err := d.installPackages(ctx); err != nil {
+		return err
+	}
+	if err := d.recomputeState(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintln(d.stderr, "Fetching flake inputs.")
+	if err := nix.FetchFlakeInputs(ctx, "path:"+d.flakeDir()); err != nil {
+		return err
+	}
+
+	svcs, err := d.Services()
+	if err != nil {
+		return err
+	}
+	if len(svcs) > 0 {
+		if _, err := d.ensureProcessCompose(ctx); err != nil {
+			return err
+		}
+	}
+	return d.updateLockfile(true /*recomputeState*/)
+}
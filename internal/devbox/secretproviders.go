@@ -0,0 +1,102 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/devconfig"
+	"go.jetpack.io/devbox/internal/envir"
+)
+
+// secretProviderTools are the CLIs that read the secrets of each provider,
+// and the packages that have them.
+var secretProviderTools = map[string]struct{ name, tool, pkg string }{
+	devconfig.SecretProvider1Password: {"1Password", "op", "_1password-cli"},
+	devconfig.SecretProviderVault:     {"Vault", "vault", "vault"},
+	devconfig.SecretProviderSops:      {"sops", "sops", "sops"},
+}
+
+// secretEnvs reads the values of the config's secrets with their providers'
+// CLIs, which run with env so they can be packages of the project. It reads
+// them once, since some providers ask the user to unlock them.
+func (d *Devbox) secretEnvs(ctx context.Context, env map[string]string) (map[string]string, error) {
+	if d.secretEnv != nil || len(d.cfg.Secrets) == 0 {
+		return d.secretEnv, nil
+	}
+	names := lo.Keys(d.cfg.Secrets)
+	slices.Sort(names)
+	secrets := make(map[string]string, len(names))
+	for _, name := range names {
+		ref, err := devconfig.ParseSecretRef(d.cfg.Secrets[name])
+		if err != nil {
+			return nil, err
+		}
+		provider := secretProviderTools[ref.Provider]
+		tool, err := lookPathIn(provider.tool, env["PATH"])
+		if err != nil {
+			return nil, usererr.New(
+				"The secret %s is in %s, which needs the %s command. Run `devbox add %s` to add it to your project.",
+				name, provider.name, provider.tool, provider.pkg)
+		}
+
+		cmd := exec.CommandContext(ctx, tool, secretProviderArgs(ref)...)
+		cmd.Dir = d.projectDir
+		cmd.Env = envir.MapToPairs(env)
+		stderr := &bytes.Buffer{}
+		cmd.Stderr = stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, usererr.WithUserMessage(err,
+				"Devbox couldn't read the secret %s from %s: %s", name, provider.name, strings.TrimSpace(stderr.String()))
+		}
+		secrets[name] = strings.TrimSuffix(string(out), "\n")
+		debug.Log("secrets: read %s from %s", name, provider.name)
+	}
+	d.secretEnv = secrets
+	return secrets, nil
+}
+
+// secretProviderArgs returns the arguments of the provider's CLI that print
+// the value of ref.
+func secretProviderArgs(ref devconfig.SecretRef) []string {
+	switch ref.Provider {
+	case devconfig.SecretProvider1Password:
+		return []string{"read", "--no-newline", ref.Path}
+	case devconfig.SecretProviderVault:
+		return []string{"kv", "get", "-field=" + ref.Key, ref.Path}
+	default:
+		// sops extracts a key with a path of the form ["db"]["password"],
+		// where indexes of arrays aren't quoted.
+		extract := strings.Builder{}
+		for _, part := range strings.Split(ref.Key, ".") {
+			if _, err := strconv.Atoi(part); err == nil {
+				fmt.Fprintf(&extract, "[%s]", part)
+			} else {
+				fmt.Fprintf(&extract, "[%s]", strconv.Quote(part))
+			}
+		}
+		return []string{"--decrypt", "--extract", extract.String(), filepath.FromSlash(ref.Path)}
+	}
+}
+
+// withoutSecrets returns env without the config's secrets, for the files that
+// devbox writes the environment to.
+func (d *Devbox) withoutSecrets(env map[string]string) map[string]string {
+	if d.cfg == nil || len(d.cfg.Secrets) == 0 {
+		return env
+	}
+	return lo.OmitByKeys(env, lo.Keys(d.cfg.Secrets))
+}
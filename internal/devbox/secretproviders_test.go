@@ -0,0 +1,68 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+func TestSecretProviderArgs(t *testing.T) {
+	testCases := []struct {
+		ref  string
+		want []string
+	}{
+		{"op://dev/db/password", []string{"read", "--no-newline", "op://dev/db/password"}},
+		{"vault://secret/app#token", []string{"kv", "get", "-field=token", "secret/app"}},
+		{"sops://secrets.enc.yaml#db.hosts.0", []string{"--decrypt", "--extract", `["db"]["hosts"][0]`, "secrets.enc.yaml"}},
+	}
+	for _, tc := range testCases {
+		ref, err := devconfig.ParseSecretRef(tc.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := secretProviderArgs(ref); !slices.Equal(got, tc.want) {
+			t.Errorf("got args %q for %s, want %q", got, tc.ref, tc.want)
+		}
+	}
+}
+
+func TestSecretEnvs(t *testing.T) {
+	// A fake vault that prints the field that it's asked for.
+	bin := t.TempDir()
+	vault := "#!/bin/sh\nif [ \"$3\" = -field=token ] && [ \"$4\" = secret/app ]; then echo s3cret; else exit 1; fi\n"
+	if err := os.WriteFile(filepath.Join(bin, "vault"), []byte(vault), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Devbox{
+		projectDir: t.TempDir(),
+		cfg:        &devconfig.Config{Secrets: map[string]string{"API_TOKEN": "vault://secret/app#token"}},
+	}
+	env := map[string]string{"PATH": bin + ":/bin:/usr/bin"}
+	got, err := d.secretEnvs(context.Background(), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["API_TOKEN"] != "s3cret" {
+		t.Errorf("got API_TOKEN %q, want %q", got["API_TOKEN"], "s3cret")
+	}
+
+	// The shellrc doesn't get the secrets.
+	shellEnv := map[string]string{"API_TOKEN": "s3cret", "EDITOR": "vim"}
+	if exported := d.withoutSecrets(shellEnv); len(exported) != 1 || exported["EDITOR"] != "vim" {
+		t.Errorf("got env without secrets %v, want only EDITOR", exported)
+	}
+
+	d.cfg.Secrets = map[string]string{"API_TOKEN": "op://dev/api/token"}
+	d.secretEnv = nil
+	if _, err := d.secretEnvs(context.Background(), map[string]string{"PATH": bin}); err == nil {
+		t.Error("got no error for a 1Password secret without op, want one")
+	}
+}
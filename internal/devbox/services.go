@@ -0,0 +1,172 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/samber/lo"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/services"
+)
+
+// ServiceLogs writes the last tail lines of the services' output to w, or of
+// all the project's services if serviceNames is empty. If follow is true, it
+// keeps writing new lines until ctx is done.
+func (d *Devbox) ServiceLogs(
+	ctx context.Context, w io.Writer, tail int, follow bool, serviceNames ...string,
+) error {
+	if !services.ProcessManagerIsRunning(d.projectDir) {
+		return usererr.New("Process-compose isn't running. Run `devbox services up` to start your services.")
+	}
+	serviceNames, err := d.serviceNamesOrAll(serviceNames)
+	if err != nil {
+		return err
+	}
+	return services.FollowLogs(ctx, w, d.projectDir, serviceNames, tail, follow)
+}
+
+// WaitForServices blocks until the services are ready, or all the project's
+// services if serviceNames is empty.
+func (d *Devbox) WaitForServices(ctx context.Context, timeout time.Duration, serviceNames ...string) error {
+	if !services.ProcessManagerIsRunning(d.projectDir) {
+		return usererr.New("Process-compose isn't running. Run `devbox services up -b` to start your services.")
+	}
+	serviceNames, err := d.serviceNamesOrAll(serviceNames)
+	if err != nil {
+		return err
+	}
+	return services.WaitReady(ctx, d.stderr, d.projectDir, serviceNames, timeout)
+}
+
+// ServiceStatus writes a table of the project's services to w, with their
+// pid, uptime, health, and the ports they listen on.
+func (d *Devbox) ServiceStatus(ctx context.Context, w io.Writer) error {
+	if !services.ProcessManagerIsRunning(d.projectDir) {
+		return usererr.New("Process-compose isn't running. Run `devbox services up` to start your services.")
+	}
+	procs, err := services.ListServices(ctx, d.projectDir, d.stderr)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(procs, func(a, b services.Process) int { return strings.Compare(a.Name, b.Name) })
+	pids := []int{}
+	for _, p := range procs {
+		if p.IsActive() && p.Pid > 0 {
+			pids = append(pids, p.Pid)
+		}
+	}
+	ports := services.ListeningPorts(pids)
+
+	tw := tabwriter.NewWriter(w, 3, 2, 4, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tHEALTH\tPID\tUPTIME\tRESTARTS\tPORTS")
+	for _, p := range procs {
+		pid, uptime, portList := "-", "-", "-"
+		if p.IsActive() && p.Pid > 0 {
+			pid = strconv.Itoa(p.Pid)
+			if p.Uptime != "" {
+				uptime = p.Uptime
+			}
+			if len(ports[p.Pid]) > 0 {
+				portList = strings.Join(lo.Map(ports[p.Pid], func(port, _ int) string { return strconv.Itoa(port) }), ",")
+			}
+		}
+		health := lo.Ternary(p.Health == "", "-", p.Health)
+		status := p.Status
+		if p.Status == types.ProcessStateCompleted || p.Status == types.ProcessStateError {
+			status = fmt.Sprintf("%s (%d)", p.Status, p.ExitCode)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", p.Name, status, health, pid, uptime, p.Restarts, portList)
+	}
+	return tw.Flush()
+}
+
+// AttachService writes the output of a service to w as it runs, until it
+// stops or ctx is done.
+func (d *Devbox) AttachService(ctx context.Context, w io.Writer, serviceName string) error {
+	if !services.ProcessManagerIsRunning(d.projectDir) {
+		return usererr.New("Process-compose isn't running. Run `devbox services up -b` to start your services.")
+	}
+	if _, err := d.serviceNamesOrAll([]string{serviceName}); err != nil {
+		return err
+	}
+	fmt.Fprintf(d.stderr, "Attached to %s. Press Ctrl-C to detach, which leaves the service running.\n", serviceName)
+	proc, err := services.Attach(ctx, w, d.projectDir, serviceName, 50)
+	if err != nil || proc == nil {
+		return err
+	}
+	fmt.Fprintf(d.stderr, "Service %s is %s with exit code %d.\n", serviceName, strings.ToLower(proc.Status), proc.ExitCode)
+	return nil
+}
+
+// ResetService deletes the data of a plugin's service, such as a database,
+// so it starts over from an empty state the next time it starts. The
+// service must be stopped.
+func (d *Devbox) ResetService(ctx context.Context, serviceName string) error {
+	svcSet, err := d.Services()
+	if err != nil {
+		return err
+	}
+	svc, ok := svcSet[serviceName]
+	if !ok {
+		return usererr.New("Service %s not found in your project", serviceName)
+	}
+	if svc.StateDir == "" {
+		return usererr.New(
+			"Service %s isn't provided by a plugin, so devbox doesn't know where it keeps its data.",
+			serviceName,
+		)
+	}
+	if services.ProcessManagerIsRunning(d.projectDir) {
+		procs, err := services.ListServices(ctx, d.projectDir, d.stderr)
+		if err != nil {
+			return err
+		}
+		for _, p := range procs {
+			if p.Name == serviceName && p.IsActive() {
+				return usererr.New(
+					"Service %s is running. Stop it with `devbox services stop %s` before resetting it.",
+					serviceName, serviceName,
+				)
+			}
+		}
+	}
+
+	found, err := d.pluginManager.ResetServiceState(d.InstallablePackages(), d.cfg.Include, serviceName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return usererr.New("Service %s not found in your project's plugins", serviceName)
+	}
+	fmt.Fprintf(d.stderr, "Deleted the data of %s in %s\n", serviceName, svc.StateDir)
+	return nil
+}
+
+// serviceNamesOrAll checks that serviceNames are services in the project, and
+// returns the names of all the services if it's empty.
+func (d *Devbox) serviceNamesOrAll(serviceNames []string) ([]string, error) {
+	svcSet, err := d.Services()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range serviceNames {
+		if _, ok := svcSet[name]; !ok {
+			return nil, usererr.New("Service %s not found in your project", name)
+		}
+	}
+	if len(serviceNames) == 0 {
+		serviceNames = lo.Keys(svcSet)
+		slices.Sort(serviceNames)
+	}
+	return serviceNames, nil
+}
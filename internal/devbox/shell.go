@@ -20,9 +20,12 @@ import (
 	"go.jetpack.io/devbox/internal/shellgen"
 	"go.jetpack.io/devbox/internal/telemetry"
 
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
 	"go.jetpack.io/devbox/internal/debug"
 	"go.jetpack.io/devbox/internal/envir"
+	"go.jetpack.io/devbox/internal/fileutil"
 	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/internal/plugin"
 	"go.jetpack.io/devbox/internal/xdg"
 )
 
@@ -62,6 +65,9 @@ type DevboxShell struct {
 
 	historyFile string
 
+	// pluginFragments are what plugins add to the shellrc.
+	pluginFragments []plugin.ShellFragment
+
 	// shellStartTime is the unix timestamp for when the command was invoked
 	shellStartTime time.Time
 }
@@ -95,6 +101,11 @@ func shellPath(devbox *Devbox) (path string, err error) {
 		}
 	}()
 
+	// A shell chosen by the project takes precedence over the user's shell.
+	if program := devbox.cfg.ShellProgram(); program != "" {
+		return projectShellPath(devbox, program)
+	}
+
 	if !devbox.pure {
 		// First, check the SHELL environment variable.
 		path = os.Getenv(envir.Shell)
@@ -105,14 +116,19 @@ func shellPath(devbox *Devbox) (path string, err error) {
 	}
 
 	// Second, fallback to using the bash that nix uses by default.
+	return nixBashPath(devbox)
+}
 
+// nixBashPath returns the path to bashInteractive from the project's nixpkgs,
+// building it if it isn't in the nix store yet.
+func nixBashPath(devbox *Devbox) (string, error) {
 	var bashNixStorePath string // of the form /nix/store/{hash}-bash-{version}/
 
 	cmd := exec.Command(
 		"nix", "eval", "--raw",
 		fmt.Sprintf("%s#bashInteractive", nix.FlakeNixpkgs(devbox.cfg.NixPkgsCommitHash())),
 	)
-	cmd.Args = append(cmd.Args, nix.ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, nix.Flags()...)
 	out, err := cmd.Output()
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -121,7 +137,7 @@ func shellPath(devbox *Devbox) (path string, err error) {
 
 	// install bashInteractive in nix/store without creating a symlink to local directory (--no-link)
 	cmd = exec.Command("nix", "build", bashNixStorePath, "--no-link")
-	cmd.Args = append(cmd.Args, nix.ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, nix.Flags()...)
 	err = cmd.Run()
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -136,6 +152,37 @@ func shellPath(devbox *Devbox) (path string, err error) {
 	return "", ErrNoRecognizableShellFound
 }
 
+// projectShellPath finds the shell program set in devbox.json. A program name
+// is looked up in the project's packages first, and then in PATH. If the
+// program is bash and it isn't installed, it uses the bash from nixpkgs.
+func projectShellPath(devbox *Devbox, program string) (string, error) {
+	if filepath.IsAbs(program) {
+		if !fileutil.Exists(program) {
+			return "", usererr.New("The shell.program %q in devbox.json does not exist.", program)
+		}
+		return program, nil
+	}
+
+	path := filepath.Join(nix.ProfileBinPath(devbox.projectDir), program)
+	if fileutil.Exists(path) {
+		debug.Log("Using shell.program from the project's packages: %s\n", path)
+		return path, nil
+	}
+	path, err := exec.LookPath(program)
+	if err == nil {
+		debug.Log("Using shell.program from PATH: %s\n", path)
+		return path, nil
+	}
+	if program == "bash" {
+		return nixBashPath(devbox)
+	}
+	return "", usererr.New(
+		"Couldn't find the shell.program %q from devbox.json. Add it to your "+
+			"packages with `devbox add %s`, or install it on your machine.",
+		program, program,
+	)
+}
+
 // initShellBinaryFields initializes the fields specific to the shell binary that will be used
 // for the devbox shell.
 func initShellBinaryFields(path string) *DevboxShell {
@@ -196,6 +243,12 @@ func WithProjectDir(projectDir string) ShellOption {
 	}
 }
 
+func WithPluginShellFragments(fragments []plugin.ShellFragment) ShellOption {
+	return func(s *DevboxShell) {
+		s.pluginFragments = fragments
+	}
+}
+
 func WithShellStartTime(t time.Time) ShellOption {
 	return func(s *DevboxShell) {
 		s.shellStartTime = t
@@ -310,6 +363,41 @@ func (s *DevboxShell) writeDevboxShellrc() (path string, err error) {
 		}
 	}()
 
+	// The message is written to its own file so that it doesn't need to be
+	// quoted differently for each shell.
+	motdPath := ""
+	if motd := strings.TrimSpace(s.devbox.cfg.ShellMotd()); motd != "" {
+		motdPath = filepath.Join(tmp, "motd")
+		motd = s.devbox.TemplateVars().Expand(motd) + "\n"
+		if err := os.WriteFile(motdPath, []byte(motd), 0o644); err != nil {
+			return "", fmt.Errorf("write shell motd file: %v", err)
+		}
+	}
+
+	// Each plugin's fragment only has the completion script for this
+	// shell.
+	type pluginFragment struct {
+		Plugin     string
+		Completion string
+		RC         []string
+	}
+	pluginFragments := []pluginFragment{}
+	for _, f := range s.pluginFragments {
+		completion := f.Completions[string(s.name)]
+		if completion == "" && len(f.RC) == 0 {
+			continue
+		}
+		pluginFragments = append(pluginFragments, pluginFragment{
+			Plugin:     f.Plugin,
+			Completion: completion,
+			RC:         f.RC,
+		})
+	}
+
+	// The shell gets the secrets from its environment, so they aren't
+	// written to the shellrc.
+	exportEnv := exportify(s.devbox.withoutSecrets(s.env))
+
 	tmpl := shellrcTmpl
 	if s.name == shFish {
 		tmpl = fishrcTmpl
@@ -323,6 +411,8 @@ func (s *DevboxShell) writeDevboxShellrc() (path string, err error) {
 		ShellStartTime   string
 		HistoryFile      string
 		ExportEnv        string
+		MotdPath         string
+		PluginFragments  []pluginFragment
 
 		RefreshAliasName   string
 		RefreshCmd         string
@@ -334,7 +424,9 @@ func (s *DevboxShell) writeDevboxShellrc() (path string, err error) {
 		HooksFilePath:      shellgen.ScriptPath(s.projectDir, shellgen.HooksFilename),
 		ShellStartTime:     telemetry.FormatShellStart(s.shellStartTime),
 		HistoryFile:        strings.TrimSpace(s.historyFile),
-		ExportEnv:          exportify(s.env),
+		ExportEnv:          exportEnv,
+		MotdPath:           motdPath,
+		PluginFragments:    pluginFragments,
 		RefreshAliasName:   s.devbox.refreshAliasName(),
 		RefreshCmd:         s.devbox.refreshCmd(),
 		RefreshAliasEnvVar: s.devbox.refreshAliasEnvVar(),
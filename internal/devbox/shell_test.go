@@ -4,6 +4,7 @@
 package devbox
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"io/fs"
@@ -13,7 +14,11 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+	"go.jetpack.io/devbox/internal/devconfig"
 	"go.jetpack.io/devbox/internal/envir"
+	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/internal/plugin"
 	"go.jetpack.io/devbox/internal/shellgen"
 )
 
@@ -36,6 +41,7 @@ func testWriteDevboxShellrc(t *testing.T, testdirs []string) {
 	tests := make([]struct {
 		name            string
 		env             map[string]string
+		plugins         []plugin.ShellFragment
 		hooksFilePath   string
 		shellrcPath     string
 		goldShellrcPath string
@@ -48,6 +54,11 @@ func testWriteDevboxShellrc(t *testing.T, testdirs []string) {
 		if b, err := os.ReadFile(filepath.Join(path, "env")); err == nil {
 			test.env = envir.PairsToMap(strings.Split(string(b), "\n"))
 		}
+		if b, err := os.ReadFile(filepath.Join(path, "plugins.json")); err == nil {
+			if err := json.Unmarshal(b, &test.plugins); err != nil {
+				t.Fatal("Got error parsing plugins.json:", err)
+			}
+		}
 
 		test.hooksFilePath = shellgen.ScriptPath(projectDir, shellgen.HooksFilename)
 
@@ -66,9 +77,11 @@ func testWriteDevboxShellrc(t *testing.T, testdirs []string) {
 		t.Run(test.name, func(t *testing.T) {
 			s := &DevboxShell{
 				devbox:          &Devbox{projectDir: projectDir},
+				name:            shBash,
 				env:             test.env,
 				projectDir:      "/path/to/projectDir",
 				userShellrcPath: test.shellrcPath,
+				pluginFragments: test.plugins,
 			}
 			gotPath, err := s.writeDevboxShellrc()
 			if err != nil {
@@ -105,3 +118,84 @@ If the new shellrc is correct, you can update the golden file with:
 		})
 	}
 }
+
+func TestProjectShellPath(t *testing.T) {
+	projectDir := t.TempDir()
+	binDir := nix.ProfileBinPath(projectDir)
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "zsh"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A fake nix that evaluates bashInteractive to a store path.
+	pathDir := t.TempDir()
+	fakeNix := "#!/bin/sh\nif [ \"$1\" = eval ]; then printf /nix/store/abc-bash; fi\n"
+	if err := os.WriteFile(filepath.Join(pathDir, "nix"), []byte(fakeNix), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", pathDir)
+	t.Setenv("SHELL", "/bin/zsh")
+	d := &Devbox{projectDir: projectDir, cfg: &devconfig.Config{}}
+
+	got, err := projectShellPath(d, "zsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(binDir, "zsh"); got != want {
+		t.Errorf("got shell path %q, want %q", got, want)
+	}
+
+	// bash falls back to the bash from nixpkgs, not $SHELL, when it isn't
+	// installed.
+	got, err = projectShellPath(d, "bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/nix/store/abc-bash/bin/bash"; got != want {
+		t.Errorf("got shell path %q for missing bash, want %q", got, want)
+	}
+
+	if _, err := projectShellPath(d, "fish"); err == nil {
+		t.Error("got nil error for a missing shell program")
+	}
+	if _, err := projectShellPath(d, "/does/not/exist/bash"); err == nil {
+		t.Error("got nil error for a missing absolute shell program")
+	}
+}
+
+func TestWriteDevboxShellrcMotd(t *testing.T) {
+	projectDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectDir, "devbox.json"), []byte(`{
+  "packages": [],
+  "shell": {"motd": "Welcome! Logs are in {{.ProjectDir}}/logs"}
+}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := Open(&devopt.Opts{Dir: projectDir, Stderr: os.Stderr})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &DevboxShell{devbox: d, projectDir: projectDir}
+	path, err := s.writeDevboxShellrc()
+	if err != nil {
+		t.Fatal("Got writeDevboxShellrc error:", err)
+	}
+	shellrc, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	motdPath := filepath.Join(filepath.Dir(path), "motd")
+	if !strings.Contains(string(shellrc), `cat "`+motdPath+`"`) {
+		t.Errorf("shellrc doesn't show the motd:\n%s", shellrc)
+	}
+	motd, err := os.ReadFile(motdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Welcome! Logs are in " + d.projectDir + "/logs\n"; string(motd) != want {
+		t.Errorf("got motd %q, want %q", motd, want)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/build"
+	"go.jetpack.io/devbox/internal/cachehash"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+// shellenvCacheFile is where devbox shellenv --cache keeps the exports it
+// printed last, relative to the project directory.
+const shellenvCacheFile = ".devbox/gen/shellenv-cache.json"
+
+// shellenvCache is the exports that devbox shellenv --cache printed, and
+// what they depend on.
+type shellenvCache struct {
+	// Key is a hash of the project's config, its lockfile, and the
+	// options of devbox shellenv.
+	Key string `json:"key"`
+
+	// HostVars are the exported variables, and HostHash is a hash of the
+	// values they had in the environment that devbox shellenv ran in.
+	// Variables such as PATH are exported with those values baked in, so
+	// the cache is only valid in an environment where they haven't changed.
+	// Only the hash is saved, so the cache doesn't have a copy of the
+	// user's environment.
+	HostVars []string `json:"host_vars"`
+	HostHash string   `json:"host_hash"`
+
+	Exports string `json:"exports"`
+}
+
+// CachedEnvExports returns the exports that EnvExports returned the last time
+// it ran with opts.Cache, if the project and the environment haven't changed
+// since. It doesn't run nix, so it's fast enough for direnv to call every
+// time you cd into the project.
+func (d *Devbox) CachedEnvExports(opts devopt.EnvExportsOpts) (string, bool) {
+	key, err := d.shellenvCacheKey(opts)
+	if err != nil {
+		debug.Log("shellenv cache: %v", err)
+		return "", false
+	}
+	b, err := os.ReadFile(filepath.Join(d.projectDir, shellenvCacheFile))
+	if err != nil {
+		return "", false
+	}
+	cache := shellenvCache{}
+	if err := json.Unmarshal(b, &cache); err != nil || cache.Key != key {
+		return "", false
+	}
+	if hostEnvHash(cache.HostVars) != cache.HostHash {
+		debug.Log("shellenv cache: the environment changed")
+		return "", false
+	}
+	return cache.Exports, true
+}
+
+// changedEnv returns the variables in env that are different from the
+// current environment, and their names.
+func changedEnv(env map[string]string) (changed map[string]string, names []string) {
+	changed = map[string]string{}
+	for k, v := range env {
+		if current, ok := os.LookupEnv(k); !ok || current != v {
+			changed[k] = v
+		}
+	}
+	names = lo.Keys(changed)
+	slices.Sort(names)
+	return changed, names
+}
+
+// hostEnvHash returns a hash of the current values of the variables.
+func hostEnvHash(names []string) string {
+	sb := strings.Builder{}
+	for _, k := range names {
+		fmt.Fprintf(&sb, "%s=%q\n", k, os.Getenv(k))
+	}
+	h, _ := cachehash.Bytes([]byte(sb.String()))
+	return h
+}
+
+func (d *Devbox) saveShellenvCache(opts devopt.EnvExportsOpts, hostVars []string, exports string) error {
+	key, err := d.shellenvCacheKey(opts)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(shellenvCache{
+		Key:      key,
+		HostVars: hostVars,
+		HostHash: hostEnvHash(hostVars),
+		Exports:  exports,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	path := filepath.Join(d.projectDir, shellenvCacheFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	// Write to a temporary file first, so that a shell that reads the
+	// cache while it's being written doesn't see half of it. The exports
+	// have the project's env, so only the user can read them.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp, path))
+}
+
+// shellenvCacheKey hashes everything other than the environment that changes
+// the exports of devbox shellenv.
+func (d *Devbox) shellenvCacheKey(opts devopt.EnvExportsOpts) (string, error) {
+	// Secrets from Jetpack Cloud can change at any time.
+	if d.cfg.IsEnvsecEnabled() {
+		return "", errors.New("env_from has secrets from Jetpack Cloud")
+	}
+	configHash, err := d.ConfigHash()
+	if err != nil {
+		return "", err
+	}
+	lockHash, err := cachehash.File(filepath.Join(d.projectDir, "devbox.lock"))
+	if err != nil {
+		return "", err
+	}
+
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "version=%s\nconfig=%s\nlock=%s\n", build.Version, configHash, lockHash)
+	fmt.Fprintf(&sb, "hooks=%t\nrefresh=%t\nrecompute=%t\n", opts.RunHooks, !opts.NoRefreshAlias, !opts.DontRecomputeEnvironment)
+	fmt.Fprintf(&sb, "pure=%t\npreserve-path=%t\nfish=%t\n", d.pure, d.preservePathStack, isFishShell())
+	fmt.Fprintf(&sb, "environment=%s\ngroups=%v\n", d.environment, d.packageGroups)
+	for _, file := range d.cfg.EnvFiles() {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(d.projectDir, file)
+		}
+		h, err := cachehash.File(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "env_from %s=%s\n", file, h)
+	}
+	keys := lo.Keys(d.env)
+	slices.Sort(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "env %s=%s\n", k, d.env[k])
+	}
+	return cachehash.Bytes([]byte(sb.String()))
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.jetpack.io/devbox/internal/devbox/devopt"
+)
+
+func TestShellenvCache(t *testing.T) {
+	path := t.TempDir()
+	writeFile := func(name, contents string) {
+		require.NoError(t, os.WriteFile(filepath.Join(path, name), []byte(contents), 0o644))
+	}
+	writeFile("devbox.json", `{"packages": [], "env_from": ".env"}`)
+	writeFile(".env", "A=1\n")
+	t.Setenv("DEVBOX_TEST_PATH", "/usr/bin")
+
+	d, err := Open(&devopt.Opts{Dir: path, Stderr: os.Stderr})
+	require.NoError(t, err)
+	opts := devopt.EnvExportsOpts{Cache: true, RunHooks: true}
+	_, ok := d.CachedEnvExports(opts)
+	assert.False(t, ok, "got cached exports before they were saved")
+
+	changed, hostVars := changedEnv(map[string]string{
+		"DEVBOX_TEST_PATH": "/devbox/bin:/usr/bin",
+		"HOME":             os.Getenv("HOME"),
+	})
+	assert.Equal(t, map[string]string{"DEVBOX_TEST_PATH": "/devbox/bin:/usr/bin"}, changed)
+	require.NoError(t, d.saveShellenvCache(opts, hostVars, "exports"))
+
+	info, err := os.Stat(filepath.Join(path, shellenvCacheFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	b, err := os.ReadFile(filepath.Join(path, shellenvCacheFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "/usr/bin", "got the host's values in the cache file")
+
+	got, ok := d.CachedEnvExports(opts)
+	assert.True(t, ok)
+	assert.Equal(t, "exports", got)
+
+	_, ok = d.CachedEnvExports(devopt.EnvExportsOpts{Cache: true})
+	assert.False(t, ok, "got cached exports for different options")
+
+	t.Setenv("DEVBOX_TEST_PATH", "/bin")
+	_, ok = d.CachedEnvExports(opts)
+	assert.False(t, ok, "got cached exports after a variable they change changed")
+	t.Setenv("DEVBOX_TEST_PATH", "/usr/bin")
+
+	writeFile(".env", "A=2\n")
+	_, ok = d.CachedEnvExports(opts)
+	assert.False(t, ok, "got cached exports after an env file changed")
+	writeFile(".env", "A=1\n")
+
+	writeFile("devbox.lock", `{"lockfile_version": "1"}`)
+	_, ok = d.CachedEnvExports(opts)
+	assert.False(t, ok, "got cached exports after the lockfile changed")
+}
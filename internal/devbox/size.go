@@ -0,0 +1,98 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"runtime/trace"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+// Size prints the closure size of each package in the environment, the size
+// of the whole environment, and the top store paths that take up the most
+// space.
+func (d *Devbox) Size(ctx context.Context, w io.Writer, top int) error {
+	ctx, task := trace.NewTask(ctx, "devboxSize")
+	defer task.End()
+
+	if err := d.ensureStateIsUpToDate(ctx, ensure); err != nil {
+		return err
+	}
+	env, err := d.computeEnv(ctx, true /*usePrintDevEnvCache*/)
+	if err != nil {
+		return err
+	}
+	packagePaths := strings.Fields(env["buildInputs"])
+	if len(packagePaths) == 0 {
+		fmt.Fprintln(w, "There are no packages in this project.")
+		return nil
+	}
+
+	infos, err := nix.ClosurePathInfo(ctx, packagePaths...)
+	if err != nil {
+		return err
+	}
+	byPath := map[string]nix.PathInfo{}
+	var total int64
+	for _, info := range infos {
+		byPath[info.Path] = info
+		total += info.NARSize
+	}
+
+	tw := tabwriter.NewWriter(w, 3, 2, 4, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tCLOSURE SIZE")
+	slices.SortFunc(packagePaths, func(a, b string) int {
+		return cmp.Compare(byPath[b].ClosureSize, byPath[a].ClosureSize)
+	})
+	for _, path := range packagePaths {
+		fmt.Fprintf(tw, "%s\t%s\n", storePathLabel(path), formatSize(byPath[path].ClosureSize))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nTotal: %s (dependencies that packages share are counted once)\n", formatSize(total))
+
+	if top <= 0 {
+		return nil
+	}
+	slices.SortFunc(infos, func(a, b nix.PathInfo) int {
+		return cmp.Compare(b.NARSize, a.NARSize)
+	})
+	fmt.Fprintf(w, "\nLargest store paths:\n")
+	tw = tabwriter.NewWriter(w, 3, 2, 4, ' ', 0)
+	for _, info := range infos[:min(top, len(infos))] {
+		fmt.Fprintf(tw, "  %s\t%s\n", info.Path, formatSize(info.NARSize))
+	}
+	return tw.Flush()
+}
+
+// storePathLabel returns a label like go@1.21.5 for a package's store path.
+func storePathLabel(storePath string) string {
+	parts := nix.NewStorePathParts(storePath)
+	if parts.Version == "" {
+		return parts.Name
+	}
+	return parts.Name + "@" + parts.Version
+}
+
+// formatSize formats a size in bytes with a binary unit, such as 12.3 MiB.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	size := float64(bytes) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if size < unit {
+			return fmt.Sprintf("%.1f %s", size, suffix)
+		}
+		size /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", size)
+}
@@ -0,0 +1,21 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import "testing"
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{
+		512:                           "512 B",
+		2048:                          "2.0 KiB",
+		5 * 1024 * 1024:               "5.0 MiB",
+		1536 * 1024 * 1024:            "1.5 GiB",
+		3 * 1024 * 1024 * 1024 * 1024: "3.0 TiB",
+	}
+	for bytes, want := range cases {
+		if got := formatSize(bytes); got != want {
+			t.Errorf("got formatSize(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime/trace"
+	"slices"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/pkg/errors"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/cachehash"
+	"go.jetpack.io/devbox/internal/ux"
+)
+
+// TmuxShell attaches to the project's tmux session, and creates it first if
+// it doesn't exist. shellCmd is the command that starts a devbox shell in each
+// of the session's windows. Besides a window for the shell, the session has a
+// window for each script, with its devbox run command typed in but not run,
+// and a window that starts the project's services.
+func (d *Devbox) TmuxShell(ctx context.Context, shellCmd []string) error {
+	ctx, task := trace.NewTask(ctx, "devboxTmuxShell")
+	defer task.End()
+
+	// Install the packages once, instead of in every window at the same
+	// time.
+	env, err := d.ensureStateIsUpToDateAndComputeEnv(ctx)
+	if err != nil {
+		return err
+	}
+	tmux, err := lookPathIn("tmux", env["PATH"])
+	if err != nil {
+		return usererr.New("devbox shell --tmux needs tmux. Run `devbox add tmux` to add it to your project.")
+	}
+
+	session := &tmuxSession{
+		tmux:  tmux,
+		name:  tmuxSessionName(d.projectDir),
+		dir:   d.projectDir,
+		shell: shellescape.QuoteCommand(shellCmd),
+	}
+
+	if !session.exists() {
+		scripts := d.ListScripts()
+		slices.Sort(scripts)
+		services, err := d.Services()
+		if err != nil {
+			return err
+		}
+		if err := session.create(scripts, len(services) > 0); err != nil {
+			return err
+		}
+		ux.Finfo(d.stderr, "Created the tmux session %s\n", session.name)
+	}
+	return session.attach()
+}
+
+// tmuxSessionName returns the name of the project's tmux session. Projects in
+// directories with the same name get different sessions.
+func tmuxSessionName(projectDir string) string {
+	dirHash, _ := cachehash.Bytes([]byte(projectDir))
+	return fmt.Sprintf("%s-%.8s", projectSlug(projectDir), dirHash)
+}
+
+var slugUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// projectSlug returns the name of the project's directory, without the
+// characters that tmux and container runtimes don't allow in names, such as
+// periods and colons.
+func projectSlug(projectDir string) string {
+	name := strings.Trim(slugUnsafeChars.ReplaceAllString(filepath.Base(projectDir), "-"), "-")
+	if name == "" {
+		return "devbox"
+	}
+	return name
+}
+
+type tmuxSession struct {
+	tmux  string
+	name  string
+	dir   string
+	shell string
+}
+
+func (t *tmuxSession) exists() bool {
+	_, err := t.run("has-session", "-t", "="+t.name)
+	return err == nil
+}
+
+func (t *tmuxSession) create(scripts []string, hasServices bool) error {
+	if _, err := t.run("new-session", "-d", "-s", t.name, "-c", t.dir, "-n", "shell", t.shell); err != nil {
+		return err
+	}
+	for _, script := range scripts {
+		if err := t.newWindow(script, "devbox run "+shellescape.Quote(script), false); err != nil {
+			return err
+		}
+	}
+	if hasServices {
+		return t.newWindow("services", "devbox services up", true)
+	}
+	return nil
+}
+
+// newWindow adds a window with a devbox shell, and types keys in it. It
+// only runs them if enter is true.
+func (t *tmuxSession) newWindow(name, keys string, enter bool) error {
+	id, err := t.run("new-window", "-d", "-P", "-F", "#{window_id}", "-t", "="+t.name+":", "-n", name, "-c", t.dir, t.shell)
+	if err != nil {
+		return err
+	}
+	id = strings.TrimSpace(id)
+	if _, err := t.run("send-keys", "-t", id, "-l", keys); err != nil {
+		return err
+	}
+	if enter {
+		_, err = t.run("send-keys", "-t", id, "Enter")
+	}
+	return err
+}
+
+// attach attaches the terminal to the session, or switches to it if the
+// terminal is already in a tmux session.
+func (t *tmuxSession) attach() error {
+	args := []string{"attach-session", "-t", "=" + t.name}
+	if os.Getenv("TMUX") != "" {
+		args = []string{"switch-client", "-t", "=" + t.name}
+	}
+	cmd := exec.Command(t.tmux, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return errors.WithStack(cmd.Run())
+}
+
+func (t *tmuxSession) run(args ...string) (string, error) {
+	cmd := exec.Command(t.tmux, args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "tmux %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
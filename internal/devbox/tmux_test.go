@@ -0,0 +1,50 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectSlug(t *testing.T) {
+	for dir, want := range map[string]string{
+		"/home/me/src/my-app":  "my-app",
+		"/home/me/src/app.io":  "app-io",
+		"/home/me/src/.config": "config",
+		"/":                    "devbox",
+	} {
+		assert.Equal(t, want, projectSlug(dir), dir)
+	}
+}
+
+func TestTmuxSessionName(t *testing.T) {
+	name := tmuxSessionName("/home/me/src/my-app")
+	assert.Regexp(t, `^my-app-[0-9a-f]{8}$`, name)
+	assert.NotEqual(t, name, tmuxSessionName("/home/me/work/my-app"))
+}
+
+func TestTmuxSessionCreate(t *testing.T) {
+	tmux, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux isn't installed")
+	}
+	// Use a tmux server of the test's own.
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	session := &tmuxSession{tmux: tmux, name: "my-app", dir: t.TempDir(), shell: "sh"}
+	t.Cleanup(func() { _, _ = session.run("kill-server") })
+	require.False(t, session.exists())
+	require.NoError(t, session.create([]string{"build", "test"}, true))
+	require.True(t, session.exists())
+
+	windows, err := session.run("list-windows", "-t", "=my-app", "-F", "#{window_name}")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shell", "build", "test", "services"}, strings.Fields(windows))
+}
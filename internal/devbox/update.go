@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.jetpack.io/devbox/internal/boxcli/featureflag"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
 	"go.jetpack.io/devbox/internal/devbox/devopt"
 	"go.jetpack.io/devbox/internal/devpkg"
 	"go.jetpack.io/devbox/internal/lock"
@@ -54,14 +55,8 @@ func (d *Devbox) Update(ctx context.Context, opts devopt.UpdateOpts) error {
 	}
 
 	for _, pkg := range pendingPackagesToUpdate {
-		if _, _, isVersioned := searcher.ParseVersionedPackage(pkg.Raw); !isVersioned {
-			if err = d.attemptToUpgradeFlake(pkg); err != nil {
-				return err
-			}
-		} else {
-			if err = d.updateDevboxPackage(pkg); err != nil {
-				return err
-			}
+		if err := d.updateLockedPackage(pkg); err != nil {
+			return err
 		}
 	}
 
@@ -72,6 +67,73 @@ func (d *Devbox) Update(ctx context.Context, opts devopt.UpdateOpts) error {
 	return nix.FlakeUpdate(shellgen.FlakePath(d))
 }
 
+// Upgrade re-resolves only the given packages to the newest version that
+// devbox.json allows, and prints each package's version before and after.
+// Unlike Update, it leaves every other package and flake input locked.
+func (d *Devbox) Upgrade(ctx context.Context, pkgs []string) error {
+	inputs, err := d.inputsToUpdate(devopt.UpdateOpts{Pkgs: pkgs})
+	if err != nil {
+		return err
+	}
+
+	before := map[string]string{}
+	for _, pkg := range inputs {
+		if pkg.IsLegacy() {
+			return usererr.New(
+				"Package %s doesn't have a version. Run `devbox update %[1]s` to convert it to %s.",
+				pkg.Raw, pkg.LegacyToVersioned(),
+			)
+		}
+		before[pkg.Raw] = lockedVersion(d.lockfile.Get(pkg.Raw))
+		if err := d.updateLockedPackage(pkg); err != nil {
+			return err
+		}
+	}
+
+	if err := d.ensureStateIsUpToDate(ctx, update); err != nil {
+		return err
+	}
+
+	for _, pkg := range inputs {
+		after := lockedVersion(d.lockfile.Get(pkg.Raw))
+		if before[pkg.Raw] == after {
+			ux.Finfo(d.stderr, "%s is already the newest version: %s\n", pkg.Raw, after)
+		} else {
+			ux.Fsuccess(d.stderr, "Upgraded %s: %s -> %s\n", pkg.Raw, before[pkg.Raw], after)
+		}
+	}
+	return nil
+}
+
+// lockedVersion returns a package's version in devbox.lock, or the
+// installable it's pinned to if devbox.lock doesn't have a version for it.
+func lockedVersion(entry *lock.Package) string {
+	switch {
+	case entry == nil:
+		return "(not locked)"
+	case entry.Version != "":
+		return entry.Version
+	default:
+		return entry.Resolved
+	}
+}
+
+// updateLockedPackage re-resolves a package that devbox.lock has pinned to
+// the latest version or revision it can be.
+func (d *Devbox) updateLockedPackage(pkg *devpkg.Package) error {
+	existing := d.lockfile.Get(pkg.Raw)
+	if pkg.Channel() != "" {
+		return d.updateChannelPackage(pkg, existing)
+	}
+	if existing.IsFlake() {
+		return d.updateFlake(pkg, existing)
+	}
+	if _, _, isVersioned := searcher.ParseVersionedPackage(pkg.Raw); !isVersioned {
+		return d.attemptToUpgradeFlake(pkg)
+	}
+	return d.updateDevboxPackage(pkg)
+}
+
 func (d *Devbox) inputsToUpdate(
 	opts devopt.UpdateOpts,
 ) ([]*devpkg.Package, error) {
@@ -167,6 +229,42 @@ func (d *Devbox) mergeResolvedPackageToLockfile(
 	return nil
 }
 
+// updateFlake pins a flake that's locked in devbox.lock to the latest revision
+// of its flake reference.
+func (d *Devbox) updateFlake(pkg *devpkg.Package, existing *lock.Package) error {
+	resolved, err := d.lockfile.FetchResolvedFlake(pkg.Raw)
+	if err != nil {
+		return err
+	}
+	if resolved.Resolved == existing.Resolved {
+		ux.Finfo(d.stderr, "Already up-to-date %s\n", pkg)
+		return nil
+	}
+	ux.Finfo(d.stderr, "Updating %s %s -> %s\n", pkg, existing.Resolved, resolved.Resolved)
+	d.lockfile.Packages[pkg.Raw] = resolved
+	return nil
+}
+
+// updateChannelPackage pins a package from a nixpkgs channel to the latest
+// revision of the channel.
+func (d *Devbox) updateChannelPackage(pkg *devpkg.Package, existing *lock.Package) error {
+	resolved, err := d.lockfile.FetchResolvedChannel(pkg.Raw, pkg.Channel())
+	if err != nil {
+		return err
+	}
+	if existing != nil && resolved.Resolved == existing.Resolved {
+		ux.Finfo(d.stderr, "Already up-to-date %s\n", pkg)
+		return nil
+	}
+	if existing == nil {
+		ux.Finfo(d.stderr, "Resolved %s to %s\n", pkg, resolved.Resolved)
+	} else {
+		ux.Finfo(d.stderr, "Updating %s %s -> %s\n", pkg, existing.Resolved, resolved.Resolved)
+	}
+	d.lockfile.Packages[pkg.Raw] = resolved
+	return nil
+}
+
 // attemptToUpgradeFlake attempts to upgrade a flake using `nix profile upgrade`
 // and prints an error if it fails, but does not propagate upgrade errors.
 func (d *Devbox) attemptToUpgradeFlake(pkg *devpkg.Package) error {
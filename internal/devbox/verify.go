@@ -0,0 +1,115 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devbox
+
+import (
+	"context"
+	"fmt"
+	"runtime/trace"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/devpkg"
+	"go.jetpack.io/devbox/internal/lock"
+	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/internal/ux"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// Verify evaluates every package in devbox.lock again, without using nix's
+// caches, and checks that the nixpkgs sources and store paths match the ones
+// in devbox.lock. With rebuild, it also builds each package again and checks
+// that the result is identical to the store path that's already installed.
+//
+// Verify reports every package that doesn't match before it returns an error,
+// so a single run shows all the drift.
+func (d *Devbox) Verify(ctx context.Context, rebuild bool) error {
+	ctx, task := trace.NewTask(ctx, "devboxVerify")
+	defer task.End()
+
+	failed := []string{}
+	for _, pkg := range d.InstallablePackages() {
+		if !pkg.IsNix() {
+			continue
+		}
+		entry := d.lockfile.Get(pkg.Raw)
+		if entry == nil {
+			ux.Fwarning(d.stderr, "%s: not in devbox.lock, skipping\n", pkg.Raw)
+			continue
+		}
+		if problems := d.verifyPackage(ctx, pkg, entry, rebuild); len(problems) > 0 {
+			for _, problem := range problems {
+				ux.Ferror(d.stderr, "%s: %s\n", pkg.Raw, problem)
+			}
+			failed = append(failed, pkg.Raw)
+			continue
+		}
+		ux.Fsuccess(d.stderr, "%s\n", pkg.Raw)
+	}
+
+	if len(failed) > 0 {
+		return usererr.New(
+			"These packages don't match devbox.lock:\n\n  %s",
+			strings.Join(failed, "\n  "),
+		)
+	}
+	fmt.Fprintln(d.stderr, "All packages match devbox.lock.")
+	return nil
+}
+
+// verifyPackage returns the ways in which a package doesn't match its entry
+// in devbox.lock.
+func (d *Devbox) verifyPackage(
+	ctx context.Context,
+	pkg *devpkg.Package,
+	entry *lock.Package,
+	rebuild bool,
+) []string {
+	problems := []string{}
+	installable, err := flake.ParseInstallable(entry.Resolved)
+	if err != nil || installable.Ref.Type == flake.TypePath {
+		// Local flakes aren't locked, so there's nothing to compare.
+		return nil
+	}
+
+	if entry.NarHash != "" {
+		narHash, err := nix.FlakeNarHash(ctx, installable.Ref.String())
+		if err != nil {
+			return append(problems, err.Error())
+		}
+		if narHash != entry.NarHash {
+			problems = append(problems, fmt.Sprintf(
+				"the source of %s has narHash %s, but devbox.lock has %s",
+				installable.Ref, narHash, entry.NarHash,
+			))
+		}
+	}
+
+	outPath, err := nix.EvalOutPath(ctx, entry.Resolved, pkg.HasAllowInsecure())
+	if err != nil {
+		return append(problems, err.Error())
+	}
+	if sysInfo := entry.Systems[nix.System()]; sysInfo != nil && sysInfo.StorePath != "" && sysInfo.StorePath != outPath {
+		problems = append(problems, fmt.Sprintf(
+			"%s evaluates to %s, but devbox.lock has %s",
+			entry.Resolved, outPath, sysInfo.StorePath,
+		))
+	}
+
+	if rebuild {
+		args := &nix.BuildArgs{
+			AllowInsecure: pkg.HasAllowInsecure(),
+			Flags:         []string{"--no-link"},
+		}
+		// --rebuild needs the existing output to compare against.
+		if err := nix.Build(ctx, args, entry.Resolved); err != nil {
+			return append(problems, err.Error())
+		}
+		args.Flags = append(args.Flags, "--rebuild")
+		if err := nix.Build(ctx, args, entry.Resolved); err != nil {
+			problems = append(problems, "rebuilding it didn't produce the same output: "+err.Error())
+		}
+	}
+	return problems
+}
@@ -27,7 +27,8 @@ import (
 // Be aware that there are 4 ways of representing a package in devbox.json that
 // the AST needs to handle:
 //
-//  1. ["name"] or ["name@version"] (versioned name array)
+//  1. ["name"], ["name@version"] or [{"name": "name", "version": "1.2.3"}]
+//     (versioned name array, which may contain package objects)
 //  2. {"name": "version"} (packages object member with version string)
 //  3. {"name": {"version": "1.2.3"}} (packages object member with package object)
 //  4. {"github:F1bonacc1/process-compose/v0.40.2": {}} (packages object member with flakeref)
@@ -45,9 +46,9 @@ func parseConfig(b []byte) (*configAST, error) {
 }
 
 // packagesField gets the "packages" field, initializing it if necessary. The
-// member value will either be an array of strings or an object. When it's an
-// object, the keys will always be package names and the values will be a
-// string or another object. Examples are:
+// member value will either be an array of strings (and package objects) or an
+// object. When it's an object, the keys will always be package names and the
+// values will be a string or another object. Examples are:
 //
 //   - {"packages": ["go", "hello"]}
 //   - {"packages": {"go": "1.20", "hello: {"platforms": ["aarch64-darwin"]}}}
@@ -237,7 +238,7 @@ func (c *configAST) migratePackagesArray(pkgs *hujson.Value) {
 	arr := pkgs.Value.(*hujson.Array)
 	obj := &hujson.Object{Members: make([]hujson.ObjectMember, len(arr.Elements))}
 	for i, elem := range arr.Elements {
-		name, version := parseVersionedName(elem.Value.(hujson.Literal).String())
+		name, version := c.packageElementName(elem)
 
 		// Preserve any comments above the array elements.
 		var before []byte
@@ -246,17 +247,48 @@ func (c *configAST) migratePackagesArray(pkgs *hujson.Value) {
 		}
 		before = append(before, '\n')
 
+		value := hujson.Value{Value: hujson.String(version)}
+		if pkgObject, ok := elem.Value.(*hujson.Object); ok {
+			value.Value = c.packageElementToObject(pkgObject, version)
+		}
 		obj.Members[i] = hujson.ObjectMember{
 			Name: hujson.Value{
 				Value:       hujson.String(name),
 				BeforeExtra: before,
 			},
-			Value: hujson.Value{Value: hujson.String(version)},
+			Value: value,
 		}
 	}
 	pkgs.Value = obj
 }
 
+// packageElementToObject converts a package object from a packages array
+// (which has a "name" field) to a package object that can be used as the
+// value of a packages object member.
+func (c *configAST) packageElementToObject(elem *hujson.Object, version string) *hujson.Object {
+	obj := &hujson.Object{AfterExtra: elem.AfterExtra}
+	for _, m := range elem.Members {
+		if m.Name.Value.(hujson.Literal).String() == "name" {
+			continue
+		}
+		// Put each field on its own line, like the other package objects.
+		if !slices.Contains(m.Name.BeforeExtra, '\n') {
+			m.Name.BeforeExtra = append(slices.Clone(m.Name.BeforeExtra), '\n')
+		}
+		obj.Members = append(obj.Members, m)
+	}
+	if version != "" && c.memberIndex(obj, "version") == -1 {
+		obj.Members = slices.Insert(obj.Members, 0, hujson.ObjectMember{
+			Name: hujson.Value{
+				Value:       hujson.String("version"),
+				BeforeExtra: []byte{'\n'},
+			},
+			Value: hujson.Value{Value: hujson.String(version)},
+		})
+	}
+	return obj
+}
+
 // convertVersionToObject transforms a version string into an object with the
 // version as a field.
 func (c *configAST) convertVersionToObject(pkg *hujson.Value) {
@@ -285,14 +317,31 @@ func (*configAST) memberIndex(obj *hujson.Object, name string) int {
 }
 
 // packageElementIndex returns the index of a package from an array of
-// versionedName strings.
-func (*configAST) packageElementIndex(arr *hujson.Array, name string) int {
+// versionedName strings or package objects.
+func (c *configAST) packageElementIndex(arr *hujson.Array, name string) int {
 	return slices.IndexFunc(arr.Elements, func(v hujson.Value) bool {
-		elemName, _ := parseVersionedName(v.Value.(hujson.Literal).String())
+		elemName, _ := c.packageElementName(v)
 		return elemName == name
 	})
 }
 
+// packageElementName returns the name and version of a package in a packages
+// array. The element is either a versionedName string or an object with a
+// "name" field (and optionally a "version" field).
+func (c *configAST) packageElementName(elem hujson.Value) (name, version string) {
+	obj, ok := elem.Value.(*hujson.Object)
+	if !ok {
+		return parseVersionedName(elem.Value.(hujson.Literal).String())
+	}
+	if i := c.memberIndex(obj, "name"); i != -1 {
+		name, version = parseVersionedName(obj.Members[i].Value.Value.(hujson.Literal).String())
+	}
+	if i := c.memberIndex(obj, "version"); i != -1 {
+		version = obj.Members[i].Value.Value.(hujson.Literal).String()
+	}
+	return name, version
+}
+
 func joinNameVersion(name, version string) string {
 	if version == "" {
 		return name
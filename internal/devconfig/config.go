@@ -26,15 +26,21 @@ import (
 const (
 	defaultName      = "devbox.json"
 	defaultTySONName = "devbox.tson"
+	defaultYAMLName  = "devbox.yaml"
+	defaultYMLName   = "devbox.yml"
 )
 
 const (
 	jsonFormat = iota
 	tsonFormat
+	yamlFormat
 )
 
 // Config defines a devbox environment as JSON.
 type Config struct {
+	// Version is the schema version of the config. See CurrentVersion.
+	Version int `json:"version,omitempty"`
+
 	Name        string `json:"name,omitempty"`
 	Description string `json:"description,omitempty"`
 
@@ -45,8 +51,21 @@ type Config struct {
 	// Env allows specifying env variables
 	Env map[string]string `json:"env,omitempty"`
 
-	// Only allows "envsec" for now
-	EnvFrom string `json:"env_from,omitempty"`
+	// EnvFrom lists other sources of env variables, such as dotenv files.
+	EnvFrom EnvFrom `json:"env_from,omitempty"`
+
+	// Secrets maps env variables to the secret references, such as
+	// "op://dev/db/password", that devbox reads their values from when it
+	// computes the environment. See SecretRef.
+	Secrets map[string]string `json:"secrets,omitempty"`
+
+	// KeepEnv lists variables from the host environment that are kept in
+	// --pure shells.
+	KeepEnv []string `json:"keep_env,omitempty"`
+
+	// ForwardAgents keeps the SSH and GPG agents working in pure and
+	// container shells, so git can push over SSH and sign commits.
+	ForwardAgents bool `json:"forward_agents,omitempty"`
 
 	// Shell configures the devbox shell environment.
 	Shell *shellConfig `json:"shell,omitempty"`
@@ -54,6 +73,10 @@ type Config struct {
 	// Deprecated: Versioned packages don't need this
 	Nixpkgs *NixpkgsConfig `json:"nixpkgs,omitempty"`
 
+	// Nix configures the nix commands that devbox runs, such as which
+	// binary caches they use.
+	Nix *NixConfig `json:"nix,omitempty"`
+
 	// Reserved to allow including other config files. Proposed format is:
 	// path: for local files
 	// https:// for remote files
@@ -61,18 +84,78 @@ type Config struct {
 	// This is a similar format to nix inputs
 	Include []string `json:"include,omitempty"`
 
+	// Services are long-running processes that devbox services starts
+	// and stops, in addition to the ones from plugins and
+	// process-compose.yaml.
+	Services map[string]*Service `json:"services,omitempty"`
+
+	// Proxy routes local hostnames, such as app.localhost, to the ports
+	// that the project's services listen on.
+	Proxy *Proxy `json:"proxy,omitempty"`
+
+	// GitHooks maps git hook names, such as "pre-commit", to the script or
+	// command that devbox runs for the hook.
+	GitHooks map[string]string `json:"git_hooks,omitempty"`
+
 	ast    *configAST
 	format int
+
+	// name is the base name of the file the config was loaded from. It is
+	// empty when the config wasn't loaded from a file.
+	name string
+
+	// yamlSrc is the YAML file the config was loaded from, if it's a
+	// devbox.yaml, so that saving it keeps its comments.
+	yamlSrc []byte
+
+	// local holds the overrides from devbox.local.json, if any. They are
+	// merged into the fields above, but never saved to devbox.json.
+	local *Config
+
+	// defaults holds the user-level defaults from ~/.config/devbox, if any.
+	// Like local, they are never saved to devbox.json.
+	defaults *Config
+
+	// localPackages are the packages from devbox.local.json and defaults
+	// that aren't in devbox.json. They're installed with the project's
+	// packages, but they are never saved to devbox.json or devbox.lock.
+	localPackages []Package
 }
 
 type shellConfig struct {
 	// InitHook contains commands that will run at shell startup.
-	InitHook *shellcmd.Commands            `json:"init_hook,omitempty"`
-	Scripts  map[string]*shellcmd.Commands `json:"scripts,omitempty"`
+	InitHook *shellcmd.Commands `json:"init_hook,omitempty"`
+	Scripts  map[string]*Script `json:"scripts,omitempty"`
+
+	// Program is the shell that devbox shell starts, such as "bash",
+	// instead of the user's $SHELL.
+	Program string `json:"program,omitempty"`
+
+	// Motd is a message that devbox shell shows after the init hooks run.
+	Motd string `json:"motd,omitempty"`
+
+	// Editor is the command that devbox code opens the project with, such
+	// as "cursor", instead of VS Code.
+	Editor string `json:"editor,omitempty"`
 }
 
 type NixpkgsConfig struct {
 	Commit string `json:"commit,omitempty"`
+
+	// PermittedInsecure lists insecure packages (by store name, such as
+	// "openssl-1.1.1w") that nixpkgs may evaluate for every package in
+	// the project.
+	PermittedInsecure []string `json:"permitted_insecure,omitempty"`
+
+	// AllowUnfree allows nixpkgs to evaluate packages with unfree licenses,
+	// such as terraform. It defaults to true.
+	AllowUnfree *bool `json:"allow_unfree,omitempty"`
+
+	// Overlays are nixpkgs overlays that are applied to every nixpkgs
+	// package in the project. Each one is either the path to a .nix file,
+	// relative to the project directory, or an inline Nix expression
+	// such as "final: prev: { ... }".
+	Overlays []string `json:"overlays,omitempty"`
 }
 
 // Stage contains a subset of fields from plansdk.Stage
@@ -85,6 +168,7 @@ const DefaultInitHook = "echo 'Welcome to devbox!' > /dev/null"
 func DefaultConfig() *Config {
 	cfg, err := loadBytes([]byte(fmt.Sprintf(`{
   "$schema": "https://raw.githubusercontent.com/jetpack-io/devbox/main/.schema/devbox.schema.json",
+  "version": %d,
   "packages": [],
   "shell": {
     "init_hook": [
@@ -97,7 +181,7 @@ func DefaultConfig() *Config {
     }
   }
 }
-`, DefaultInitHook)))
+`, CurrentVersion, DefaultInitHook)))
 	if err != nil {
 		panic("default devbox.json is invalid: " + err.Error())
 	}
@@ -112,7 +196,16 @@ func (c *Config) Bytes() []byte {
 func (c *Config) Hash() (string, error) {
 	ast := c.ast.root.Clone()
 	ast.Minimize()
-	return cachehash.Bytes(ast.Pack())
+	b := ast.Pack()
+	for _, other := range []*Config{c.local, c.defaults} {
+		if other == nil {
+			continue
+		}
+		otherAST := other.ast.root.Clone()
+		otherAST.Minimize()
+		b = append(b, otherAST.Pack()...)
+	}
+	return cachehash.Bytes(b)
 }
 
 func (c *Config) Equals(other *Config) bool {
@@ -131,6 +224,40 @@ func (c *Config) NixPkgsCommitHash() string {
 	return c.Nixpkgs.Commit
 }
 
+// PermittedInsecurePackages returns the insecure packages that are allowed
+// project-wide. Packages can also allow them individually with
+// allow_insecure.
+func (c *Config) PermittedInsecurePackages() []string {
+	if c == nil || c.Nixpkgs == nil {
+		return nil
+	}
+	return c.Nixpkgs.PermittedInsecure
+}
+
+// NixpkgsOverlays returns the project's nixpkgs overlays. Use IsOverlayFile
+// to tell overlay files from inline expressions.
+func (c *Config) NixpkgsOverlays() []string {
+	if c == nil || c.Nixpkgs == nil {
+		return nil
+	}
+	return c.Nixpkgs.Overlays
+}
+
+// IsOverlayFile reports whether an entry in nixpkgs.overlays is the path to
+// a .nix file rather than an inline Nix expression.
+func IsOverlayFile(overlay string) bool {
+	return strings.HasSuffix(overlay, ".nix") && !strings.ContainsAny(overlay, ":{}\n")
+}
+
+// AllowUnfree reports whether nixpkgs may evaluate packages with unfree
+// licenses. It's true unless nixpkgs.allow_unfree is set to false.
+func (c *Config) AllowUnfree() bool {
+	if c == nil || c.Nixpkgs == nil || c.Nixpkgs.AllowUnfree == nil {
+		return true
+	}
+	return *c.Nixpkgs.AllowUnfree
+}
+
 func (c *Config) InitHook() *shellcmd.Commands {
 	if c == nil || c.Shell == nil {
 		return nil
@@ -138,12 +265,81 @@ func (c *Config) InitHook() *shellcmd.Commands {
 	return c.Shell.InitHook
 }
 
-// SaveTo writes the config to a file.
+// ShellProgram returns the shell program that the project uses for
+// devbox shell, or an empty string to use the user's shell.
+func (c *Config) ShellProgram() string {
+	if c == nil || c.Shell == nil {
+		return ""
+	}
+	return c.Shell.Program
+}
+
+// Editor returns the editor that devbox code opens, or an empty string to use
+// VS Code. devbox.local.json and the user's defaults can set it too, since
+// it's usually a personal choice.
+func (c *Config) Editor() string {
+	if c == nil {
+		return ""
+	}
+	for _, cfg := range []*Config{c.local, c, c.defaults} {
+		if cfg != nil && cfg.Shell != nil && cfg.Shell.Editor != "" {
+			return cfg.Shell.Editor
+		}
+	}
+	return ""
+}
+
+// ForwardsAgents reports whether the project, devbox.local.json or the user's
+// defaults turn on forward_agents.
+func (c *Config) ForwardsAgents() bool {
+	if c == nil {
+		return false
+	}
+	for _, cfg := range []*Config{c, c.local, c.defaults} {
+		if cfg != nil && cfg.ForwardAgents {
+			return true
+		}
+	}
+	return false
+}
+
+// ShellMotd returns the message to show when entering devbox shell, or an
+// empty string if there isn't one.
+func (c *Config) ShellMotd() string {
+	if c == nil || c.Shell == nil {
+		return ""
+	}
+	return c.Shell.Motd
+}
+
+// FileName returns the base name of the file the config was loaded from, such
+// as devbox.json or devbox.yaml.
+func (c *Config) FileName() string {
+	if c.name == "" {
+		return defaultName
+	}
+	return c.name
+}
+
+// SaveTo writes the config to a file. The file keeps the name and format
+// (JSON or YAML) that the config was loaded from.
 func (c *Config) SaveTo(path string) error {
-	if c.format != jsonFormat {
+	switch c.format {
+	case jsonFormat:
+		return os.WriteFile(filepath.Join(path, defaultName), c.Bytes(), 0o644)
+	case yamlFormat:
+		b, err := patchYAML(c.yamlSrc, c.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(path, c.name), b, 0o644); err != nil {
+			return err
+		}
+		c.yamlSrc = b
+		return nil
+	default:
 		return errors.New("cannot save config to non-json format")
 	}
-	return os.WriteFile(filepath.Join(path, defaultName), c.Bytes(), 0o644)
 }
 
 // Load reads a devbox config file, and validates it.
@@ -152,7 +348,42 @@ func Load(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	return loadBytes(b)
+
+	name := filepath.Base(path)
+	format := jsonFormat
+	var yamlSrc []byte
+	if isYAMLName(name) {
+		yamlSrc = b
+		if b, err = yamlToJSON(b); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+		format = yamlFormat
+	}
+
+	// Check the config against the schema before unmarshalling it so
+	// that mistakes are reported where they are instead of as an error
+	// from encoding/json (or not at all, for unknown fields).
+	ast, err := parseConfig(b)
+	if err != nil {
+		return nil, err
+	}
+	// Offsets in a converted YAML file don't match the original, so only
+	// report positions for JSON.
+	if err := schemaUserError(name, b, validateSchema(ast), format == jsonFormat); err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(name, cfg.Version); err != nil {
+		return nil, err
+	}
+	cfg.format = format
+	cfg.name = name
+	cfg.yamlSrc = yamlSrc
+	return cfg, nil
 }
 
 func loadBytes(b []byte) (*Config, error) {
@@ -193,6 +424,8 @@ func validateConfig(cfg *Config) error {
 	fns := []func(cfg *Config) error{
 		ValidateNixpkg,
 		validateScripts,
+		validateGitHooks,
+		validateSecrets,
 	}
 
 	for _, fn := range fns {
@@ -207,7 +440,14 @@ var whitespace = regexp.MustCompile(`\s`)
 
 func validateScripts(cfg *Config) error {
 	scripts := cfg.Scripts()
+	// Sort the names so that errors (such as dependency cycles) are
+	// reported the same way every time.
+	names := make([]string, 0, len(scripts))
 	for k := range scripts {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	for _, k := range names {
 		if strings.TrimSpace(k) == "" {
 			return errors.New("cannot have script with empty name in devbox.json")
 		}
@@ -219,6 +459,30 @@ func validateScripts(cfg *Config) error {
 			return errors.Errorf(
 				"cannot have an empty script body in devbox.json: %s", k)
 		}
+		if _, err := cfg.ScriptDependencies(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitHookNames are the client-side hooks that git runs.
+var gitHookNames = []string{
+	"applypatch-msg", "commit-msg", "fsmonitor-watchman", "post-applypatch",
+	"post-checkout", "post-commit", "post-merge", "post-rewrite",
+	"pre-applypatch", "pre-auto-gc", "pre-commit", "pre-merge-commit",
+	"pre-push", "pre-rebase", "prepare-commit-msg", "push-to-checkout",
+	"reference-transaction", "sendemail-validate",
+}
+
+func validateGitHooks(cfg *Config) error {
+	for name, cmd := range cfg.GitHooks {
+		if !slices.Contains(gitHookNames, name) {
+			return usererr.New("%q in git_hooks isn't a git hook, such as pre-commit or pre-push.", name)
+		}
+		if strings.TrimSpace(cmd) == "" {
+			return usererr.New("The %s git hook in devbox.json needs a script or a command.", name)
+		}
 	}
 	return nil
 }
@@ -237,15 +501,27 @@ func ValidateNixpkg(cfg *Config) error {
 			len(hash),
 		)
 	}
+	// The commit is interpolated into flake references, so make sure it's
+	// a real commit hash and not a branch name or something else.
+	if strings.Trim(strings.ToLower(hash), "0123456789abcdef") != "" {
+		return usererr.New(
+			"Expected nixpkgs.commit to be a hexadecimal git commit hash, but got %q",
+			hash,
+		)
+	}
 	return nil
 }
 
+func isYAMLName(name string) bool {
+	return name == defaultYAMLName || name == defaultYMLName
+}
+
 func IsConfigName(name string) bool {
 	return slices.Contains(ValidConfigNames(), name)
 }
 
 func ValidConfigNames() []string {
-	names := []string{defaultName}
+	names := []string{defaultName, defaultYAMLName, defaultYMLName}
 	if featureflag.TySON.Enabled() {
 		names = append(names, defaultTySONName)
 	}
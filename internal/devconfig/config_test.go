@@ -87,6 +87,42 @@ func TestNoChanges(t *testing.T) {
 	}
 }
 
+func TestCommentsAndTrailingCommas(t *testing.T) {
+	in, want := parseConfigTxtarTest(t, `comments and trailing commas are accepted and preserved when saving
+-- in --
+{
+  // Pinned because 1.22 breaks our build.
+  "packages": {
+    "go": "1.21",
+  },
+  "env": {
+    "FOO": "bar", /* trailing comma */
+  },
+}
+-- want --
+{
+  // Pinned because 1.22 breaks our build.
+  "packages": {
+    "go":    "1.21",
+    "hello": "latest",
+  },
+  "env": {
+    "FOO": "bar", /* trailing comma */
+  },
+}`)
+
+	if got := in.Env["FOO"]; got != "bar" {
+		t.Errorf("got env FOO=%q, want %q", got, "bar")
+	}
+	in.Packages.Add("hello@latest")
+	if diff := cmp.Diff(want, in.Bytes(), optParseHujson()); diff != "" {
+		t.Errorf("wrong parsed config json (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, in.Bytes()); diff != "" {
+		t.Errorf("wrong raw config hujson (-want +got):\n%s", diff)
+	}
+}
+
 func TestAddPackageEmptyConfig(t *testing.T) {
 	in, want := parseConfigTxtarTest(t, `
 -- in --
@@ -522,6 +558,70 @@ func TestAddPlatformsMigrateArray(t *testing.T) {
 	}
 }
 
+func TestAddPlatformsMigrateArrayObjects(t *testing.T) {
+	in, want := parseConfigTxtarTest(t, `
+-- in --
+{
+  "packages": [
+    "go",
+    {"name": "python@3.10", "excluded_platforms": ["aarch64-darwin"]},
+    {"name": "inotify-tools", "platforms": ["x86_64-linux"]}
+  ]
+}
+-- want --
+{
+  "packages": {
+    "go": {
+      "platforms": ["aarch64-darwin"]
+    },
+    "python": {
+      "version":            "3.10",
+      "excluded_platforms": ["aarch64-darwin"]
+    },
+    "inotify-tools": {
+      "platforms": ["x86_64-linux", "aarch64-linux"]
+    }
+  }
+}`)
+
+	err := in.Packages.AddPlatforms(io.Discard, "go", []string{"aarch64-darwin"})
+	if err != nil {
+		t.Error(err)
+	}
+	err = in.Packages.AddPlatforms(io.Discard, "inotify-tools", []string{"aarch64-linux"})
+	if err != nil {
+		t.Error(err)
+	}
+	if diff := cmp.Diff(want, in.Bytes(), optParseHujson()); diff != "" {
+		t.Errorf("wrong parsed config json (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, in.Bytes()); diff != "" {
+		t.Errorf("wrong raw config hujson (-want +got):\n%s", diff)
+	}
+}
+
+func TestRemovePackageArrayObject(t *testing.T) {
+	in, want := parseConfigTxtarTest(t, `
+-- in --
+{
+  "packages": [
+    "go",
+    {"name": "inotify-tools", "platforms": ["x86_64-linux"]}
+  ]
+}
+-- want --
+{
+  "packages": [
+    "go"
+  ]
+}`)
+
+	in.Packages.Remove("inotify-tools")
+	if diff := cmp.Diff(want, in.Bytes(), optParseHujson()); diff != "" {
+		t.Errorf("wrong parsed config json (-want +got):\n%s", diff)
+	}
+}
+
 func TestAddPlatformsMigrateArrayComments(t *testing.T) {
 	in, want := parseConfigTxtarTest(t, `
 -- in --
@@ -685,6 +785,38 @@ func TestSetAllowInsecure(t *testing.T) {
 	}
 }
 
+func TestAddEnvFrom(t *testing.T) {
+	in, want := parseConfigTxtarTest(t, `
+-- in --
+{
+  "packages": [],
+  "env_from": ".env"
+}
+-- want --
+{
+  "packages": [],
+  "env_from": [".env", "jetpack-cloud"]
+}`)
+
+	if diff := cmp.Diff([]string{".env"}, in.EnvFiles()); diff != "" {
+		t.Errorf("wrong env files (-want +got):\n%s", diff)
+	}
+	if in.IsEnvsecEnabled() {
+		t.Error("got IsEnvsecEnabled() = true before adding jetpack-cloud")
+	}
+	in.AddEnvFrom("jetpack-cloud")
+	in.AddEnvFrom("jetpack-cloud")
+	if !in.IsEnvsecEnabled() {
+		t.Error("got IsEnvsecEnabled() = false after adding jetpack-cloud")
+	}
+	if diff := cmp.Diff(want, in.Bytes(), optParseHujson()); diff != "" {
+		t.Errorf("wrong parsed config json (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, in.Bytes()); diff != "" {
+		t.Errorf("wrong raw config hujson (-want +got):\n%s", diff)
+	}
+}
+
 func TestDefault(t *testing.T) {
 	path := filepath.Join(t.TempDir())
 	in := DefaultConfig()
@@ -712,3 +844,40 @@ func TestDefault(t *testing.T) {
 		t.Errorf("got different JSON after load/save/load:\ninput:\n%s\noutput:\n%s", inBytes, outBytes)
 	}
 }
+
+func TestValidateGitHooks(t *testing.T) {
+	if _, err := loadBytes([]byte(`{"git_hooks": {"pre-commit": "lint", "pre-push": "test"}}`)); err != nil {
+		t.Errorf("got error %v for valid git_hooks", err)
+	}
+
+	testCases := []struct {
+		hooks   string
+		wantErr string
+	}{
+		{`{"pre-comit": "lint"}`, `"pre-comit" in git_hooks isn't a git hook`},
+		{`{"pre-commit": " "}`, "The pre-commit git hook in devbox.json needs a script or a command."},
+	}
+	for _, tc := range testCases {
+		_, err := loadBytes([]byte(`{"git_hooks": ` + tc.hooks + `}`))
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("got error %v for git_hooks %s, want it to contain %q", err, tc.hooks, tc.wantErr)
+		}
+	}
+}
+
+func TestAllowUnfree(t *testing.T) {
+	for config, want := range map[string]bool{
+		`{}`:                                   true,
+		`{"nixpkgs": {"commit": ""}}`:          true,
+		`{"nixpkgs": {"allow_unfree": true}}`:  true,
+		`{"nixpkgs": {"allow_unfree": false}}`: false,
+	} {
+		cfg, err := loadBytes([]byte(config))
+		if err != nil {
+			t.Fatalf("loadBytes(%s) error = %v", config, err)
+		}
+		if got := cfg.AllowUnfree(); got != want {
+			t.Errorf("got AllowUnfree() = %v for %s, want %v", got, config, want)
+		}
+	}
+}
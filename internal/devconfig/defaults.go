@@ -0,0 +1,81 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/xdg"
+)
+
+// defaultsName is an optional, user-level config in ~/.config/devbox that
+// provides defaults for every project. It uses the same schema as devbox.json.
+const defaultsName = "defaults.json"
+
+// DefaultsPath returns the path of the user-level config defaults.
+func DefaultsPath() string {
+	return xdg.ConfigSubpath(filepath.Join("devbox", defaultsName))
+}
+
+// loadDefaults loads the user-level defaults (if they exist) and merges them
+// under c. Like devbox.local.json, they are never written back to devbox.json.
+func (c *Config) loadDefaults() error {
+	path := DefaultsPath()
+	if !fileutil.Exists(path) {
+		return nil
+	}
+	defaults, err := Load(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", path)
+	}
+	c.mergeDefaults(defaults)
+	return nil
+}
+
+// mergeDefaults merges the user-level defaults into c. The project's own
+// config always wins:
+//
+//   - packages that aren't already in c are kept apart in LocalPackages, like
+//     the ones in devbox.local.json.
+//   - env vars are only added if c doesn't set them.
+//   - keep_env entries that aren't already in c are appended.
+//   - init hook commands in defaults run before the ones in c.
+//   - nix substituters, trusted keys and builders that aren't already in c are appended,
+//     and nix build settings are only used if c doesn't set them.
+func (c *Config) mergeDefaults(defaults *Config) {
+	c.defaults = defaults
+
+	c.addLocalPackages(defaults.Packages.Collection, false)
+
+	for k, v := range defaults.Env {
+		if c.Env == nil {
+			c.Env = map[string]string{}
+		}
+		if _, ok := c.Env[k]; !ok {
+			c.Env[k] = v
+		}
+	}
+
+	for _, name := range defaults.KeepEnv {
+		if !slices.Contains(c.KeepEnv, name) {
+			c.KeepEnv = append(c.KeepEnv, name)
+		}
+	}
+
+	if hook := defaults.InitHook(); hook != nil && len(hook.Cmds) > 0 {
+		if c.Shell == nil {
+			c.Shell = &shellConfig{}
+		}
+		if c.Shell.InitHook == nil {
+			c.Shell.InitHook = &shellcmd.Commands{}
+		}
+		c.Shell.InitHook.Cmds = append(slices.Clone(hook.Cmds), c.Shell.InitHook.Cmds...)
+	}
+
+	c.mergeNix(defaults.Nix, false)
+}
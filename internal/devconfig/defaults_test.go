@@ -0,0 +1,97 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/envir"
+)
+
+func TestOpenMergesDefaults(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv(envir.XDGConfigHome, configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "devbox"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, DefaultsPath(), `{
+  "packages": ["ripgrep", "go@1.20"],
+  "env": {"EDITOR": "vim", "FOO": "default"},
+  "keep_env": ["SSH_AUTH_SOCK"],
+  "shell": {"init_hook": "echo defaults"},
+  "nix": {
+    "substituters": ["https://team.cachix.org", "https://cache.example.com"],
+    "builders": [{"uri": "ssh://builder", "max_jobs": 4}]
+  }
+}`)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultName), `{
+  "packages": ["go@1.21"],
+  "env": {"FOO": "project"},
+  "keep_env": ["AWS_PROFILE"],
+  "shell": {"init_hook": "echo project"},
+  "nix": {"substituters": ["https://team.cachix.org"], "trusted_public_keys": ["team.cachix.org-1:abc="]}
+}`)
+	writeFile(t, filepath.Join(dir, localName), `{
+  "packages": ["ripgrep@14"],
+  "env": {"EDITOR": "nano"},
+  "shell": {"init_hook": "echo local"}
+}`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	wantPkgs := []string{"go@1.21"}
+	if diff := cmp.Diff(wantPkgs, cfg.Packages.VersionedNames()); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantLocalPkgs := []string{"ripgrep@14"}
+	gotLocalPkgs := []string{}
+	for _, pkg := range cfg.LocalPackages() {
+		gotLocalPkgs = append(gotLocalPkgs, pkg.VersionedName())
+	}
+	if diff := cmp.Diff(wantLocalPkgs, gotLocalPkgs); diff != "" {
+		t.Errorf("wrong local packages (-want +got):\n%s", diff)
+	}
+	wantEnv := map[string]string{"EDITOR": "nano", "FOO": "project"}
+	if diff := cmp.Diff(wantEnv, cfg.Env); diff != "" {
+		t.Errorf("wrong env (-want +got):\n%s", diff)
+	}
+	wantKeepEnv := []string{"AWS_PROFILE", "SSH_AUTH_SOCK"}
+	if diff := cmp.Diff(wantKeepEnv, cfg.KeepEnv); diff != "" {
+		t.Errorf("wrong keep_env (-want +got):\n%s", diff)
+	}
+	if got, want := cfg.InitHook().String(), "echo defaults\necho project\necho local"; got != want {
+		t.Errorf("got init hook %q, want %q", got, want)
+	}
+	wantNix := NixConfig{
+		Substituters:      []string{"https://team.cachix.org", "https://cache.example.com"},
+		TrustedPublicKeys: []string{"team.cachix.org-1:abc="},
+		Builders:          []NixBuilder{{URI: "ssh://builder", MaxJobs: 4}},
+	}
+	if diff := cmp.Diff(wantNix, cfg.NixSettings()); diff != "" {
+		t.Errorf("wrong nix settings (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenInvalidDefaults(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv(envir.XDGConfigHome, configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "devbox"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, DefaultsPath(), `{"packages": "ripgrep"}`)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultName), `{"packages": []}`)
+	if _, err := Open(dir); err == nil {
+		t.Error("got nil error for invalid defaults")
+	}
+}
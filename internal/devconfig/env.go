@@ -1,6 +1,165 @@
 package devconfig
 
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/tailscale/hujson"
+)
+
+// env_from sources that load the project's secrets from Jetpack Cloud.
+// "envsec" is the legacy name.
+const (
+	envFromJetpackCloud = "jetpack-cloud"
+	envFromEnvsec       = "envsec"
+)
+
+// EnvFrom lists the sources that env variables are loaded from, in addition
+// to the env map. Each source is either "jetpack-cloud" or the path of a
+// dotenv file relative to the project directory. It can be written as a single
+// string or an array of strings:
+//
+//	"env_from": "jetpack-cloud"
+//	"env_from": [".env", ".env.local"]
+type EnvFrom []string
+
+func (e *EnvFrom) UnmarshalJSON(b []byte) error {
+	var source string
+	if err := json.Unmarshal(b, &source); err == nil {
+		*e = EnvFrom{source}
+		return nil
+	}
+	var sources []string
+	if err := json.Unmarshal(b, &sources); err != nil {
+		return errors.WithStack(err)
+	}
+	*e = sources
+	return nil
+}
+
+func (e EnvFrom) MarshalJSON() ([]byte, error) {
+	if len(e) == 1 {
+		return json.Marshal(e[0])
+	}
+	return json.Marshal([]string(e))
+}
+
 func (c *Config) IsEnvsecEnabled() bool {
 	// envsec for legacy.
-	return c.EnvFrom == "envsec" || c.EnvFrom == "jetpack-cloud"
+	return slices.ContainsFunc(c.EnvFrom, isJetpackCloud)
+}
+
+// EnvFiles returns the dotenv files listed in env_from in the order they
+// should be loaded. Paths are relative to the project directory.
+func (c *Config) EnvFiles() []string {
+	var files []string
+	for _, source := range c.EnvFrom {
+		if !isJetpackCloud(source) {
+			files = append(files, source)
+		}
+	}
+	return files
+}
+
+// AddEnvFrom adds source to env_from unless it's already there. A single
+// source is saved as a string to match the format older versions of devbox
+// wrote.
+func (c *Config) AddEnvFrom(source string) {
+	if slices.Contains(c.EnvFrom, source) {
+		return
+	}
+	c.EnvFrom = append(c.EnvFrom, source)
+	if len(c.EnvFrom) == 1 {
+		c.ast.setStringField("env_from", source)
+		return
+	}
+	c.ast.setRootStringArray("env_from", c.EnvFrom)
+}
+
+// ProjectEnv returns the env map from the project's config file, without the
+// values that devbox.local.json and the user-level defaults add or override.
+func (c *Config) ProjectEnv() (map[string]string, error) {
+	b, err := hujson.Standardize(c.Bytes())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	project := struct {
+		Env map[string]string `json:"env"`
+	}{}
+	if err := json.Unmarshal(b, &project); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return project.Env, nil
+}
+
+// setEnv sets the env map. Variables are written in sorted order.
+func (c *Config) setEnv(env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+	c.Env = env
+	c.ast.setRootStringMap("env", env)
+}
+
+func isJetpackCloud(source string) bool {
+	return source == envFromJetpackCloud || source == envFromEnvsec
+}
+
+// setRootStringArray sets a root field to an array of strings, replacing any
+// existing value.
+func (c *configAST) setRootStringArray(key string, vals []string) {
+	arr := &hujson.Array{Elements: make([]hujson.Value, 0, len(vals))}
+	for _, v := range vals {
+		arr.Elements = append(arr.Elements, hujson.Value{Value: hujson.String(v)})
+	}
+
+	rootObject := c.root.Value.(*hujson.Object)
+	if i := c.memberIndex(rootObject, key); i == -1 {
+		rootObject.Members = append(rootObject.Members, hujson.ObjectMember{
+			Name: hujson.Value{
+				Value:       hujson.String(key),
+				BeforeExtra: []byte{'\n'},
+			},
+			Value: hujson.Value{Value: arr},
+		})
+	} else {
+		rootObject.Members[i].Value.Value = arr
+	}
+	c.root.Format()
+}
+
+// setRootStringMap sets a root field to an object of strings, replacing any
+// existing value.
+func (c *configAST) setRootStringMap(key string, vals map[string]string) {
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	obj := &hujson.Object{Members: make([]hujson.ObjectMember, 0, len(vals))}
+	for _, k := range keys {
+		obj.Members = append(obj.Members, hujson.ObjectMember{
+			Name: hujson.Value{
+				Value:       hujson.String(k),
+				BeforeExtra: []byte{'\n'},
+			},
+			Value: hujson.Value{Value: hujson.String(vals[k])},
+		})
+	}
+
+	rootObject := c.root.Value.(*hujson.Object)
+	if i := c.memberIndex(rootObject, key); i == -1 {
+		rootObject.Members = append(rootObject.Members, hujson.ObjectMember{
+			Name: hujson.Value{
+				Value:       hujson.String(key),
+				BeforeExtra: []byte{'\n'},
+			},
+			Value: hujson.Value{Value: obj},
+		})
+	} else {
+		rootObject.Members[i].Value.Value = obj
+	}
+	c.root.Format()
 }
@@ -11,39 +11,89 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/samber/lo"
+	"github.com/tailscale/hujson"
 
 	"go.jetpack.io/devbox/internal/boxcli/featureflag"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
 	"go.jetpack.io/devbox/internal/devpkg/pkgtype"
 	"go.jetpack.io/devbox/internal/fileutil"
 	"go.jetpack.io/devbox/internal/initrec"
+	"go.jetpack.io/devbox/internal/ux"
 )
 
 func Init(dir string, writer io.Writer) (created bool, err error) {
-	created, err = initConfigFile(filepath.Join(dir, defaultName))
-	if err != nil || !created {
-		return created, err
+	return InitWithOpts(dir, InitOpts{}, writer)
+}
+
+// InitOpts are the options of InitWithOpts.
+type InitOpts struct {
+	// Format is the format of the config file: "json" (devbox.json, the
+	// default) or "yaml" (devbox.yaml).
+	Format string
+
+	// SelectPackages chooses which of the packages that devbox recommends
+	// are added to the new config. If it's nil, packages are only
+	// suggested.
+	SelectPackages func(pkgs []string) ([]string, error)
+
+	// From is the path of an existing environment file, such as a
+	// shell.nix, to migrate. Its packages are always added.
+	From string
+}
+
+// InitWithOpts is like Init, but lets the caller choose the format of the
+// config file, add the recommended packages to it or migrate an existing
+// environment file.
+func InitWithOpts(dir string, opts InitOpts, writer io.Writer) (created bool, err error) {
+	var name string
+	switch format := opts.Format; format {
+	case "json", "":
+		name = defaultName
+	case "yaml", "yml":
+		name = defaultYAMLName
+	default:
+		return false, usererr.New(
+			"invalid config format %q. Format must be one of json or yaml.", format)
+	}
+	// Don't create a second config if the directory already has one,
+	// regardless of its format.
+	for _, existing := range ValidConfigNames() {
+		if fileutil.Exists(filepath.Join(dir, existing)) {
+			return false, nil
+		}
 	}
 
-	// package suggestion
-	pkgsToSuggest, err := initrec.Get(dir)
-	if err != nil {
-		return created, err
+	var rec *initrec.Recommendation
+	var pkgs []string
+	if opts.From != "" {
+		rec, err = initrec.FromFile(opts.From)
+		if err != nil {
+			return false, err
+		}
+		pkgs = rec.Packages
+	} else if rec, err = initrec.Get(dir); err != nil {
+		return false, err
 	}
-	if len(pkgsToSuggest) > 0 {
-		s := fmt.Sprintf("devbox add %s", strings.Join(pkgsToSuggest, " "))
-		fmt.Fprintf(
-			writer,
-			"We detected extra packages you may need. To install them, run `%s`\n",
-			color.HiYellowString(s),
-		)
+	if opts.From == "" && opts.SelectPackages != nil && len(rec.Packages) > 0 {
+		if pkgs, err = opts.SelectPackages(rec.Packages); err != nil {
+			return false, err
+		}
+	}
+	created, err = initConfigFile(filepath.Join(dir, name), rec, pkgs)
+	if err != nil || !created {
+		return created, err
 	}
-	return created, err
+	printRecommendation(writer, rec, pkgs, name)
+	return created, nil
 }
 
-func initConfigFile(path string) (created bool, err error) {
+func initConfigFile(path string, rec *initrec.Recommendation, pkgs []string) (created bool, err error) {
 	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
 	if errors.Is(err, os.ErrExist) {
 		return false, nil
@@ -57,7 +107,19 @@ func initConfigFile(path string) (created bool, err error) {
 		}
 	}()
 
-	_, err = file.Write(DefaultConfig().Bytes())
+	cfg := DefaultConfig()
+	cfg.applyRecommendation(rec)
+	for _, pkg := range pkgs {
+		cfg.Packages.Add(pkg)
+	}
+	b := cfg.Bytes()
+	if isYAMLName(filepath.Base(path)) {
+		if b, err = jsonToYAML(b); err != nil {
+			file.Close()
+			return false, err
+		}
+	}
+	_, err = file.Write(b)
 	if err != nil {
 		file.Close()
 		return false, err
@@ -68,9 +130,108 @@ func initConfigFile(path string) (created bool, err error) {
 	return true, nil
 }
 
+// printRecommendation tells the user about the settings that devbox init
+// added for the detected projects and the packages they may need. Unlike the
+// settings, packages are only suggested unless the user chose to add them
+// (added), because installing them can take a while.
+func printRecommendation(
+	writer io.Writer,
+	rec *initrec.Recommendation,
+	added []string,
+	configName string,
+) {
+	if len(rec.Projects) == 0 {
+		return
+	}
+	var lines []string
+	if len(rec.Env) > 0 {
+		names := lo.Keys(rec.Env)
+		slices.Sort(names)
+		lines = append(lines, "  env: "+strings.Join(names, ", "))
+	}
+	for _, cmd := range rec.InitHook {
+		lines = append(lines, "  init_hook: "+cmd)
+	}
+	if len(rec.Scripts) > 0 {
+		names := lo.Keys(rec.Scripts)
+		slices.Sort(names)
+		lines = append(lines, "  scripts: "+strings.Join(names, ", "))
+	}
+	if len(lines) > 0 {
+		fmt.Fprintf(
+			writer,
+			"We detected a %s project and added these settings to %s. Feel free to edit or remove them:\n%s\n",
+			strings.Join(rec.Projects, " and "), configName, strings.Join(lines, "\n"),
+		)
+	}
+	for _, conflict := range rec.Conflicts {
+		ux.Fwarning(writer, "%s\n", conflict)
+	}
+	for _, warning := range rec.Warnings {
+		ux.Fwarning(writer, "%s\n", warning)
+	}
+	if len(added) > 0 {
+		fmt.Fprintf(
+			writer,
+			"Added these packages to %s: %s. They're installed the next time you run `devbox shell`.\n",
+			configName, strings.Join(added, " "),
+		)
+	}
+	suggested := lo.Without(rec.Packages, added...)
+	if len(suggested) > 0 {
+		s := fmt.Sprintf("devbox add %s", strings.Join(suggested, " "))
+		fmt.Fprintf(
+			writer,
+			"We detected extra packages you may need. To install them, run `%s`\n",
+			color.HiYellowString(s),
+		)
+	}
+}
+
+// applyRecommendation adds the recommended settings to a new config created
+// by DefaultConfig. Recommended scripts replace any default script with the
+// same name.
+func (c *Config) applyRecommendation(rec *initrec.Recommendation) {
+	c.setEnv(rec.Env)
+	if len(rec.InitHook) > 0 {
+		c.Shell.InitHook.Cmds = append(c.Shell.InitHook.Cmds, rec.InitHook...)
+		c.ast.appendInitHook(rec.InitHook)
+	}
+	names := lo.Keys(rec.Scripts)
+	slices.Sort(names)
+	for _, name := range names {
+		cmds := shellcmd.Commands{Cmds: rec.Scripts[name]}
+		c.Shell.Scripts[name] = &Script{Commands: cmds, Inputs: rec.ScriptInputs[name]}
+		c.ast.setScript(name, rec.Scripts[name], rec.ScriptInputs[name])
+	}
+}
+
 func Open(projectDir string) (*Config, error) {
+	cfg, err := open(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.loadDefaults(); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadLocal(projectDir); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func open(projectDir string) (*Config, error) {
 	cfgPath := filepath.Join(projectDir, defaultName)
 
+	// devbox.json takes precedence. Fallback to YAML if it doesn't exist.
+	if !fileutil.Exists(cfgPath) {
+		for _, name := range []string{defaultYAMLName, defaultYMLName} {
+			if path := filepath.Join(projectDir, name); fileutil.Exists(path) {
+				return Load(path)
+			}
+		}
+	}
+
 	if !featureflag.TySON.Enabled() {
 		return Load(cfgPath)
 	}
@@ -102,8 +263,75 @@ func Open(projectDir string) (*Config, error) {
 			return nil, err
 		}
 		config.format = tsonFormat
+		config.name = defaultTySONName
 		return config, nil
 	}
 
 	return Load(cfgPath)
 }
+
+// appendInitHook appends commands to the shell.init_hook array of a config
+// created by DefaultConfig.
+func (c *configAST) appendInitHook(cmds []string) {
+	arr := c.defaultShellMember("init_hook").(*hujson.Array)
+	for _, cmd := range cmds {
+		arr.Elements = append(arr.Elements, hujson.Value{
+			Value:       hujson.String(cmd),
+			BeforeExtra: []byte{'\n'},
+		})
+	}
+	c.root.Format()
+}
+
+// setScript sets a script in the shell.scripts object of a config created by
+// DefaultConfig. A script with a single command is written as a string, and a
+// script with inputs is written as an object.
+func (c *configAST) setScript(name string, cmds, inputs []string) {
+	scripts := c.defaultShellMember("scripts").(*hujson.Object)
+	value := hujson.Value{Value: hujson.String(cmds[0])}
+	if len(cmds) > 1 {
+		value = hujson.Value{Value: stringArray(cmds)}
+	}
+	if len(inputs) > 0 {
+		value = hujson.Value{Value: &hujson.Object{Members: []hujson.ObjectMember{
+			{
+				Name:  hujson.Value{Value: hujson.String("command"), BeforeExtra: []byte{'\n'}},
+				Value: value,
+			},
+			{
+				Name:  hujson.Value{Value: hujson.String("inputs"), BeforeExtra: []byte{'\n'}},
+				Value: hujson.Value{Value: stringArray(inputs)},
+			},
+		}}}
+	}
+	if i := c.memberIndex(scripts, name); i != -1 {
+		scripts.Members[i].Value = value
+	} else {
+		scripts.Members = append(scripts.Members, hujson.ObjectMember{
+			Name: hujson.Value{
+				Value:       hujson.String(name),
+				BeforeExtra: []byte{'\n'},
+			},
+			Value: value,
+		})
+	}
+	c.root.Format()
+}
+
+// stringArray returns an array of strings with one element per line.
+func stringArray(vals []string) *hujson.Array {
+	arr := &hujson.Array{}
+	for _, v := range vals {
+		arr.Elements = append(arr.Elements, hujson.Value{
+			Value:       hujson.String(v),
+			BeforeExtra: []byte{'\n'},
+		})
+	}
+	return arr
+}
+
+func (c *configAST) defaultShellMember(key string) hujson.ValueTrimmed {
+	rootObject := c.root.Value.(*hujson.Object)
+	shell := rootObject.Members[c.memberIndex(rootObject, "shell")].Value.Value.(*hujson.Object)
+	return shell.Members[c.memberIndex(shell, key)].Value.Value
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestInitRecommendedEnv(t *testing.T) {
+	dir := t.TempDir()
+	gomod := "module example.com/foo\n\ngo 1.21\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Init(dir, io.Discard); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	want := map[string]string{
+		"GOBIN":  "{{.DevboxDir}}/go/bin",
+		"GOPATH": "{{.DevboxDir}}/go",
+		"PATH":   "{{.DevboxDir}}/go/bin:$PATH",
+	}
+	if diff := cmp.Diff(want, cfg.Env); diff != "" {
+		t.Errorf("wrong env (-want +got):\n%s", diff)
+	}
+}
+
+func TestInitRecommendedHookAndScripts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("flask\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Init(dir, io.Discard); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	wantHook := DefaultInitHook + "\n. $VENV_DIR/bin/activate"
+	if got := cfg.InitHook().String(); got != wantHook {
+		t.Errorf("got init hook %q, want %q", got, wantHook)
+	}
+	scripts := cfg.Scripts()
+	if got, want := scripts["install"].String(), "pip install -r requirements.txt"; got != want {
+		t.Errorf("got install script %q, want %q", got, want)
+	}
+	if diff := cmp.Diff([]string{"requirements.txt"}, scripts["install"].Inputs); diff != "" {
+		t.Errorf("wrong install script inputs (-want +got):\n%s", diff)
+	}
+	if _, ok := scripts["test"]; !ok {
+		t.Error("default test script is missing")
+	}
+}
+
+func TestInitMultipleProjects(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.21\n",
+		"package.json": `{"scripts": {"build": "vite build"}}`,
+	}
+	fileutil.WriteFilesForTest(t, dir, files)
+	var out strings.Builder
+	if _, err := Init(dir, &out); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "We detected a Go and Node.js project") {
+		t.Errorf("got output %q, want it to mention the Go and Node.js projects", out.String())
+	}
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	want := []string{"go build ./...", "npm run build"}
+	if diff := cmp.Diff(want, cfg.Scripts()["build"].Cmds); diff != "" {
+		t.Errorf("wrong build script (-want +got):\n%s", diff)
+	}
+}
+
+func TestInitAddsSelectedPackages(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Gemfile"), []byte("source 'https://rubygems.org'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	opts := InitOpts{SelectPackages: func(pkgs []string) ([]string, error) {
+		return pkgs[:1], nil
+	}}
+	if _, err := InitWithOpts(dir, opts, &out); err != nil {
+		t.Fatalf("InitWithOpts() error = %v", err)
+	}
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"ruby"}, cfg.Packages.VersionedNames()); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(out.String(), "devbox add gcc gnumake") {
+		t.Errorf("got output %q, want it to suggest the packages that weren't selected", out.String())
+	}
+}
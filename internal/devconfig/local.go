@@ -0,0 +1,132 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+// localName is an optional, per-user config file that lives next to
+// devbox.json. It is meant to be gitignored so that developers can add
+// personal packages, env vars, hooks or scripts without modifying the shared
+// devbox.json.
+const localName = "devbox.local.json"
+
+// loadLocal loads devbox.local.json from projectDir (if it exists) and merges
+// it over c. The merged values are only held in memory: they are never written
+// back to devbox.json when the config is saved.
+func (c *Config) loadLocal(projectDir string) error {
+	path := filepath.Join(projectDir, localName)
+	if !fileutil.Exists(path) {
+		return nil
+	}
+	local, err := Load(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", localName)
+	}
+	c.mergeLocal(local)
+	return nil
+}
+
+// mergeLocal merges the local overrides into c:
+//
+//   - packages that aren't already in c are kept apart in LocalPackages, so
+//     that they don't end up in devbox.lock.
+//   - env vars, secrets and scripts in local take precedence over those in c.
+//   - keep_env entries that aren't already in c are appended.
+//   - init hook commands in local run after the ones in c.
+//   - include entries that aren't already in c are appended.
+//   - nix substituters, trusted keys and builders that aren't already in c are appended,
+//     and nix build settings in local take precedence.
+func (c *Config) mergeLocal(local *Config) {
+	c.local = local
+
+	c.addLocalPackages(local.Packages.Collection, true)
+
+	if len(local.Env) > 0 {
+		if c.Env == nil {
+			c.Env = map[string]string{}
+		}
+		maps.Copy(c.Env, local.Env)
+	}
+
+	if len(local.Secrets) > 0 {
+		if c.Secrets == nil {
+			c.Secrets = map[string]string{}
+		}
+		maps.Copy(c.Secrets, local.Secrets)
+	}
+
+	for _, name := range local.KeepEnv {
+		if !slices.Contains(c.KeepEnv, name) {
+			c.KeepEnv = append(c.KeepEnv, name)
+		}
+	}
+
+	if local.Shell != nil {
+		if c.Shell == nil {
+			c.Shell = &shellConfig{}
+		}
+		if hook := local.InitHook(); hook != nil && len(hook.Cmds) > 0 {
+			if c.Shell.InitHook == nil {
+				c.Shell.InitHook = &shellcmd.Commands{}
+			}
+			c.Shell.InitHook.Cmds = append(c.Shell.InitHook.Cmds, hook.Cmds...)
+		}
+		if len(local.Shell.Scripts) > 0 {
+			if c.Shell.Scripts == nil {
+				c.Shell.Scripts = map[string]*Script{}
+			}
+			maps.Copy(c.Shell.Scripts, local.Shell.Scripts)
+		}
+	}
+
+	for _, include := range local.Include {
+		if !slices.Contains(c.Include, include) {
+			c.Include = append(c.Include, include)
+		}
+	}
+
+	c.mergeNix(local.Nix, true)
+}
+
+// LocalFileName returns the base name of the project's devbox.local.json, or
+// an empty string if the project doesn't have one.
+func (c *Config) LocalFileName() string {
+	if c.local == nil {
+		return ""
+	}
+	return c.local.FileName()
+}
+
+// LocalPackages returns the packages from devbox.local.json and the
+// user-level defaults that aren't in devbox.json.
+func (c *Config) LocalPackages() []Package {
+	return c.localPackages
+}
+
+// addLocalPackages adds the packages that aren't already in c, by name, to
+// c.localPackages. If override is true, they replace the local packages with
+// the same name.
+func (c *Config) addLocalPackages(pkgs []Package, override bool) {
+	for _, pkg := range pkgs {
+		sameName := func(p Package) bool { return p.name == pkg.name }
+		if slices.ContainsFunc(c.Packages.Collection, sameName) {
+			continue
+		}
+		if i := slices.IndexFunc(c.localPackages, sameName); i >= 0 {
+			if override {
+				c.localPackages[i] = pkg
+			}
+			continue
+		}
+		c.localPackages = append(c.localPackages, pkg)
+	}
+}
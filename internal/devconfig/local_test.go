@@ -0,0 +1,112 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOpenMergesLocalConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultName), `{
+  "packages": ["go@1.21", "hello"],
+  "env": {"FOO": "shared", "BAR": "shared"},
+  "shell": {
+    "init_hook": "echo shared",
+    "scripts": {"test": "go test ./..."}
+  }
+}`)
+	writeFile(t, filepath.Join(dir, localName), `{
+  // Personal tools that shouldn't be committed.
+  "packages": ["ripgrep", "hello@2.12"],
+  "env": {"FOO": "local"},
+  "shell": {
+    "init_hook": ["echo local"],
+    "scripts": {"test": "go test -v ./...", "lint": "golangci-lint run"}
+  }
+}`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	wantPkgs := []string{"go@1.21", "hello"}
+	if diff := cmp.Diff(wantPkgs, cfg.Packages.VersionedNames()); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantLocalPkgs := []string{"ripgrep"}
+	gotLocalPkgs := []string{}
+	for _, pkg := range cfg.LocalPackages() {
+		gotLocalPkgs = append(gotLocalPkgs, pkg.VersionedName())
+	}
+	if diff := cmp.Diff(wantLocalPkgs, gotLocalPkgs); diff != "" {
+		t.Errorf("wrong local packages (-want +got):\n%s", diff)
+	}
+	wantEnv := map[string]string{"FOO": "local", "BAR": "shared"}
+	if diff := cmp.Diff(wantEnv, cfg.Env); diff != "" {
+		t.Errorf("wrong env (-want +got):\n%s", diff)
+	}
+	projectEnv, err := cfg.ProjectEnv()
+	if err != nil {
+		t.Fatalf("ProjectEnv() error = %v", err)
+	}
+	wantProjectEnv := map[string]string{"FOO": "shared", "BAR": "shared"}
+	if diff := cmp.Diff(wantProjectEnv, projectEnv); diff != "" {
+		t.Errorf("wrong project env (-want +got):\n%s", diff)
+	}
+	if got, want := cfg.InitHook().String(), "echo shared\necho local"; got != want {
+		t.Errorf("got init hook %q, want %q", got, want)
+	}
+	scripts := cfg.Scripts()
+	if got, want := scripts["test"].String(), "go test -v ./..."; got != want {
+		t.Errorf("got test script %q, want %q", got, want)
+	}
+	if _, ok := scripts["lint"]; !ok {
+		t.Error("got no lint script from local config")
+	}
+}
+
+func TestLocalConfigIsNotSaved(t *testing.T) {
+	dir := t.TempDir()
+	shared := `{
+  "packages": ["go@1.21"]
+}`
+	writeFile(t, filepath.Join(dir, defaultName), shared)
+	writeFile(t, filepath.Join(dir, localName), `{"packages": ["ripgrep"], "env": {"FOO": "local"}}`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	sharedCfg, err := Load(filepath.Join(dir, defaultName))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Equals(sharedCfg) {
+		t.Error("got equal hashes for configs with and without local overrides")
+	}
+
+	if err := cfg.SaveTo(dir); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, defaultName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(shared, string(got)); diff != "" {
+		t.Errorf("local overrides were saved to devbox.json (-want +got):\n%s", diff)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/tailscale/hujson"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+// CurrentVersion is the schema version of devbox.json written by this version
+// of devbox. A config without a "version" field is version 0.
+//
+// Increment it when fields are renamed or restructured, and add a migration to
+// migrations that upgrades older configs.
+const CurrentVersion = 1
+
+// migration upgrades the syntax tree of a config to a newer schema version.
+type migration struct {
+	// version is the schema version that the migration upgrades to.
+	version int
+
+	// description explains the change to the user.
+	description string
+
+	// apply modifies the config and reports whether anything changed.
+	apply func(ast *configAST) bool
+}
+
+var migrations = []migration{
+	{
+		version:     1,
+		description: `Removed the unused "install_stage", "build_stage" and "start_stage" fields`,
+		apply: func(ast *configAST) bool {
+			return ast.removeRootFields("install_stage", "build_stage", "start_stage")
+		},
+	},
+	{
+		version:     1,
+		description: `Renamed "env_from": "envsec" to "env_from": "jetpack-cloud"`,
+		apply: func(ast *configAST) bool {
+			return ast.replaceRootString("env_from", "envsec", "jetpack-cloud")
+		},
+	},
+}
+
+// Migrate upgrades the config in projectDir to CurrentVersion, saving it in
+// place. It returns a description of each change that was made. Migrate
+// works on the raw file so that it can fix configs that no longer load.
+func Migrate(projectDir string) ([]string, error) {
+	path := configPath(projectDir)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	name := filepath.Base(path)
+	src := b
+	if isYAMLName(name) {
+		if b, err = yamlToJSON(b); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+	}
+
+	ast, err := parseConfig(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", name)
+	}
+	if ast.root.Value.Kind() != '{' {
+		return nil, usererr.New("%s must contain a JSON object", name)
+	}
+	version, err := ast.version()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(name, version); err != nil {
+		return nil, err
+	}
+
+	changes := applyMigrations(ast, version)
+	if version == CurrentVersion {
+		return changes, nil
+	}
+	ast.setVersion(CurrentVersion)
+	changes = append(changes, "Set the schema version to "+strconv.Itoa(CurrentVersion))
+
+	// Make sure the result is a valid config before overwriting the file.
+	b = bytes.ReplaceAll(ast.root.Pack(), []byte("\t"), []byte("  "))
+	if ast, err = parseConfig(b); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := schemaUserError(name, b, validateSchema(ast), !isYAMLName(name)); err != nil {
+		return nil, err
+	}
+	if _, err := loadBytes(b); err != nil {
+		return nil, err
+	}
+	if isYAMLName(name) {
+		if b, err = patchYAML(src, b); err != nil {
+			return nil, err
+		}
+	}
+	return changes, errors.WithStack(os.WriteFile(path, b, 0o644))
+}
+
+// NeedsMigration reports whether the config uses fields that a migration
+// would change. It doesn't consider a missing version field alone to be a
+// reason to migrate.
+func (c *Config) NeedsMigration() bool {
+	if c == nil || c.ast == nil || c.Version >= CurrentVersion {
+		return false
+	}
+	// Apply the migrations to a copy to avoid modifying the config.
+	ast, err := parseConfig(c.ast.root.Pack())
+	if err != nil {
+		return false
+	}
+	return len(applyMigrations(ast, c.Version)) > 0
+}
+
+func applyMigrations(ast *configAST, fromVersion int) []string {
+	changes := []string{}
+	for _, m := range migrations {
+		if m.version > fromVersion && m.apply(ast) {
+			changes = append(changes, m.description)
+		}
+	}
+	if len(changes) > 0 {
+		ast.root.Format()
+	}
+	return changes
+}
+
+// checkVersion returns an error if a config's schema version is newer than
+// this version of devbox supports.
+func checkVersion(name string, version int) error {
+	if version > CurrentVersion {
+		return usererr.New(
+			"%s uses schema version %d, but this version of devbox only supports "+
+				"versions up to %d. Please run `devbox version update` to update devbox.",
+			name, version, CurrentVersion,
+		)
+	}
+	return nil
+}
+
+// configPath returns the path of the config file in projectDir, following the
+// same precedence as Open.
+func configPath(projectDir string) string {
+	path := filepath.Join(projectDir, defaultName)
+	if !fileutil.Exists(path) {
+		for _, name := range []string{defaultYAMLName, defaultYMLName} {
+			if p := filepath.Join(projectDir, name); fileutil.Exists(p) {
+				return p
+			}
+		}
+	}
+	return path
+}
+
+// version returns the value of the root "version" field, or 0 if there isn't
+// one.
+func (c *configAST) version() (int, error) {
+	rootObject := c.root.Value.(*hujson.Object)
+	i := c.memberIndex(rootObject, "version")
+	if i == -1 {
+		return 0, nil
+	}
+	lit, ok := rootObject.Members[i].Value.Value.(hujson.Literal)
+	if !ok || lit.Kind() != '0' {
+		return 0, usererr.New(`"version" must be a number`)
+	}
+	version, err := strconv.Atoi(string(lit))
+	if err != nil {
+		return 0, usererr.New(`"version" must be a whole number, but got %s`, lit)
+	}
+	return version, nil
+}
+
+// setVersion sets the root "version" field, adding it to the top of the config
+// if necessary.
+func (c *configAST) setVersion(version int) {
+	rootObject := c.root.Value.(*hujson.Object)
+	value := hujson.Value{Value: hujson.Int(int64(version))}
+	if i := c.memberIndex(rootObject, "version"); i != -1 {
+		rootObject.Members[i].Value.Value = value.Value
+		return
+	}
+
+	// Keep "$schema" first since editors expect to find it there.
+	at := 0
+	if len(rootObject.Members) > 0 && rootObject.Members[0].Name.Value.(hujson.Literal).String() == "$schema" {
+		at = 1
+	}
+	rootObject.Members = slices.Insert(rootObject.Members, at, hujson.ObjectMember{
+		Name: hujson.Value{
+			Value:       hujson.String("version"),
+			BeforeExtra: []byte{'\n'},
+		},
+		Value: value,
+	})
+	c.root.Format()
+}
+
+// removeRootFields removes the given fields from the root object and reports
+// whether any of them existed.
+func (c *configAST) removeRootFields(names ...string) bool {
+	rootObject := c.root.Value.(*hujson.Object)
+	n := len(rootObject.Members)
+	rootObject.Members = slices.DeleteFunc(rootObject.Members, func(m hujson.ObjectMember) bool {
+		return slices.Contains(names, m.Name.Value.(hujson.Literal).String())
+	})
+	return len(rootObject.Members) != n
+}
+
+// replaceRootString replaces the value of a root string field if it's equal
+// to oldValue.
+func (c *configAST) replaceRootString(name, oldValue, newValue string) bool {
+	rootObject := c.root.Value.(*hujson.Object)
+	i := c.memberIndex(rootObject, name)
+	if i == -1 {
+		return false
+	}
+	lit, ok := rootObject.Members[i].Value.Value.(hujson.Literal)
+	if !ok || lit.Kind() != '"' || lit.String() != oldValue {
+		return false
+	}
+	rootObject.Members[i].Value.Value = hujson.String(newValue)
+	return true
+}
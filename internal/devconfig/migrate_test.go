@@ -0,0 +1,113 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultName), `{
+  "$schema": "https://raw.githubusercontent.com/jetpack-io/devbox/main/.schema/devbox.schema.json",
+  // Secrets for the dev environment.
+  "env_from": "envsec",
+  "packages": ["go@1.21"],
+  "install_stage": {"command": "go mod download"}
+}`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !cfg.NeedsMigration() {
+		t.Error("got NeedsMigration() = false for a config with legacy fields")
+	}
+
+	changes, err := Migrate(dir)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(changes) != 3 {
+		t.Errorf("got %d changes, want 3:\n%s", len(changes), strings.Join(changes, "\n"))
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, defaultName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "https://raw.githubusercontent.com/jetpack-io/devbox/main/.schema/devbox.schema.json",
+  "version": 1,
+  // Secrets for the dev environment.
+  "env_from": "jetpack-cloud",
+  "packages": ["go@1.21"],
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong migrated config (-want +got):\n%s", diff)
+	}
+
+	cfg, err = Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("got version %d, want %d", cfg.Version, CurrentVersion)
+	}
+	if cfg.NeedsMigration() {
+		t.Error("got NeedsMigration() = true after migrating")
+	}
+
+	// Migrating again shouldn't change anything.
+	changes, err = Migrate(dir)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("got changes for an up to date config:\n%s", strings.Join(changes, "\n"))
+	}
+}
+
+func TestMigrateYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultYAMLName), `packages:
+  - go@1.21
+build_stage:
+  command: go build
+`)
+
+	if _, err := Migrate(dir); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, defaultYAMLName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `version: 1
+packages:
+  - go@1.21
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong migrated config (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultName), `{"version": 1000, "packages": []}`)
+
+	_, err := Open(dir)
+	if err == nil || !strings.Contains(err.Error(), "schema version 1000") {
+		t.Errorf("got error %v, want an error about the schema version", err)
+	}
+	if _, err := Migrate(dir); err == nil {
+		t.Error("got nil error migrating a config with a newer version")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import "slices"
+
+// NixConfig holds the nix.conf settings that devbox passes to nix when it
+// installs the project's packages:
+//
+//	"nix": {
+//	  "substituters": ["https://my-team.cachix.org"],
+//	  "trusted_public_keys": ["my-team.cachix.org-1:..."]
+//	}
+type NixConfig struct {
+	// Substituters are binary caches that nix uses in addition to the
+	// ones in nix.conf, such as a Cachix cache.
+	Substituters []string `json:"substituters,omitempty"`
+
+	// TrustedPublicKeys are the public keys that sign the binaries in
+	// Substituters.
+	TrustedPublicKeys []string `json:"trusted_public_keys,omitempty"`
+
+	// Offline stops nix from using the network, like devbox shell
+	// --offline. It's usually set in devbox.local.json or the user-level
+	// defaults rather than in devbox.json.
+	Offline bool `json:"offline,omitempty"`
+
+	// MaxJobs, Cores and HTTPConnections set nix's max-jobs, cores and
+	// http-connections. When they're 0, devbox picks values for the
+	// machine unless nix.conf sets them.
+	MaxJobs         int `json:"max_jobs,omitempty"`
+	Cores           int `json:"cores,omitempty"`
+	HTTPConnections int `json:"http_connections,omitempty"`
+
+	// Builders are remote machines that nix can build packages on, such
+	// as a Linux machine that builds Linux-only packages for macOS users.
+	Builders []NixBuilder `json:"builders,omitempty"`
+}
+
+// NixBuilder is a remote machine that nix builds packages on over SSH.
+type NixBuilder struct {
+	// URI is the machine's address, such as ssh://builder.example.com or
+	// ssh-ng://me@builder.
+	URI string `json:"uri"`
+
+	// Systems are the platforms the machine builds for, such as
+	// x86_64-linux. Nix assumes the local machine's system if it's empty.
+	Systems []string `json:"systems,omitempty"`
+
+	// SSHKey is the path of the SSH private key for the machine.
+	SSHKey string `json:"ssh_key,omitempty"`
+
+	// MaxJobs is how many builds nix runs on the machine at once.
+	MaxJobs int `json:"max_jobs,omitempty"`
+
+	// SpeedFactor ranks the machine against the other builders. Nix
+	// prefers builders with a higher speed factor.
+	SpeedFactor int `json:"speed_factor,omitempty"`
+
+	// Features are the machine's supported system features, such as kvm
+	// or big-parallel.
+	Features []string `json:"features,omitempty"`
+}
+
+// NixSettings returns the project's nix settings, including the ones from
+// devbox.local.json and the user-level defaults.
+func (c *Config) NixSettings() NixConfig {
+	if c == nil || c.Nix == nil {
+		return NixConfig{}
+	}
+	return *c.Nix
+}
+
+// mergeNix appends the settings and builders (by URI) in other that c
+// doesn't already have. Either config can turn on offline mode. The numeric
+// settings in other replace the ones in c if override is true, and otherwise
+// only fill in the ones c doesn't set.
+func (c *Config) mergeNix(other *NixConfig, override bool) {
+	if other == nil {
+		return
+	}
+	if c.Nix == nil {
+		c.Nix = &NixConfig{}
+	}
+	c.Nix.Substituters = appendMissing(c.Nix.Substituters, other.Substituters)
+	c.Nix.TrustedPublicKeys = appendMissing(c.Nix.TrustedPublicKeys, other.TrustedPublicKeys)
+	for _, b := range other.Builders {
+		if !slices.ContainsFunc(c.Nix.Builders, func(existing NixBuilder) bool { return existing.URI == b.URI }) {
+			c.Nix.Builders = append(c.Nix.Builders, b)
+		}
+	}
+	c.Nix.Offline = c.Nix.Offline || other.Offline
+	mergeInt(&c.Nix.MaxJobs, other.MaxJobs, override)
+	mergeInt(&c.Nix.Cores, other.Cores, override)
+	mergeInt(&c.Nix.HTTPConnections, other.HTTPConnections, override)
+}
+
+func mergeInt(dst *int, src int, override bool) {
+	if src != 0 && (override || *dst == 0) {
+		*dst = src
+	}
+}
+
+func appendMissing(dst, src []string) []string {
+	for _, s := range src {
+		if !slices.Contains(dst, s) {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
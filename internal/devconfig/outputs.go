@@ -0,0 +1,43 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"slices"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/searcher"
+)
+
+// knownOutputs are the output names that nixpkgs commonly uses. A package
+// name ending in one of them, such as openssl.dev, selects that output.
+var knownOutputs = []string{"bin", "debug", "dev", "devdoc", "doc", "info", "lib", "man", "out", "static"}
+
+// SplitOutputSuffix splits a package name like openssl.dev or openssl.dev@3.1
+// into the package (openssl or openssl@3.1) and the outputs to install. The
+// default out output is kept along with the selected one, so the package's
+// programs and libraries are still installed. Flakes and names that don't
+// end in a known output are returned unchanged.
+func SplitOutputSuffix(raw string) (pkg string, outputs []string) {
+	if strings.ContainsAny(raw, ":#^") || strings.HasPrefix(raw, ".") || strings.HasPrefix(raw, "/") {
+		return raw, nil
+	}
+	name, version, versioned := searcher.ParseVersionedPackage(raw)
+	if !versioned {
+		name = raw
+	}
+	i := strings.LastIndexByte(name, '.')
+	if i <= 0 || !slices.Contains(knownOutputs, name[i+1:]) {
+		return raw, nil
+	}
+	output := name[i+1:]
+	pkg = name[:i]
+	if versioned {
+		pkg += "@" + version
+	}
+	if output == "out" {
+		return pkg, []string{"out"}
+	}
+	return pkg, []string{"out", output}
+}
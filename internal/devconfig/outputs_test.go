@@ -0,0 +1,32 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitOutputSuffix(t *testing.T) {
+	cases := []struct {
+		raw         string
+		wantPkg     string
+		wantOutputs []string
+	}{
+		{"openssl.dev", "openssl", []string{"out", "dev"}},
+		{"openssl.dev@3.1", "openssl@3.1", []string{"out", "dev"}},
+		{"curl.out", "curl", []string{"out"}},
+		{"python3.11", "python3.11", nil},
+		{"nodePackages.npm@latest", "nodePackages.npm@latest", nil},
+		{"github:nixos/nixpkgs#libcap.dev", "github:nixos/nixpkgs#libcap.dev", nil},
+		{"./flake.dev", "./flake.dev", nil},
+	}
+	for _, tc := range cases {
+		gotPkg, gotOutputs := SplitOutputSuffix(tc.raw)
+		if gotPkg != tc.wantPkg || !slices.Equal(gotOutputs, tc.wantOutputs) {
+			t.Errorf("got SplitOutputSuffix(%q) = %q, %v, want %q, %v",
+				tc.raw, gotPkg, gotOutputs, tc.wantPkg, tc.wantOutputs)
+		}
+	}
+}
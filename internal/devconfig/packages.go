@@ -62,8 +62,9 @@ func (pkgs *Packages) Remove(versionedName string) {
 	if i == -1 {
 		return
 	}
+	key := pkgs.Collection[i].configKey()
 	pkgs.Collection = slices.Delete(pkgs.Collection, i, i+1)
-	pkgs.ast.removePackage(name)
+	pkgs.ast.removePackage(key)
 }
 
 // AddPlatforms adds a platform to the list of platforms for a given package
@@ -100,7 +101,7 @@ func (pkgs *Packages) AddPlatforms(writer io.Writer, versionedname string, platf
 		}
 	}
 	if len(pkg.Platforms) > oldLen {
-		pkgs.ast.appendPlatforms(pkg.name, "platforms", pkg.Platforms[oldLen:])
+		pkgs.ast.appendPlatforms(pkg.configKey(), "platforms", pkg.Platforms[oldLen:])
 		ux.Finfo(writer,
 			"Added platform %s to package %s\n", strings.Join(platforms, ", "),
 			pkg.VersionedName(),
@@ -140,7 +141,7 @@ func (pkgs *Packages) ExcludePlatforms(writer io.Writer, versionedName string, p
 		}
 	}
 	if len(pkg.ExcludedPlatforms) > oldLen {
-		pkgs.ast.appendPlatforms(pkg.name, "excluded_platforms", pkg.ExcludedPlatforms[oldLen:])
+		pkgs.ast.appendPlatforms(pkg.configKey(), "excluded_platforms", pkg.ExcludedPlatforms[oldLen:])
 		ux.Finfo(writer, "Excluded platform %s for package %s\n", strings.Join(platforms, ", "),
 			pkg.VersionedName())
 	}
@@ -155,6 +156,22 @@ func (pkgs *Packages) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
+	// The list may also mix strings with package objects that have a name,
+	// such as {"name": "inotify-tools", "platforms": ["x86_64-linux"]}.
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err == nil {
+		packagesList := make([]Package, 0, len(elements))
+		for _, elem := range elements {
+			pkg, err := packageFromListElement(elem)
+			if err != nil {
+				return err
+			}
+			packagesList = append(packagesList, pkg)
+		}
+		pkgs.Collection = packagesList
+		return nil
+	}
+
 	// Second, attempt to unmarshal as a map of Packages
 	// We use orderedmap to preserve the order of the packages. While the JSON
 	// specification specifies that maps are unordered, we do rely on the order
@@ -171,6 +188,7 @@ func (pkgs *Packages) UnmarshalJSON(data []byte) error {
 	for pair := orderedMap.Oldest(); pair != nil; pair = pair.Next() {
 		pkg := pair.Value
 		pkg.name = pair.Key
+		pkg.applyOutputSuffix()
 		packagesList = append(packagesList, pkg)
 	}
 	pkgs.Collection = packagesList
@@ -185,7 +203,7 @@ func (pkgs *Packages) SetPatchGLibc(versionedName string, v bool) error {
 	}
 	if pkgs.Collection[i].PatchGlibc != v {
 		pkgs.Collection[i].PatchGlibc = v
-		pkgs.ast.setPackageBool(name, "patch_glibc", v)
+		pkgs.ast.setPackageBool(pkgs.Collection[i].configKey(), "patch_glibc", v)
 	}
 	return nil
 }
@@ -198,7 +216,7 @@ func (pkgs *Packages) SetDisablePlugin(versionedName string, v bool) error {
 	}
 	if pkgs.Collection[i].DisablePlugin != v {
 		pkgs.Collection[i].DisablePlugin = v
-		pkgs.ast.setPackageBool(name, "disable_plugin", v)
+		pkgs.ast.setPackageBool(pkgs.Collection[i].configKey(), "disable_plugin", v)
 	}
 	return nil
 }
@@ -219,7 +237,7 @@ func (pkgs *Packages) SetOutputs(writer io.Writer, versionedName string, outputs
 
 	if len(toAdd) > 0 {
 		pkg := &pkgs.Collection[i]
-		pkgs.ast.appendOutputs(pkg.name, "outputs", toAdd)
+		pkgs.ast.appendOutputs(pkg.configKey(), "outputs", toAdd)
 		ux.Finfo(writer, "Added outputs %s to package %s\n", strings.Join(toAdd, ", "), versionedName)
 	}
 	return nil
@@ -241,21 +259,38 @@ func (pkgs *Packages) SetAllowInsecure(writer io.Writer, versionedName string, w
 
 	if len(toAdd) > 0 {
 		pkg := &pkgs.Collection[i]
-		pkgs.ast.appendAllowInsecure(pkg.name, "allow_insecure", toAdd)
+		pkgs.ast.appendAllowInsecure(pkg.configKey(), "allow_insecure", toAdd)
 		pkg.AllowInsecure = append(pkg.AllowInsecure, toAdd...)
 		ux.Finfo(writer, "Allowed insecure %s for package %s\n", strings.Join(toAdd, ", "), versionedName)
 	}
 	return nil
 }
 
+// Groups returns the names of all groups used by the packages, in the order
+// they first appear.
+func (pkgs *Packages) Groups() []string {
+	groups := []string{}
+	for _, p := range pkgs.Collection {
+		for _, g := range p.Groups {
+			if !slices.Contains(groups, g) {
+				groups = append(groups, g)
+			}
+		}
+	}
+	return groups
+}
+
 func (pkgs *Packages) index(name, version string) int {
 	return slices.IndexFunc(pkgs.Collection, func(p Package) bool {
-		return p.name == name && p.Version == version
+		return (p.name == name || p.key == name) && p.Version == version
 	})
 }
 
 type Package struct {
-	name    string
+	name string
+	// key is the name as written in devbox.json when it differs from name,
+	// such as openssl.dev for the openssl package with the dev output.
+	key     string
 	Version string `json:"version,omitempty"`
 
 	DisablePlugin     bool     `json:"disable_plugin,omitempty"`
@@ -273,6 +308,21 @@ type Package struct {
 	// AllowInsecure is a whitelist of packages that may be marked insecure
 	// in nixpkgs, but are allowed by the user to be installed.
 	AllowInsecure []string `json:"allow_insecure,omitempty"`
+
+	// Groups are the names of the groups that this package belongs to, such
+	// as "dev" or "test". Groups can be selected or skipped with the --only
+	// and --skip flags. Packages without groups are always included.
+	Groups []string `json:"groups,omitempty"`
+
+	// Channel is the nixpkgs channel, such as nixos-unstable, or the nixpkgs
+	// commit to install the package from instead of the Devbox search index.
+	// The package name is then a nixpkgs attribute path.
+	Channel string `json:"channel,omitempty"`
+
+	// Instances are names of separate copies of the package's plugin, such
+	// as "legacy". Each instance has its own data directory, ports, and
+	// env variables prefixed with its name, such as LEGACY_PGPORT.
+	Instances []string `json:"instances,omitempty"`
 }
 
 func NewVersionOnlyPackage(name, version string) Package {
@@ -307,6 +357,18 @@ func NewPackage(name string, values map[string]any) Package {
 	if a, ok := values["allow_insecure"]; ok {
 		allowInsecure = a.([]string)
 	}
+	var groups []string
+	if g, ok := values["groups"]; ok {
+		groups = g.([]string)
+	}
+	var channel string
+	if c, ok := values["channel"]; ok {
+		channel = c.(string)
+	}
+	var instances []string
+	if i, ok := values["instances"]; ok {
+		instances = i.([]string)
+	}
 
 	return Package{
 		name:              name,
@@ -315,6 +377,9 @@ func NewPackage(name string, values map[string]any) Package {
 		ExcludedPlatforms: excludedPlatforms,
 		Outputs:           outputs,
 		AllowInsecure:     allowInsecure,
+		Groups:            groups,
+		Channel:           channel,
+		Instances:         instances,
 	}
 }
 
@@ -340,6 +405,41 @@ func (p *Package) IsEnabledOnPlatform() bool {
 	return true
 }
 
+// packageFromVersionedName converts a list element like hello@2.12 or
+// openssl.dev to a package.
+func packageFromVersionedName(versionedName string) Package {
+	name, version := parseVersionedName(versionedName)
+	pkg := NewVersionOnlyPackage(name, version)
+	pkg.applyOutputSuffix()
+	return pkg
+}
+
+// applyOutputSuffix selects the output in a package name like openssl.dev,
+// the same way `devbox add openssl.dev` does, so the package is installed
+// with it. The name as written is kept to edit the package in devbox.json.
+func (p *Package) applyOutputSuffix() {
+	name, outputs := SplitOutputSuffix(p.name)
+	if outputs == nil {
+		return
+	}
+	p.key = p.name
+	p.name = name
+	for _, o := range p.Outputs {
+		if !slices.Contains(outputs, o) {
+			outputs = append(outputs, o)
+		}
+	}
+	p.Outputs = outputs
+}
+
+// configKey returns the package's name as written in devbox.json.
+func (p *Package) configKey() string {
+	if p.key != "" {
+		return p.key
+	}
+	return p.name
+}
+
 func (p *Package) VersionedName() string {
 	name := p.name
 	if p.Version != "" {
@@ -379,13 +479,47 @@ func parseVersionedName(versionedName string) (name, version string) {
 	return name, version
 }
 
+// packageFromListElement converts an element of a packages list to a
+// package. The element is either a versioned name or an object with a "name"
+// field, where the name may include a version.
+//
+// Example inputs: `"hello@2.12"`, `{"name": "hello@2.12"}` and
+// `{"name": "hello", "version": "2.12", "platforms": ["x86_64-linux"]}`
+func packageFromListElement(elem json.RawMessage) (Package, error) {
+	var versionedName string
+	if err := json.Unmarshal(elem, &versionedName); err == nil {
+		return packageFromVersionedName(versionedName), nil
+	}
+
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(elem, &named); err != nil {
+		return Package{}, errors.WithStack(err)
+	}
+	if named.Name == "" {
+		return Package{}, usererr.New("package %s in devbox.json is missing a name", elem)
+	}
+
+	pkg := Package{}
+	if err := json.Unmarshal(elem, &pkg); err != nil {
+		return Package{}, err
+	}
+	name, version := parseVersionedName(named.Name)
+	pkg.name = name
+	if pkg.Version == "" {
+		pkg.Version = version
+	}
+	pkg.applyOutputSuffix()
+	return pkg, nil
+}
+
 // packagesFromLegacyList converts a list of strings to a list of packages
 // Example inputs: `["python@latest", "hello", "cowsay@1"]`
 func packagesFromLegacyList(packages []string) []Package {
 	packagesList := []Package{}
 	for _, p := range packages {
-		name, version := parseVersionedName(p)
-		packagesList = append(packagesList, NewVersionOnlyPackage(name, version))
+		packagesList = append(packagesList, packageFromVersionedName(p))
 	}
 	return packagesList
 }
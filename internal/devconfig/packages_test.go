@@ -1,6 +1,7 @@
 package devconfig
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -36,6 +37,24 @@ func TestJsonifyConfigPackages(t *testing.T) {
 				Collection: packagesFromLegacyList([]string{"python", "hello@latest", "go@1.20"}),
 			},
 		},
+		{
+			name: "list-with-objects",
+			jsonConfig: `{"packages":["go@1.20",` +
+				`{"name":"inotify-tools","platforms":["x86_64-linux"]},` +
+				`{"name":"python@3.10","excluded_platforms":["aarch64-darwin"]}]}`,
+			expected: Packages{
+				Collection: []Package{
+					NewVersionOnlyPackage("go", "1.20"),
+					NewPackage("inotify-tools", map[string]any{
+						"platforms": []string{"x86_64-linux"},
+					}),
+					NewPackage("python", map[string]any{
+						"version":            "3.10",
+						"excluded_platforms": []string{"aarch64-darwin"},
+					}),
+				},
+			},
+		},
 		{
 			name:       "map-with-string-value",
 			jsonConfig: `{"packages":{"python":"latest","go":"1.20"}}`,
@@ -288,3 +307,37 @@ func TestParseVersionedName(t *testing.T) {
 		})
 	}
 }
+
+func TestOutputSuffixPackages(t *testing.T) {
+	testCases := map[string]string{
+		"map":  `{"packages":{"openssl.dev":"3","curl":"latest"}}`,
+		"list": `{"packages":["openssl.dev@3","curl@latest"]}`,
+	}
+	for name, jsonConfig := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config, err := loadBytes([]byte(jsonConfig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pkgs := &config.Packages
+			if diff := cmp.Diff([]string{"openssl@3", "curl@latest"}, pkgs.VersionedNames()); diff != "" {
+				t.Errorf("wrong package names (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]string{"out", "dev"}, pkgs.Collection[0].Outputs); diff != "" {
+				t.Errorf("wrong outputs (-want +got):\n%s", diff)
+			}
+
+			pkgs.Remove("openssl.dev@3")
+			if diff := cmp.Diff([]string{"curl@latest"}, pkgs.VersionedNames()); diff != "" {
+				t.Errorf("wrong package names after remove (-want +got):\n%s", diff)
+			}
+			got, err := hujson.Minimize(config.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(got), "openssl") {
+				t.Errorf("got config %s after remove, want openssl removed", got)
+			}
+		})
+	}
+}
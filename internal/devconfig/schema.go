@@ -0,0 +1,335 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tailscale/hujson"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
+)
+
+// legacyFields are top-level fields that older versions of devbox wrote to
+// devbox.json. They are no longer used, but we still accept them so that
+// existing projects keep working.
+var legacyFields = []string{
+	"$schema",
+	"install_stage",
+	"build_stage",
+	"start_stage",
+}
+
+var (
+	packagesType = reflect.TypeOf(Packages{})
+	packageType  = reflect.TypeOf(Package{})
+	commandsType = reflect.TypeOf(shellcmd.Commands{})
+	envFromType  = reflect.TypeOf(EnvFrom{})
+	scriptType   = reflect.TypeOf(Script{})
+)
+
+// schemaError is a value in a devbox config that doesn't match the schema
+// defined by the Config struct.
+type schemaError struct {
+	// path is the location of the value, such as "shell.init_hook".
+	path string
+	// offset is the byte offset of the value in the config file.
+	offset int
+	msg    string
+}
+
+// validateSchema checks the config's syntax tree against the fields and types
+// of the Config struct. Unlike encoding/json, it reports unknown fields (and
+// suggests the field that was likely meant) instead of ignoring them.
+func validateSchema(ast *configAST) []schemaError {
+	if ast.root.Value.Kind() != '{' {
+		return []schemaError{{
+			offset: ast.root.StartOffset,
+			msg:    "config must be a JSON object",
+		}}
+	}
+	return validateValue(&ast.root, reflect.TypeOf(Config{}), "")
+}
+
+func validateValue(v *hujson.Value, t reflect.Type, path string) []schemaError {
+	if t.Kind() == reflect.Pointer {
+		if v.Value.Kind() == 'n' {
+			return nil
+		}
+		t = t.Elem()
+	}
+
+	switch t {
+	case packagesType:
+		return validatePackages(v, path)
+	case packageType:
+		if v.Value.Kind() == '"' {
+			return nil
+		}
+		return validateStruct(v, t, path, "a version string or an object")
+	case scriptType:
+		switch v.Value.Kind() {
+		case '{':
+			return validateStruct(v, reflect.TypeOf(scriptObject{}), path, "an object")
+		case '"', 'n', '[':
+			return validateValue(v, commandsType, path)
+		}
+		return []schemaError{typeError(v, path, "a string, an array of strings or an object")}
+	case commandsType, envFromType:
+		switch v.Value.Kind() {
+		case '"', 'n':
+			return nil
+		case '[':
+			return validateValue(v, reflect.TypeOf([]string{}), path)
+		}
+		return []schemaError{typeError(v, path, "a string or an array of strings")}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return validateStruct(v, t, path, "an object")
+	case reflect.Map:
+		obj, ok := v.Value.(*hujson.Object)
+		if !ok {
+			return []schemaError{typeError(v, path, "an object")}
+		}
+		var errs []schemaError
+		for i := range obj.Members {
+			name := obj.Members[i].Name.Value.(hujson.Literal).String()
+			errs = append(errs, validateValue(&obj.Members[i].Value, t.Elem(), joinPath(path, name))...)
+		}
+		return errs
+	case reflect.Slice:
+		arr, ok := v.Value.(*hujson.Array)
+		if !ok {
+			return []schemaError{typeError(v, path, "an array")}
+		}
+		var errs []schemaError
+		for i := range arr.Elements {
+			errs = append(errs, validateValue(&arr.Elements[i], t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case reflect.String:
+		if v.Value.Kind() != '"' {
+			return []schemaError{typeError(v, path, "a string")}
+		}
+	case reflect.Bool:
+		if k := v.Value.Kind(); k != 't' && k != 'f' {
+			return []schemaError{typeError(v, path, "a boolean")}
+		}
+	case reflect.Int:
+		lit, ok := v.Value.(hujson.Literal)
+		if !ok || lit.Kind() != '0' {
+			return []schemaError{typeError(v, path, "a whole number")}
+		}
+		if _, err := strconv.Atoi(string(lit)); err != nil {
+			return []schemaError{typeError(v, path, "a whole number")}
+		}
+	}
+	return nil
+}
+
+func validateStruct(v *hujson.Value, t reflect.Type, path, want string) []schemaError {
+	obj, ok := v.Value.(*hujson.Object)
+	if !ok {
+		return []schemaError{typeError(v, path, want)}
+	}
+
+	fields := jsonFields(t)
+	var errs []schemaError
+	for i := range obj.Members {
+		member := &obj.Members[i]
+		name := member.Name.Value.(hujson.Literal).String()
+		field, ok := fields[name]
+		if !ok {
+			if path == "" && slices.Contains(legacyFields, name) {
+				continue
+			}
+			msg := fmt.Sprintf("unknown field %q", name)
+			if suggestion := closestName(name, fields); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			errs = append(errs, schemaError{
+				path:   joinPath(path, name),
+				offset: member.Name.StartOffset,
+				msg:    msg,
+			})
+			continue
+		}
+		errs = append(errs, validateValue(&member.Value, field.Type, joinPath(path, name))...)
+	}
+	return errs
+}
+
+// jsonFields returns the exported fields of a struct, keyed by their JSON
+// names.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func validatePackages(v *hujson.Value, path string) []schemaError {
+	switch v.Value.Kind() {
+	case 'n':
+		return nil
+	case '[':
+		var errs []schemaError
+		for i, elem := range v.Value.(*hujson.Array).Elements {
+			errs = append(errs, validatePackageElement(&elem, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case '{':
+		return validateValue(v, reflect.TypeOf(map[string]Package{}), path)
+	}
+	return []schemaError{typeError(v, path, "an array or an object")}
+}
+
+// validatePackageElement validates an element of a packages array, which is
+// either a versioned name or a package object with a "name" field.
+func validatePackageElement(v *hujson.Value, path string) []schemaError {
+	switch v.Value.Kind() {
+	case '"':
+		return nil
+	case '{':
+	default:
+		return []schemaError{typeError(v, path, "a package name or an object")}
+	}
+
+	obj := v.Value.(*hujson.Object)
+	i := slices.IndexFunc(obj.Members, func(m hujson.ObjectMember) bool {
+		return m.Name.Value.(hujson.Literal).String() == "name"
+	})
+	if i == -1 {
+		return []schemaError{{
+			path:   path,
+			offset: v.StartOffset,
+			msg:    `missing field "name"`,
+		}}
+	}
+	errs := validateValue(&obj.Members[i].Value, reflect.TypeOf(""), joinPath(path, "name"))
+
+	// Validate the remaining fields as a regular package object.
+	rest := *obj
+	rest.Members = slices.Delete(slices.Clone(obj.Members), i, i+1)
+	return append(errs, validateStruct(&hujson.Value{Value: &rest}, packageType, path, "an object")...)
+}
+
+func typeError(v *hujson.Value, path, want string) schemaError {
+	got := "a value"
+	switch v.Value.Kind() {
+	case '{':
+		got = "an object"
+	case '[':
+		got = "an array"
+	case '"':
+		got = "a string"
+	case '0':
+		got = "a number"
+	case 't', 'f':
+		got = "a boolean"
+	case 'n':
+		got = "null"
+	}
+	return schemaError{
+		path:   path,
+		offset: v.StartOffset,
+		msg:    fmt.Sprintf("expected %s, but got %s", want, got),
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// closestName returns the field name that is most similar to name, or an empty
+// string if none of the fields are similar enough to be a likely typo.
+func closestName(name string, fields map[string]reflect.StructField) string {
+	best, bestDist := "", 3 // Only suggest names within an edit distance of 2.
+	for candidate := range fields {
+		dist := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if dist < bestDist || (dist == bestDist && candidate < best) {
+			best, bestDist = candidate, dist
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// schemaUserError combines schema errors into a single user error. When
+// withPosition is true, each error includes the line and column of the
+// offending value in b.
+func schemaUserError(name string, b []byte, errs []schemaError, withPosition bool) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if name == "" {
+		name = defaultName
+	}
+
+	msg := &strings.Builder{}
+	fmt.Fprintf(msg, "%s is invalid:", name)
+	for _, err := range errs {
+		msg.WriteString("\n  ")
+		if withPosition {
+			line, col := lineColumn(b, err.offset)
+			fmt.Fprintf(msg, "%d:%d: ", line, col)
+		}
+		if err.path != "" {
+			fmt.Fprintf(msg, "%s: ", err.path)
+		}
+		msg.WriteString(err.msg)
+	}
+	return usererr.New("%s", msg.String())
+}
+
+// lineColumn converts a byte offset into a 1-based line and column.
+func lineColumn(b []byte, offset int) (line, col int) {
+	offset = min(offset, len(b))
+	before := b[:offset]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	col = offset - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+)
+
+func TestSchemaErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name: "misspelled field",
+			config: `{
+  "packages": ["go"],
+  "shell": {
+    "init_hooks": "echo hello"
+  }
+}`,
+			want: []string{`4:5: shell.init_hooks: unknown field "init_hooks", did you mean "init_hook"?`},
+		},
+		{
+			name: "unknown field",
+			config: `{
+  "packages": [],
+  "daemons": {}
+}`,
+			want: []string{`3:3: daemons: unknown field "daemons"`},
+		},
+		{
+			name: "wrong type",
+			config: `{
+  "packages": {
+    "go": {"version": 1.21}
+  },
+  "env": {"DEBUG": true}
+}`,
+			want: []string{
+				`3:23: packages.go.version: expected a string, but got a number`,
+				`5:20: env.DEBUG: expected a string, but got a boolean`,
+			},
+		},
+		{
+			name:   "package object without a name",
+			config: `{"packages": ["go", {"platforms": ["x86_64-linux"]}]}`,
+			want:   []string{`1:21: packages[1]: missing field "name"`},
+		},
+		{
+			name:   "misspelled script field",
+			config: `{"shell": {"scripts": {"test": {"commands": "go test"}}}}`,
+			want:   []string{`1:33: shell.scripts.test.commands: unknown field "commands", did you mean "command"?`},
+		},
+		{
+			name:   "misspelled service field",
+			config: `{"services": {"web": {"commnd": "npm start"}}}`,
+			want:   []string{`1:23: services.web.commnd: unknown field "commnd", did you mean "command"?`},
+		},
+		{
+			name:   "wrong script type",
+			config: `{"shell": {"scripts": {"test": 1}}}`,
+			want:   []string{`1:32: shell.scripts.test: expected a string, an array of strings or an object, but got a number`},
+		},
+		{
+			name:   "wrong allow_unfree type",
+			config: `{"nixpkgs": {"allow_unfree": "no"}}`,
+			want:   []string{`1:30: nixpkgs.allow_unfree: expected a boolean, but got a string`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), defaultName)
+			writeFile(t, path, tc.config)
+
+			_, err := Load(path)
+			if err == nil {
+				t.Fatal("got nil error for an invalid config")
+			}
+			if _, ok := usererr.Extract(err); !ok {
+				t.Errorf("got error %q, want a user error", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("got error:\n%s\nwant it to contain:\n%s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaAllowsValidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), defaultName)
+	writeFile(t, path, `{
+  "$schema": "https://raw.githubusercontent.com/jetpack-io/devbox/main/.schema/devbox.schema.json",
+  "packages": {
+    "go": "latest",
+    "glibcLocales": {
+      "version": "latest",
+      "platforms": ["x86_64-linux"],
+      "outputs": ["out"]
+    }
+  },
+  "env": {"FOO": "bar"},
+  "shell": {
+    "init_hook": null,
+    "scripts": {
+      "build": "go build",
+      "test": ["go vet ./...", "go test ./..."]
+    }
+  },
+  "nixpkgs": {"commit": "f80ac848e3d6f0c12c52758c0f25c10c97ca3b62"},
+  // Leftover from older versions of devbox.
+  "install_stage": {"command": "go mod download"}
+}`)
+	if _, err := Load(path); err != nil {
+		t.Errorf("Load() error = %v", err)
+	}
+}
+
+func TestSchemaErrorsYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), defaultYAMLName)
+	writeFile(t, path, `packages: [go]
+envs:
+  FOO: bar
+`)
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("got nil error for an invalid config")
+	}
+	want := `devbox.yaml is invalid:
+  envs: unknown field "envs", did you mean "env"?`
+	if err.Error() != want {
+		t.Errorf("got error:\n%s\nwant:\n%s", err, want)
+	}
+}
@@ -1,10 +1,87 @@
 package devconfig
 
-import "go.jetpack.io/devbox/internal/devbox/shellcmd"
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
+)
+
+// Script is an entry in shell.scripts. It's either the script's commands (as
+// a string or an array of strings) or an object with the commands and other
+// settings:
+//
+//	"test": {"command": "go test ./...", "depends_on": ["build"]}
+type Script struct {
+	shellcmd.Commands
+
+	// DependsOn lists scripts that run, in order, before this one.
+	DependsOn []string
+
+	// Env holds env variables that are only set for this script. They
+	// take precedence over the project's env variables.
+	Env map[string]string
+
+	// Dir is the directory the script runs in, relative to the project
+	// directory. It defaults to the project directory.
+	Dir string
+
+	// Inputs are the files (or globs) the script needs, relative to the
+	// project directory. Generated Dockerfiles copy only these files before
+	// running the script, so its image layer is cached until they change.
+	Inputs []string
+}
+
+// scriptObject is the object form of a Script.
+type scriptObject struct {
+	Command   *shellcmd.Commands `json:"command"`
+	DependsOn []string           `json:"depends_on,omitempty"`
+	Env       map[string]string  `json:"env,omitempty"`
+	Dir       string             `json:"dir,omitempty"`
+	Inputs    []string           `json:"inputs,omitempty"`
+}
+
+func (s *Script) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '{' {
+		*s = Script{}
+		return s.Commands.UnmarshalJSON(data)
+	}
+	var obj scriptObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return errors.WithStack(err)
+	}
+	*s = Script{DependsOn: obj.DependsOn, Env: obj.Env, Dir: obj.Dir, Inputs: obj.Inputs}
+	if obj.Command != nil {
+		s.Commands = *obj.Command
+	}
+	return nil
+}
+
+// MarshalJSON only uses the object form when the script has settings other
+// than its commands.
+func (s Script) MarshalJSON() ([]byte, error) {
+	if len(s.DependsOn) == 0 && len(s.Env) == 0 && s.Dir == "" && len(s.Inputs) == 0 {
+		return s.Commands.MarshalJSON()
+	}
+	return json.Marshal(scriptObject{
+		Command:   &s.Commands,
+		DependsOn: s.DependsOn,
+		Env:       s.Env,
+		Dir:       s.Dir,
+		Inputs:    s.Inputs,
+	})
+}
 
 type script struct {
 	shellcmd.Commands
-	Comments string
+	DependsOn []string
+	Env       map[string]string
+	Dir       string
+	Inputs    []string
+	Comments  string
 }
 
 type scripts map[string]*script
@@ -14,12 +91,53 @@ func (c *Config) Scripts() scripts {
 		return nil
 	}
 	result := make(scripts)
-	for name, commands := range c.Shell.Scripts {
+	for name, s := range c.Shell.Scripts {
 		result[name] = &script{
-			Commands: *commands,
-			Comments: string(c.ast.beforeComment("shell", "scripts", name)),
+			Commands:  s.Commands,
+			DependsOn: s.DependsOn,
+			Env:       s.Env,
+			Dir:       s.Dir,
+			Inputs:    s.Inputs,
+			Comments:  string(c.ast.beforeComment("shell", "scripts", name)),
 		}
 	}
 
 	return result
 }
+
+// ScriptDependencies returns the scripts that must run before the named
+// script, in the order they should run. Each script appears once, even if
+// several scripts depend on it.
+func (c *Config) ScriptDependencies(name string) ([]string, error) {
+	var order []string
+	err := visitScriptDependencies(c.Scripts(), name, nil, func(dep string) {
+		if dep != name && !slices.Contains(order, dep) {
+			order = append(order, dep)
+		}
+	})
+	return order, err
+}
+
+// visitScriptDependencies calls visit for each of a script's dependencies
+// (depth first) and then for the script itself. path holds the scripts that
+// are being visited and is used to detect cycles.
+func visitScriptDependencies(all scripts, name string, path []string, visit func(string)) error {
+	if i := slices.Index(path, name); i != -1 {
+		cycle := append(slices.Clone(path[i:]), name)
+		return usererr.New(
+			"Scripts in devbox.json have a dependency cycle: %s",
+			strings.Join(cycle, " -> "),
+		)
+	}
+	path = append(path, name)
+	for _, dep := range all[name].DependsOn {
+		if _, ok := all[dep]; !ok {
+			return usererr.New("Script %q in devbox.json depends on unknown script %q", name, dep)
+		}
+		if err := visitScriptDependencies(all, dep, path, visit); err != nil {
+			return err
+		}
+	}
+	visit(name)
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScriptDependencies(t *testing.T) {
+	cfg, err := loadBytes([]byte(`{
+  "shell": {
+    "scripts": {
+      "generate": "go generate ./...",
+      "build": {"command": "go build ./...", "depends_on": ["generate"]},
+      "lint": {"command": ["golangci-lint run"], "depends_on": ["generate"]},
+      "test": {"command": "go test ./...", "depends_on": ["build", "lint"]}
+    }
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cfg.ScriptDependencies("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"generate", "build", "lint"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong dependencies (-want +got):\n%s", diff)
+	}
+	if got := cfg.Scripts()["build"].String(); got != "go build ./..." {
+		t.Errorf("got build script %q, want %q", got, "go build ./...")
+	}
+
+	got, err = cfg.ScriptDependencies("generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got dependencies %v for a script without depends_on", got)
+	}
+}
+
+func TestScriptDependenciesErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		scripts string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			scripts: `{
+  "a": {"command": "echo a", "depends_on": ["b"]},
+  "b": {"command": "echo b", "depends_on": ["c"]},
+  "c": {"command": "echo c", "depends_on": ["a"]}
+}`,
+			wantErr: "dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:    "self",
+			scripts: `{"a": {"command": "echo a", "depends_on": ["a"]}}`,
+			wantErr: "dependency cycle: a -> a",
+		},
+		{
+			name:    "unknown",
+			scripts: `{"test": {"command": "go test", "depends_on": ["biuld"]}}`,
+			wantErr: `Script "test" in devbox.json depends on unknown script "biuld"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loadBytes([]byte(`{"shell": {"scripts": ` + tc.scripts + `}}`))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got error %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+)
+
+// The providers that the references in secrets can read from.
+const (
+	SecretProvider1Password = "op"
+	SecretProviderVault     = "vault"
+	SecretProviderSops      = "sops"
+)
+
+// SecretRef is where the value of a variable in secrets comes from. It's
+// written as one of:
+//
+//	"op://<vault>/<item>/<field>"   a 1Password secret reference
+//	"vault://<path>#<field>"        a field of a Vault KV secret
+//	"sops://<file>#<key>"           a key, such as db.password, of a file
+//	                                encrypted with sops
+type SecretRef struct {
+	Provider string
+
+	// Path is the 1Password reference, the Vault path, or the sops file
+	// relative to the project directory.
+	Path string
+
+	// Key is the Vault field or the sops key. It's empty for 1Password.
+	Key string
+}
+
+// ParseSecretRef parses a reference in secrets.
+func ParseSecretRef(ref string) (SecretRef, error) {
+	provider, rest, _ := strings.Cut(ref, "://")
+	if provider == SecretProvider1Password {
+		if rest == "" {
+			return SecretRef{}, errors.Errorf("the 1Password reference %q needs a vault, item and field, such as op://dev/db/password", ref)
+		}
+		return SecretRef{Provider: provider, Path: ref}, nil
+	}
+	if provider != SecretProviderVault && provider != SecretProviderSops {
+		return SecretRef{}, errors.Errorf(
+			"the secret reference %q needs to start with op://, vault:// or sops://", ref)
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	if path == "" || key == "" {
+		return SecretRef{}, errors.Errorf(
+			"the secret reference %q needs a path and a key, such as %s://secrets/app#password", ref, provider)
+	}
+	return SecretRef{Provider: provider, Path: path, Key: key}, nil
+}
+
+func validateSecrets(cfg *Config) error {
+	names := lo.Keys(cfg.Secrets)
+	slices.Sort(names)
+	for _, name := range names {
+		if _, err := ParseSecretRef(cfg.Secrets[name]); err != nil {
+			return usererr.New("The secret %s in devbox.json is invalid: %v.", name, err)
+		}
+	}
+	return nil
+}
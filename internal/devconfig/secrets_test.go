@@ -0,0 +1,44 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSecretRef(t *testing.T) {
+	testCases := []struct {
+		ref  string
+		want SecretRef
+	}{
+		{"op://dev/postgres/password", SecretRef{Provider: "op", Path: "op://dev/postgres/password"}},
+		{"vault://secret/app#token", SecretRef{Provider: "vault", Path: "secret/app", Key: "token"}},
+		{"sops://secrets/dev.enc.yaml#db.password", SecretRef{Provider: "sops", Path: "secrets/dev.enc.yaml", Key: "db.password"}},
+	}
+	for _, tc := range testCases {
+		got, err := ParseSecretRef(tc.ref)
+		if err != nil {
+			t.Errorf("got error %v for %q", err, tc.ref)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("got %+v for %q, want %+v", got, tc.ref, tc.want)
+		}
+	}
+
+	for _, ref := range []string{"op://", "vault://secret/app", "sops://#key", "s3://bucket/key", "hunter2"} {
+		if _, err := ParseSecretRef(ref); err == nil {
+			t.Errorf("got no error for %q", ref)
+		}
+	}
+}
+
+func TestValidateSecrets(t *testing.T) {
+	_, err := loadBytes([]byte(`{"secrets": {"API_KEY": "vault://secret/app"}}`))
+	want := "The secret API_KEY in devbox.json is invalid"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want it to contain %q", err, want)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+// Service is a long-running process, such as a database or a web server,
+// that devbox services supervises:
+//
+//	"services": {
+//	  "web": {"command": "npm run dev", "depends_on": ["db"]}
+//	}
+type Service struct {
+	// Command is the shell command that runs the service in the
+	// foreground.
+	Command string `json:"command"`
+
+	// Dir is the directory the service runs in, relative to the project
+	// directory. It defaults to the project directory.
+	Dir string `json:"dir,omitempty"`
+
+	// Env holds env variables that are only set for this service.
+	Env map[string]string `json:"env,omitempty"`
+
+	// DependsOn lists services that start before this one. They can be
+	// services from plugins or process-compose.yaml too.
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Schedule makes the service a recurring task that runs Command at
+	// the times of a cron expression, such as "*/5 * * * *", or at an
+	// interval, such as "@every 30s", instead of keeping it running.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Ready is how devbox services knows that the service has started and
+	// can handle requests. Services that depend on this one wait until
+	// it's ready.
+	Ready *ReadyCheck `json:"ready,omitempty"`
+}
+
+// ReadyCheck checks whether a service is ready. Set one of its fields.
+type ReadyCheck struct {
+	// Port is a TCP port on 127.0.0.1 that accepts connections once the
+	// service is ready.
+	Port int `json:"port,omitempty"`
+
+	// HTTP is a URL that returns a 2xx status once the service is ready.
+	HTTP string `json:"http,omitempty"`
+
+	// Command is a shell command that succeeds once the service is ready,
+	// such as "pg_isready".
+	Command string `json:"command,omitempty"`
+}
+
+// Proxy is a reverse proxy that devbox services runs with Caddy, so a project
+// with several services gets stable URLs for them:
+//
+//	"proxy": {
+//	  "port": 8080,
+//	  "routes": {"app.localhost": "3000", "api.localhost": "127.0.0.1:4000"}
+//	}
+type Proxy struct {
+	// Port is the port the proxy listens on. It defaults to 8080.
+	Port int `json:"port,omitempty"`
+
+	// Routes maps each hostname to the address the proxy forwards its
+	// requests to. An address that's only a port is on 127.0.0.1.
+	Routes map[string]string `json:"routes"`
+}
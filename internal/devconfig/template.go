@@ -0,0 +1,50 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"regexp"
+)
+
+// templateVarRe matches a template variable such as {{.ProjectDir}} or
+// {{ .Virtenv }}.
+var templateVarRe = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+// TemplateVars are the devbox-provided values that can be used as template
+// variables in env values, the init hook and scripts. For example:
+//
+//	"env": {"GOPATH": "{{.ProjectDir}}/.gopath"}
+type TemplateVars struct {
+	// ProjectDir is the directory containing devbox.json.
+	ProjectDir string
+	// DevboxDir is the project's .devbox directory.
+	DevboxDir string
+	// Virtenv is the directory where plugins keep their files.
+	Virtenv string
+	// OS and Arch are the current operating system and architecture, as
+	// reported by Go (e.g. "darwin" and "arm64").
+	OS   string
+	Arch string
+}
+
+// Expand replaces the template variables in s with their values. Unlike
+// text/template, it leaves anything that isn't a known variable untouched so
+// that commands with their own template syntax keep working (e.g.
+// docker ps --format '{{.Names}}').
+func (v TemplateVars) Expand(s string) string {
+	values := map[string]string{
+		"ProjectDir": v.ProjectDir,
+		"DevboxDir":  v.DevboxDir,
+		"Virtenv":    v.Virtenv,
+		"OS":         v.OS,
+		"Arch":       v.Arch,
+	}
+	return templateVarRe.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVarRe.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import "testing"
+
+func TestTemplateVarsExpand(t *testing.T) {
+	vars := TemplateVars{
+		ProjectDir: "/home/user/project",
+		DevboxDir:  "/home/user/project/.devbox",
+		Virtenv:    "/home/user/project/.devbox/virtenv",
+		OS:         "linux",
+		Arch:       "amd64",
+	}
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"{{.ProjectDir}}/bin", "/home/user/project/bin"},
+		{"{{ .Virtenv }}/postgres", "/home/user/project/.devbox/virtenv/postgres"},
+		{"{{.DevboxDir}}/cache-{{.OS}}-{{.Arch}}", "/home/user/project/.devbox/cache-linux-amd64"},
+		{"docker ps --format '{{.Names}}'", "docker ps --format '{{.Names}}'"},
+		{"echo {{.ProjectDir", "echo {{.ProjectDir"},
+		{"$PWD/bin", "$PWD/bin"},
+	}
+	for _, tc := range testCases {
+		if got := vars.Expand(tc.in); got != tc.want {
+			t.Errorf("Expand(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
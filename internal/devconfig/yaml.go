@@ -0,0 +1,292 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/tailscale/hujson"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlToJSON converts a devbox.yaml document to JSON so that it can be loaded
+// like a regular devbox.json. Unlike unmarshalling into a map, it walks the
+// YAML nodes to preserve the order of object keys (the order of packages is
+// significant).
+func yamlToJSON(b []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(doc.Content) == 0 {
+		// An empty YAML file is equivalent to an empty config.
+		return []byte("{}"), nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeYAMLNodeAsJSON(buf, doc.Content[0], reflect.TypeOf(Config{})); err != nil {
+		return nil, err
+	}
+	return hujson.Format(buf.Bytes())
+}
+
+// writeYAMLNodeAsJSON writes node as JSON. t is the type in the Config struct
+// that the node is for, or nil if it isn't a known field. Plain scalars such
+// as 8080, 1.20 and true are written as strings where t expects a string, so
+// unquoted env values and versions load like quoted ones.
+func writeYAMLNodeAsJSON(buf *bytes.Buffer, node *yaml.Node, t reflect.Type) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeYAMLNodeAsJSON(buf, node.Content[0], t)
+	case yaml.AliasNode:
+		return writeYAMLNodeAsJSON(buf, node.Alias, t)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key := node.Content[i].Value
+			if err := writeJSONValue(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeYAMLNodeAsJSON(buf, node.Content[i+1], yamlMemberType(t, key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, elem := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeYAMLNodeAsJSON(buf, elem, yamlElementType(t)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		var v any = node.Value
+		if !yamlWantsString(t) || !slices.Contains([]string{"!!int", "!!float", "!!bool"}, node.ShortTag()) {
+			if err := node.Decode(&v); err != nil {
+				return errors.Wrapf(err, "line %d", node.Line)
+			}
+		}
+		if err := writeJSONValue(buf, v); err != nil {
+			return errors.Wrapf(err, "line %d", node.Line)
+		}
+	default:
+		return errors.Errorf("line %d: unsupported YAML node", node.Line)
+	}
+	return nil
+}
+
+// yamlMemberType returns the type of the key field of a mapping for t, like
+// validateValue does for JSON objects.
+func yamlMemberType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case packagesType:
+		return packageType
+	case packageType:
+		if key == "name" {
+			// The name of a package in a packages array.
+			return reflect.TypeOf("")
+		}
+	case scriptType:
+		t = reflect.TypeOf(scriptObject{})
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if field, ok := jsonFields(t)[key]; ok {
+			return field.Type
+		}
+	case reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+// yamlElementType returns the type of the elements of a sequence for t.
+func yamlElementType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case packagesType:
+		return packageType
+	case scriptType, commandsType, envFromType:
+		return reflect.TypeOf("")
+	}
+	if t.Kind() == reflect.Slice {
+		return t.Elem()
+	}
+	return nil
+}
+
+// yamlWantsString reports whether a scalar for t must be a string.
+func yamlWantsString(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case packageType, scriptType, commandsType, envFromType:
+		return true
+	}
+	return t.Kind() == reflect.String
+}
+
+// writeJSONValue writes v as JSON without escaping HTML characters, which are
+// common in shell commands (e.g. "&&" and ">").
+func writeJSONValue(buf *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return errors.WithStack(err)
+	}
+	// Encode always appends a newline.
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+// jsonToYAML converts the (hu)JSON of a devbox config to block-style YAML,
+// preserving the order of object keys.
+func jsonToYAML(b []byte) ([]byte, error) {
+	doc, err := jsonToYAMLNode(b)
+	if err != nil {
+		return nil, err
+	}
+	return encodeYAML(doc)
+}
+
+// patchYAML writes the (hu)JSON of a devbox config over src, the YAML file it
+// was loaded from. Values that didn't change keep their comments and style,
+// and the comments of changed values are moved to the new ones.
+func patchYAML(src, b []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil || len(doc.Content) == 0 {
+		return jsonToYAML(b)
+	}
+	updated, err := jsonToYAMLNode(b)
+	if err != nil {
+		return nil, err
+	}
+	doc.Content[0] = mergeYAMLNode(doc.Content[0], updated.Content[0])
+	return encodeYAML(&doc)
+}
+
+func jsonToYAMLNode(b []byte) (*yaml.Node, error) {
+	b, err := hujson.Standardize(bytes.Clone(b))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// JSON is valid YAML, so we can parse it directly into YAML nodes.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	clearYAMLStyle(&doc)
+	return &doc, nil
+}
+
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeYAMLNode returns updated, reusing the nodes of orig for the keys,
+// elements and values that it still has.
+func mergeYAMLNode(orig, updated *yaml.Node) *yaml.Node {
+	if orig.Kind != updated.Kind {
+		return updated
+	}
+	switch updated.Kind {
+	case yaml.MappingNode:
+		content := make([]*yaml.Node, 0, len(updated.Content))
+		for i := 0; i+1 < len(updated.Content); i += 2 {
+			key, value := updated.Content[i], updated.Content[i+1]
+			for j := 0; j+1 < len(orig.Content); j += 2 {
+				if orig.Content[j].Value == key.Value {
+					key, value = orig.Content[j], mergeYAMLNode(orig.Content[j+1], value)
+					break
+				}
+			}
+			content = append(content, key, value)
+		}
+		merged := *orig
+		merged.Content = content
+		return &merged
+	case yaml.SequenceNode:
+		// Scalars, such as the versioned names in a packages array, are
+		// matched by value so that removing one keeps the comments of the
+		// others. Other elements are matched by position.
+		used := make([]bool, len(orig.Content))
+		content := make([]*yaml.Node, 0, len(updated.Content))
+		for i, elem := range updated.Content {
+			j := slices.IndexFunc(orig.Content, func(o *yaml.Node) bool {
+				return o.Kind == yaml.ScalarNode && elem.Kind == yaml.ScalarNode && o.Value == elem.Value
+			})
+			if j < 0 && elem.Kind != yaml.ScalarNode && i < len(orig.Content) {
+				j = i
+			}
+			if j >= 0 && !used[j] {
+				used[j] = true
+				elem = mergeYAMLNode(orig.Content[j], elem)
+			}
+			content = append(content, elem)
+		}
+		merged := *orig
+		merged.Content = content
+		return &merged
+	case yaml.ScalarNode:
+		if orig.Value == updated.Value {
+			return orig
+		}
+		merged := *updated
+		merged.HeadComment = orig.HeadComment
+		merged.LineComment = orig.LineComment
+		merged.FootComment = orig.FootComment
+		return &merged
+	}
+	return updated
+}
+
+// clearYAMLStyle resets the style of every node so that the encoder emits
+// block-style YAML and only quotes strings when necessary.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
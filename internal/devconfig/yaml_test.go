@@ -0,0 +1,227 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devconfig
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOpenYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultYAMLName), `# Shared team config
+packages:
+  python: "3.10"
+  go: latest
+  hello:
+    platforms: [x86_64-linux]
+env:
+  FOO: bar
+shell:
+  init_hook:
+    - echo hello
+  scripts:
+    test: go test ./...
+`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	wantPkgs := []string{"python@3.10", "go@latest", "hello"}
+	if diff := cmp.Diff(wantPkgs, cfg.Packages.VersionedNames()); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	if got := cfg.Env["FOO"]; got != "bar" {
+		t.Errorf("got env FOO=%q, want %q", got, "bar")
+	}
+	if got, want := cfg.InitHook().String(), "echo hello"; got != want {
+		t.Errorf("got init hook %q, want %q", got, want)
+	}
+	if got, want := cfg.Scripts()["test"].String(), "go test ./..."; got != want {
+		t.Errorf("got test script %q, want %q", got, want)
+	}
+	if got, want := cfg.FileName(), defaultYAMLName; got != want {
+		t.Errorf("got file name %q, want %q", got, want)
+	}
+}
+
+func TestOpenYAMLPlainScalars(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultYAMLName), `packages:
+  go: 1.20
+  python:
+    version: 3.10
+env:
+  PORT: 8080
+  DEBUG: true
+  RATIO: 0.5
+shell:
+  scripts:
+    answer: 42
+    serve:
+      command: true
+      env:
+        WORKERS: 4
+nixpkgs:
+  allow_unfree: false
+`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	wantPkgs := []string{"go@1.20", "python@3.10"}
+	if diff := cmp.Diff(wantPkgs, cfg.Packages.VersionedNames()); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantEnv := map[string]string{"PORT": "8080", "DEBUG": "true", "RATIO": "0.5"}
+	if diff := cmp.Diff(wantEnv, cfg.Env); diff != "" {
+		t.Errorf("wrong env (-want +got):\n%s", diff)
+	}
+	scripts := cfg.Scripts()
+	if got, want := scripts["answer"].String(), "42"; got != want {
+		t.Errorf("got answer script %q, want %q", got, want)
+	}
+	if got, want := scripts["serve"].String(), "true"; got != want {
+		t.Errorf("got serve script %q, want %q", got, want)
+	}
+	if got, want := scripts["serve"].Env["WORKERS"], "4"; got != want {
+		t.Errorf("got serve script env WORKERS=%q, want %q", got, want)
+	}
+	if cfg.AllowUnfree() {
+		t.Error("got allow_unfree = true, want the boolean to stay a boolean")
+	}
+}
+
+func TestSaveYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultYMLName), `packages:
+  python: "3.10"
+`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	cfg.Packages.Add("go@1.21")
+	cfg.Packages.Remove("python@3.10")
+	if err := cfg.SaveTo(dir); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, defaultName)); err == nil {
+		t.Errorf("got %s after saving a YAML config, want only %s", defaultName, defaultYMLName)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, defaultYMLName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `packages:
+  go: "1.21"
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong saved YAML (-want +got):\n%s", diff)
+	}
+}
+
+func TestSaveYAMLKeepsComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultYAMLName), `# team config
+packages:
+  # The version that CI uses.
+  go: 1.20
+  python: "3.10" # for the docs
+env:
+  FOO: bar # inline
+`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	cfg.Packages.Add("nodejs@20")
+	cfg.Packages.Remove("python@3.10")
+	if err := cfg.SaveTo(dir); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, defaultYAMLName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# team config
+packages:
+  # The version that CI uses.
+  go: 1.20
+  nodejs: "20"
+env:
+  FOO: bar # inline
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong saved YAML (-want +got):\n%s", diff)
+	}
+}
+
+func TestSaveYAMLArrayKeepsComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, defaultYAMLName), `packages:
+  - python@3.10 # for the docs
+  - go@1.20 # the version that CI uses
+`)
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	cfg.Packages.Remove("python@3.10")
+	cfg.Packages.Add("nodejs@20")
+	if err := cfg.SaveTo(dir); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, defaultYAMLName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `packages:
+  - go@1.20 # the version that CI uses
+  - nodejs@20
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong saved YAML (-want +got):\n%s", diff)
+	}
+}
+
+func TestInitYAML(t *testing.T) {
+	dir := t.TempDir()
+	created, err := InitWithOpts(dir, InitOpts{Format: "yaml"}, io.Discard)
+	if err != nil {
+		t.Fatalf("InitWithOpts() error = %v", err)
+	}
+	if !created {
+		t.Fatal("got created = false, want true")
+	}
+
+	cfg, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !cfg.Equals(DefaultConfig()) {
+		t.Errorf("got config:\n%s\nwant default config:\n%s", cfg.Bytes(), DefaultConfig().Bytes())
+	}
+
+	// A second init shouldn't create a devbox.json next to devbox.yaml.
+	created, err = Init(dir, io.Discard)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if created {
+		t.Error("got created = true for a directory with an existing devbox.yaml")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devpkg
+
+import (
+	"strings"
+	"sync"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/lock"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// newChannelPackage returns a package that devbox.json installs from a nixpkgs
+// channel or revision instead of the Devbox search index. raw is the package's
+// nixpkgs attribute path, such as nodejs-18_x.
+func newChannelPackage(raw, channel string, isInstallable bool, locker lock.Locker) *Package {
+	pkg := &Package{
+		Raw:           raw,
+		lockfile:      locker,
+		isInstallable: isInstallable,
+		channel:       channel,
+	}
+	if installable, err := lock.ChannelInstallable(raw, channel); err == nil {
+		pkg.setInstallable(installable, locker.ProjectDir())
+	}
+	pkg.resolve = sync.OnceValue(func() error { return resolveChannel(pkg) })
+	return pkg
+}
+
+// resolveChannel is like resolve for packages from a nixpkgs channel. The
+// package is pinned to a revision of the channel in devbox.lock.
+func resolveChannel(pkg *Package) error {
+	if strings.Contains(pkg.Raw, "@") {
+		return usererr.New(
+			"Package %s has a version and a channel. Remove the version to install "+
+				"the package from the %s channel.", pkg.Raw, pkg.channel,
+		)
+	}
+	resolved, err := pkg.lockfile.ResolveChannel(pkg.Raw, pkg.channel)
+	if err != nil {
+		return err
+	}
+	parsed, err := flake.ParseInstallable(resolved.Resolved)
+	if err != nil {
+		return err
+	}
+	pkg.setInstallable(parsed, pkg.lockfile.ProjectDir())
+	return nil
+}
+
+// Channel returns the nixpkgs channel or revision that devbox.json installs the
+// package from, or "" if it comes from the Devbox search index.
+func (p *Package) Channel() string {
+	return p.channel
+}
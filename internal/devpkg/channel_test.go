@@ -0,0 +1,27 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devpkg
+
+import (
+	"testing"
+)
+
+func TestChannelPackage(t *testing.T) {
+	pkg := newChannelPackage("nodejs-18_x", "nixos-unstable", true, &lockfile{})
+	if pkg.IsDevboxPackage {
+		t.Error("got a devbox package for a package from a channel")
+	}
+	if got := pkg.String(); got != "nodejs-18_x" {
+		t.Errorf("got String() = %q, want %q", got, "nodejs-18_x")
+	}
+	want := "github:NixOS/nixpkgs/" + nixCommitHash
+	if got := pkg.URLForFlakeInput(); got != want {
+		t.Errorf("got URLForFlakeInput() = %q, want %q", got, want)
+	}
+
+	versioned := newChannelPackage("nodejs@18", "nixos-unstable", true, &lockfile{})
+	if _, err := versioned.PackageAttributePath(); err == nil {
+		t.Error("got nil error for a package with a version and a channel")
+	}
+}
@@ -9,9 +9,9 @@ import (
 
 	"github.com/pkg/errors"
 	"go.jetpack.io/devbox/internal/boxcli/featureflag"
+	"go.jetpack.io/devbox/internal/fileutil"
 	"go.jetpack.io/devbox/internal/lock"
 	"go.jetpack.io/devbox/internal/nix"
-	"go.jetpack.io/devbox/internal/vercheck"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -112,6 +112,11 @@ func (p *Package) fetchNarInfoStatus() (bool, error) {
 		)
 	}
 
+	// Offline, the only cache is the local Nix store.
+	if nix.IsOffline() {
+		return fileutil.Exists(sysInfo.StorePath), nil
+	}
+
 	pathParts := nix.NewStorePathParts(sysInfo.StorePath)
 	reqURL := BinaryCache + "/" + pathParts.Hash + ".narinfo"
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -155,14 +160,8 @@ func (p *Package) sysInfoIfExists() (*lock.SystemInfo, error) {
 		return nil, nil
 	}
 
-	version, err := nix.Version()
-	if err != nil {
-		return nil, err
-	}
-
-	// enable for nix >= 2.17
-	if vercheck.SemverCompare(version, "2.17.0") < 0 {
-		return nil, err
+	if !nix.AtLeast(nix.VersionFetchClosureInputAddressed) {
+		return nil, nil
 	}
 
 	entry, err := p.lockfile.Resolve(p.Raw)
@@ -0,0 +1,247 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devpkg
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/cachehash"
+	"go.jetpack.io/devbox/internal/lock"
+	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// nixFileFlakesDir is the directory, relative to the project, where devbox
+// generates a flake for each package that's a local .nix file.
+const nixFileFlakesDir = ".devbox/gen/nixfile"
+
+// nixFilePath returns the path of the .nix file if raw is a package that
+// points at one, such as ./nix/mytool.nix or path:./nix/mytool.nix.
+func nixFilePath(raw string) (string, bool) {
+	path := strings.TrimPrefix(raw, "path:")
+	if !strings.HasSuffix(path, ".nix") || strings.ContainsAny(path, "#?^") {
+		return "", false
+	}
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "/") {
+		return "", false
+	}
+	return path, true
+}
+
+// newNixFilePackage returns a package that builds a local .nix file. Nix can
+// only install flakes, so the package resolves to a flake that devbox
+// generates for the file.
+func newNixFilePackage(raw, path string, isInstallable bool, locker lock.Locker) *Package {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(locker.ProjectDir(), path)
+	}
+	pkg := &Package{
+		Raw:           raw,
+		lockfile:      locker,
+		isInstallable: isInstallable,
+		nixFile:       path,
+	}
+	pkg.setInstallable(flake.Installable{
+		Ref: flake.Ref{Type: flake.TypePath, Path: nixFileFlakeDir(locker.ProjectDir(), path)},
+	}, locker.ProjectDir())
+	pkg.resolve = sync.OnceValue(func() error { return writeNixFileFlake(pkg) })
+	return pkg
+}
+
+// nixFileFlakeDir returns the directory of the flake that devbox generates
+// for a .nix file.
+func nixFileFlakeDir(projectDir, path string) string {
+	sum, _ := cachehash.Bytes([]byte(path))
+	name := strings.TrimSuffix(filepath.Base(path), ".nix") + "-" + sum[:min(len(sum), 8)]
+	return filepath.Join(projectDir, nixFileFlakesDir, name)
+}
+
+// nixPathRegex matches the relative path literals in a .nix file, such as
+// ./lib.nix or ../patches/fix.patch.
+var nixPathRegex = regexp.MustCompile(`(?:^|[\s(\[{=:])(\.\.?/[\w.+\-/]*)`)
+
+// writeNixFileFlake writes the flake for a .nix file package. The flake
+// copies the file, and the files it refers to with relative paths, into the
+// flake's src directory, and builds the file with nixpkgs' callPackage. A
+// file that's a derivation instead of a function is used as it is.
+func writeNixFileFlake(pkg *Package) error {
+	if _, err := os.Stat(pkg.nixFile); errors.Is(err, fs.ErrNotExist) {
+		return usererr.New("The nix file %s for package %s doesn't exist.", pkg.nixFile, pkg.Raw)
+	}
+	root, sources, err := nixFileSources(pkg.nixFile)
+	if err != nil {
+		return err
+	}
+	flakeDir := pkg.installable.Ref.Path
+	if err := copyNixFileSources(root, sources, filepath.Join(flakeDir, "src")); err != nil {
+		return err
+	}
+
+	nixpkgsURL, _, _ := strings.Cut(pkg.lockfile.LegacyNixpkgsPath(""), "#")
+	system := nix.System()
+	rel, _ := filepath.Rel(root, pkg.nixFile)
+	contents := fmt.Sprintf(`{
+  description = "Devbox package for %[1]s";
+
+  inputs = {
+    nixpkgs.url = "%[2]s";
+  };
+
+  outputs = { nixpkgs, ... }:
+    let
+      pkgs = import nixpkgs {
+        system = "%[3]s";
+        config.allowUnfree = %[4]t;
+      };
+      pkg = import (./src + "/%[5]s");
+    in
+    {
+      packages."%[3]s".default =
+        if builtins.isFunction pkg then pkgs.callPackage pkg { } else pkg;
+    };
+}
+`, pkg.Raw, nixpkgsURL, system, nix.AllowUnfree(), filepath.ToSlash(rel))
+
+	path := filepath.Join(flakeDir, "flake.nix")
+	// Rewriting an unchanged flake.nix would make nix evaluate it again.
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, []byte(contents)) {
+		return nil
+	}
+	return errors.WithStack(os.WriteFile(path, []byte(contents), 0o644))
+}
+
+// nixFileSources returns the files that a .nix file needs to build: the file
+// itself and the files and directories that it refers to with relative
+// paths, and theirs in turn. It also returns the closest directory that has
+// all of them. References to files that don't exist are left for nix to
+// report.
+func nixFileSources(path string) (root string, sources []string, err error) {
+	seen := map[string]bool{}
+	var visit func(path string) error
+	visit = func(path string) error {
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if info.IsDir() {
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			for _, entry := range entries {
+				// Don't copy the generated flakes into themselves.
+				if entry.Name() == ".devbox" || entry.Name() == ".git" {
+					continue
+				}
+				if err := visit(filepath.Join(path, entry.Name())); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		sources = append(sources, path)
+		if filepath.Ext(path) != ".nix" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, m := range nixPathRegex.FindAllStringSubmatch(string(content), -1) {
+			ref := filepath.Join(filepath.Dir(path), m[1])
+			if _, err := os.Stat(ref); err != nil {
+				continue
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(path); err != nil {
+		return "", nil, err
+	}
+	slices.Sort(sources)
+
+	root = filepath.Dir(path)
+	for _, source := range sources {
+		for !strings.HasPrefix(source, root+string(filepath.Separator)) && root != filepath.Dir(root) {
+			root = filepath.Dir(root)
+		}
+	}
+	return root, sources, nil
+}
+
+// copyNixFileSources copies the sources of a .nix file into dir, keeping
+// their paths relative to root. Files that are already up to date are left
+// alone, and files that aren't sources anymore are removed.
+func copyNixFileSources(root string, sources []string, dir string) error {
+	keep := map[string]bool{}
+	for _, source := range sources {
+		rel, err := filepath.Rel(root, source)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dst := filepath.Join(dir, rel)
+		keep[dst] = true
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if existing, err := os.ReadFile(dst); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := os.WriteFile(dst, content, 0o644); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || keep[path] {
+			return err
+		}
+		return os.Remove(path)
+	}))
+}
+
+// nixFileHash returns a hash of the sources of a .nix file, which changes
+// whenever one of the files that are copied into its flake does.
+func nixFileHash(path string) (string, error) {
+	root, sources, err := nixFileSources(path)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for _, source := range sources {
+		sum, err := cachehash.File(source)
+		if err != nil {
+			return "", err
+		}
+		rel, _ := filepath.Rel(root, source)
+		fmt.Fprintf(&buf, "%s %s\n", sum, rel)
+	}
+	return cachehash.Bytes(buf.Bytes())
+}
+
+// NixFile returns the path of the local .nix file that the package builds, or
+// "" if it isn't a nix file package.
+func (p *Package) NixFile() string {
+	return p.nixFile
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package devpkg
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+func TestNixFilePath(t *testing.T) {
+	cases := map[string]bool{
+		"./nix/mytool.nix":      true,
+		"path:./nix/mytool.nix": true,
+		"/opt/tools/tool.nix":   true,
+		"./my-flake#my-package": false,
+		"path:./my-flake":       false,
+		"hello":                 false,
+	}
+	for raw, want := range cases {
+		if _, got := nixFilePath(raw); got != want {
+			t.Errorf("got nixFilePath(%q) ok = %t, want %t", raw, got, want)
+		}
+	}
+}
+
+func TestNixFilePackage(t *testing.T) {
+	t.Setenv("__DEVBOX_NIX_SYSTEM", "x86_64-linux")
+	projectDir := t.TempDir()
+	fileutil.WriteFilesForTest(t, projectDir, map[string]string{
+		"nix/mytool.nix":      "{ callPackage }: callPackage ./lib.nix { patch = ../patches/fix.patch; }",
+		"nix/lib.nix":         "{ hello, patch }: hello.overrideAttrs { patches = [ patch ]; }",
+		"nix/unrelated.nix":   "{ }: null",
+		"patches/fix.patch":   "--- a\n+++ b\n",
+		"secrets/credentials": "not copied",
+	})
+
+	pkg := PackageFromStringWithDefaults("./nix/mytool.nix", &lockfile{projectDir: projectDir})
+	if pkg.IsDevboxPackage {
+		t.Error("got a devbox package for a nix file")
+	}
+	url := pkg.URLForFlakeInput()
+	wantPrefix := "path:" + filepath.Join(projectDir, nixFileFlakesDir, "mytool-")
+	if !strings.HasPrefix(url, wantPrefix) {
+		t.Errorf("got flake input URL %q, want prefix %q", url, wantPrefix)
+	}
+	flakeDir := strings.TrimPrefix(url, "path:")
+
+	flakeNix, err := os.ReadFile(filepath.Join(flakeDir, "flake.nix"))
+	if err != nil {
+		t.Fatal("got error reading the generated flake:", err)
+	}
+	for _, want := range []string{
+		`pkg = import (./src + "/nix/mytool.nix");`,
+		`config.allowUnfree = true;`,
+		`packages."x86_64-linux".default`,
+	} {
+		if !strings.Contains(string(flakeNix), want) {
+			t.Errorf("got generated flake without %q:\n%s", want, flakeNix)
+		}
+	}
+
+	var copied []string
+	err = filepath.WalkDir(filepath.Join(flakeDir, "src"), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(filepath.Join(flakeDir, "src"), path)
+			copied = append(copied, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"nix/lib.nix", "nix/mytool.nix", "patches/fix.patch"}, copied); diff != "" {
+		t.Errorf("wrong copied files (-want +got):\n%s", diff)
+	}
+
+	hash := pkg.Hash()
+	fileutil.WriteFilesForTest(t, projectDir, map[string]string{"nix/lib.nix": "{ hello, patch }: hello"})
+	if pkg.Hash() == hash {
+		t.Error("got the same hash after changing a file that the nix file imports")
+	}
+}
+
+func TestNixFilePackageDisallowUnfree(t *testing.T) {
+	t.Setenv("__DEVBOX_NIX_SYSTEM", "x86_64-linux")
+	nix.SetAllowUnfree(false)
+	t.Cleanup(func() { nix.SetAllowUnfree(true) })
+	projectDir := t.TempDir()
+	fileutil.WriteFilesForTest(t, projectDir, map[string]string{"mytool.nix": "{ hello }: hello"})
+
+	pkg := PackageFromStringWithDefaults("./mytool.nix", &lockfile{projectDir: projectDir})
+	flakeNix, err := os.ReadFile(filepath.Join(strings.TrimPrefix(pkg.URLForFlakeInput(), "path:"), "flake.nix"))
+	if err != nil {
+		t.Fatal("got error reading the generated flake:", err)
+	}
+	if !strings.Contains(string(flakeNix), "config.allowUnfree = false;") {
+		t.Errorf("got generated flake that allows unfree packages:\n%s", flakeNix)
+	}
+}
@@ -74,6 +74,8 @@ type Package struct {
 	// 2. Local
 	//    flakes in a relative sub-directory
 	//    example: ./local_flake_subdir#myPackage
+	//    or .nix files, which devbox wraps in a generated flake
+	//    example: ./nix/mytool.nix
 	// 3. Github
 	//    remote flakes with raw name starting with `Github:`
 	//    example: github:nixos/nixpkgs/5233fd2ba76a3accb5aaa999c00509a11fd0793c#hello
@@ -91,9 +93,29 @@ type Package struct {
 	// installed even if they are marked as insecure.
 	AllowInsecure []string
 
+	// Groups are the devbox.json package groups that this package belongs to.
+	Groups []string
+
+	// Instances are the names of the copies of the package's plugin that
+	// devbox.json asks for. If it's empty, the plugin runs once.
+	Instances []string
+
+	// IsLocal is true if the package comes from devbox.local.json or the
+	// user-level defaults instead of devbox.json. Local packages are locked
+	// in devbox.local.lock instead of devbox.lock.
+	IsLocal bool
+
 	// isInstallable is true if the package may be enabled on the current platform.
 	isInstallable bool
 
+	// nixFile is the absolute path of the local .nix file that the package
+	// builds, if it's a nix file package such as ./nix/mytool.nix.
+	nixFile string
+
+	// channel is the nixpkgs channel or revision that devbox.json installs
+	// the package from, if any.
+	channel string
+
 	normalizedPackageAttributePathCache string // memoized value from normalizedPackageAttributePath()
 }
 
@@ -119,16 +141,32 @@ func PackagesFromStringsWithOptions(rawNames []string, l lock.Locker, opts devop
 func PackagesFromConfig(config *devconfig.Config, l lock.Locker) []*Package {
 	result := []*Package{}
 	for _, cfgPkg := range config.Packages.Collection {
-		pkg := newPackage(cfgPkg.VersionedName(), cfgPkg.IsEnabledOnPlatform(), l)
-		pkg.DisablePlugin = cfgPkg.DisablePlugin
-		pkg.PatchGlibc = cfgPkg.PatchGlibc && nix.SystemIsLinux()
-		pkg.Outputs = cfgPkg.Outputs
-		pkg.AllowInsecure = cfgPkg.AllowInsecure
+		result = append(result, packageFromConfig(cfgPkg, l))
+	}
+	for _, cfgPkg := range config.LocalPackages() {
+		pkg := packageFromConfig(cfgPkg, l)
+		pkg.IsLocal = true
 		result = append(result, pkg)
 	}
 	return result
 }
 
+func packageFromConfig(cfgPkg devconfig.Package, l lock.Locker) *Package {
+	var pkg *Package
+	if cfgPkg.Channel != "" {
+		pkg = newChannelPackage(cfgPkg.VersionedName(), cfgPkg.Channel, cfgPkg.IsEnabledOnPlatform(), l)
+	} else {
+		pkg = newPackage(cfgPkg.VersionedName(), cfgPkg.IsEnabledOnPlatform(), l)
+	}
+	pkg.DisablePlugin = cfgPkg.DisablePlugin
+	pkg.PatchGlibc = cfgPkg.PatchGlibc && nix.SystemIsLinux()
+	pkg.Outputs = cfgPkg.Outputs
+	pkg.AllowInsecure = cfgPkg.AllowInsecure
+	pkg.Groups = cfgPkg.Groups
+	pkg.Instances = cfgPkg.Instances
+	return pkg
+}
+
 func PackageFromStringWithDefaults(raw string, locker lock.Locker) *Package {
 	return newPackage(raw, true /*isInstallable*/, locker)
 }
@@ -143,6 +181,10 @@ func PackageFromStringWithOptions(raw string, locker lock.Locker, opts devopt.Ad
 }
 
 func newPackage(raw string, isInstallable bool, locker lock.Locker) *Package {
+	if path, ok := nixFilePath(raw); ok {
+		return newNixFilePackage(raw, path, isInstallable, locker)
+	}
+
 	pkg := &Package{
 		Raw:           raw,
 		lockfile:      locker,
@@ -160,10 +202,15 @@ func newPackage(raw string, isInstallable bool, locker lock.Locker) *Package {
 		return pkg
 	}
 
-	// We currently don't lock flake references in devbox.lock, so there's
-	// nothing to resolve.
-	pkg.resolve = sync.OnceValue(func() error { return nil })
 	pkg.setInstallable(parsed, locker.ProjectDir())
+
+	// Remote flakes are pinned in devbox.lock, but local (path) flakes
+	// aren't, so there's nothing to resolve.
+	if parsed.Ref.Type == flake.TypePath {
+		pkg.resolve = sync.OnceValue(func() error { return nil })
+		return pkg
+	}
+	pkg.resolve = sync.OnceValue(func() error { return resolveFlake(pkg) })
 	return pkg
 }
 
@@ -217,6 +264,24 @@ func resolve(pkg *Package) error {
 	return nil
 }
 
+// resolveFlake is like resolve for flake installables. Flakes that devbox
+// doesn't pin keep the installable from devbox.json.
+func resolveFlake(pkg *Package) error {
+	resolved, err := pkg.lockfile.Resolve(pkg.Raw)
+	if err != nil {
+		return err
+	}
+	if resolved.Resolved == "" {
+		return nil
+	}
+	parsed, err := flake.ParseInstallable(resolved.Resolved)
+	if err != nil {
+		return err
+	}
+	pkg.setInstallable(parsed, pkg.lockfile.ProjectDir())
+	return nil
+}
+
 func (p *Package) setInstallable(i flake.Installable, projectDir string) {
 	if i.Ref.Type == flake.TypePath && !filepath.IsAbs(i.Ref.Path) {
 		i.Ref.Path = filepath.Join(projectDir, i.Ref.Path)
@@ -269,6 +334,16 @@ func (p *Package) URLForFlakeInput() string {
 	return p.installable.Ref.String()
 }
 
+// NarHash returns the narHash of the nixpkgs source the package resolves to,
+// as recorded in devbox.lock. It's empty until devbox has evaluated the
+// package once.
+func (p *Package) NarHash() string {
+	if entry := p.lockfile.Get(p.Raw); entry != nil {
+		return entry.NarHash
+	}
+	return ""
+}
+
 // IsInstallable returns whether this package is installable. Not to be confused
 // with the Installable() method which returns the corresponding nix concept.
 func (p *Package) IsInstallable() bool {
@@ -447,7 +522,11 @@ var ErrCannotBuildPackageOnSystem = errors.New("unable to build for system")
 
 func (p *Package) Hash() string {
 	sum := ""
-	if p.installable.Ref.Type == flake.TypePath {
+	if p.nixFile != "" {
+		// Like local flakes, but the flake.nix is generated, so hash the
+		// files that are copied into it instead.
+		sum, _ = nixFileHash(p.nixFile)
+	} else if p.installable.Ref.Type == flake.TypePath {
 		// For local flakes, use content hash of the flake.nix file to ensure
 		// user always gets newest flake.
 		sum, _ = cachehash.File(filepath.Join(p.installable.Ref.Path, "flake.nix"))
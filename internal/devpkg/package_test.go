@@ -133,6 +133,10 @@ func (l *lockfile) Resolve(pkg string) (*lock.Package, error) {
 	}
 }
 
+func (l *lockfile) ResolveChannel(pkg, channel string) (*lock.Package, error) {
+	return &lock.Package{Resolved: l.LegacyNixpkgsPath(pkg), Channel: channel}, nil
+}
+
 func testInputFromString(s, projectDir string) *testInput {
 	return lo.ToPtr(testInput{Package: PackageFromStringWithDefaults(s, &lockfile{projectDir})})
 }
@@ -4,9 +4,12 @@
 package envir
 
 const (
-	DevboxCache         = "DEVBOX_CACHE"
-	DevboxFeaturePrefix = "DEVBOX_FEATURE_"
-	DevboxGateway       = "DEVBOX_GATEWAY"
+	DevboxCache = "DEVBOX_CACHE"
+	// DevboxContainerRuntime is the container runtime, such as podman, that
+	// devbox shell --isolate=container uses.
+	DevboxContainerRuntime = "DEVBOX_CONTAINER_RUNTIME"
+	DevboxFeaturePrefix    = "DEVBOX_FEATURE_"
+	DevboxGateway          = "DEVBOX_GATEWAY"
 	// DevboxLatestVersion is the latest version available of the devbox CLI binary.
 	// NOTE: it should NOT start with v (like 0.4.8)
 	DevboxLatestVersion  = "DEVBOX_LATEST_VERSION"
@@ -0,0 +1,35 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ToolVersionsFile is the file that asdf (and mise) use to pin the versions of
+// a project's tools.
+const ToolVersionsFile = ".tool-versions"
+
+// ToolVersions returns the tool versions in the .tool-versions file in dir,
+// keyed by the asdf plugin name (such as "nodejs", "python" or "golang").
+func ToolVersions(dir string) map[string]string {
+	tools := map[string]string{}
+	f, err := os.Open(filepath.Join(dir, ToolVersionsFile))
+	if err != nil {
+		return tools
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		tools[fields[0]] = fields[1]
+	}
+	return tools
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package initrec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Matches an entry of a Brewfile, such as brew "postgresql@15", restart_service: true.
+var brewfileEntryRegex = regexp.MustCompile(`^(\w+)\s+["']([^"']+)["']`)
+
+// brewFormulas maps Homebrew formulas to the nixpkgs packages that provide
+// them. Formulas that have the same name in nixpkgs are listed too, so that
+// every formula devbox adds is one it knows about.
+var brewFormulas = map[string]string{
+	"awscli":              "awscli2",
+	"bash":                "bash",
+	"bat":                 "bat",
+	"cmake":               "cmake",
+	"coreutils":           "coreutils",
+	"curl":                "curl",
+	"direnv":              "direnv",
+	"docker":              "docker-client",
+	"docker-compose":      "docker-compose",
+	"elixir":              "elixir",
+	"erlang":              "erlang",
+	"fd":                  "fd",
+	"ffmpeg":              "ffmpeg",
+	"findutils":           "findutils",
+	"fzf":                 "fzf",
+	"gawk":                "gawk",
+	"gh":                  "gh",
+	"git":                 "git",
+	"git-lfs":             "git-lfs",
+	"gnu-sed":             "gnused",
+	"gnu-tar":             "gnutar",
+	"gnupg":               "gnupg",
+	"go":                  "go",
+	"gradle":              "gradle",
+	"graphviz":            "graphviz",
+	"grep":                "gnugrep",
+	"helm":                "kubernetes-helm",
+	"htop":                "htop",
+	"httpie":              "httpie",
+	"imagemagick":         "imagemagick",
+	"jq":                  "jq",
+	"k9s":                 "k9s",
+	"kubernetes-cli":      "kubectl",
+	"kind":                "kind",
+	"libpq":               "postgresql",
+	"make":                "gnumake",
+	"maven":               "maven",
+	"minikube":            "minikube",
+	"mysql":               "mysql80",
+	"neovim":              "neovim",
+	"ninja":               "ninja",
+	"node":                "nodejs",
+	"openjdk":             "jdk",
+	"openssl":             "openssl",
+	"php":                 "php",
+	"pkg-config":          "pkg-config",
+	"pnpm":                "nodePackages.pnpm",
+	"postgresql":          "postgresql",
+	"protobuf":            "protobuf",
+	"python":              "python3",
+	"redis":               "redis",
+	"ripgrep":             "ripgrep",
+	"ruby":                "ruby",
+	"rustup":              "rustup",
+	"shellcheck":          "shellcheck",
+	"sqlite":              "sqlite",
+	"terraform":           "terraform",
+	"the_silver_searcher": "silver-searcher",
+	"tmux":                "tmux",
+	"tree":                "tree",
+	"watchman":            "watchman",
+	"wget":                "wget",
+	"yarn":                "yarn",
+	"yq":                  "yq-go",
+	"zsh":                 "zsh",
+}
+
+// fromBrewfile converts the formulas in a Brewfile into packages. Versioned
+// formulas, like node@18, keep their version, which devbox resolves the same
+// way. Casks and other entries that aren't formulas are reported in Warnings.
+func fromBrewfile(name string, content []byte, rec *Recommendation) {
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		m := brewfileEntryRegex.FindStringSubmatch(strings.TrimSpace(s.Text()))
+		if m == nil {
+			continue
+		}
+		kind, entry := m[1], m[2]
+		switch kind {
+		case "tap":
+			// Taps only add sources of formulas.
+		case "brew":
+			pkg, ok := brewPackage(entry)
+			if !ok {
+				rec.Warnings = append(rec.Warnings, fmt.Sprintf(
+					"%s has formula %s, which devbox doesn't know the package for. Find it with `devbox search`.",
+					name, entry,
+				))
+				continue
+			}
+			if !slices.Contains(rec.Packages, pkg) {
+				rec.Packages = append(rec.Packages, pkg)
+			}
+		default:
+			rec.Warnings = append(rec.Warnings, fmt.Sprintf(
+				"%s has %s %s, which isn't a formula. Devbox only imports formulas.",
+				name, kind, entry,
+			))
+		}
+	}
+}
+
+// brewPackage returns the package for a formula. Formulas from taps, like
+// hashicorp/tap/terraform, aren't in nixpkgs.
+func brewPackage(formula string) (string, bool) {
+	if strings.Contains(formula, "/") {
+		return "", false
+	}
+	name, version, versioned := strings.Cut(formula, "@")
+	pkg, ok := brewFormulas[name]
+	if !ok {
+		return "", false
+	}
+	if versioned {
+		if versionedPkg, ok := brewVersionedFormulas[name]; ok {
+			pkg = versionedPkg
+		}
+		pkg += "@" + version
+	}
+	return pkg, true
+}
+
+// brewVersionedFormulas are the packages of versioned formulas whose
+// unversioned package already names a version, like python3 or mysql80.
+var brewVersionedFormulas = map[string]string{
+	"mysql":  "mysql",
+	"python": "python",
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package initrec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromBrewfile(t *testing.T) {
+	brewfile := `tap "hashicorp/tap"
+brew "node@18"
+brew "python@3.11"
+brew "mysql@8.0"
+brew "postgresql@15", restart_service: true
+brew "gnu-sed"
+brew "hashicorp/tap/terraform"
+brew "frobnicate"
+cask "docker"
+`
+	path := filepath.Join(t.TempDir(), "Brewfile")
+	if err := os.WriteFile(path, []byte(brewfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := FromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPkgs := []string{"nodejs@18", "python@3.11", "mysql@8.0", "postgresql@15", "gnused"}
+	if diff := cmp.Diff(wantPkgs, rec.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantWarnings := []string{
+		"Brewfile has formula hashicorp/tap/terraform, which devbox doesn't know the package for. Find it with `devbox search`.",
+		"Brewfile has formula frobnicate, which devbox doesn't know the package for. Find it with `devbox search`.",
+		"Brewfile has cask docker, which isn't a formula. Devbox only imports formulas.",
+	}
+	if diff := cmp.Diff(wantWarnings, rec.Warnings); diff != "" {
+		t.Errorf("wrong warnings (-want +got):\n%s", diff)
+	}
+}
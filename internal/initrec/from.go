@@ -0,0 +1,44 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package initrec
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+)
+
+// FromFile returns the packages and settings of the environment defined in
+// an existing file, such as a shell.nix or Brewfile, so that devbox init can migrate it to
+// a devbox config. Unlike the recommendations of Get, every package is meant
+// to be added to the config.
+func FromFile(path string) (*Recommendation, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, usererr.New("File %s doesn't exist.", path)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rec := newRecommendation()
+	name := filepath.Base(path)
+	switch {
+	case filepath.Ext(name) == ".nix":
+		rec.Projects = append(rec.Projects, "Nix")
+		fromNixFile(name, content, rec)
+	case name == "Brewfile":
+		rec.Projects = append(rec.Projects, "Homebrew")
+		fromBrewfile(name, content, rec)
+	default:
+		return nil, usererr.New(
+			"Devbox can't import %s. It can import a Brewfile or Nix files, such as shell.nix or flake.nix.",
+			name,
+		)
+	}
+	return rec, nil
+}
@@ -4,47 +4,279 @@
 package initrec
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 	"github.com/samber/lo"
 
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
+	"go.jetpack.io/devbox/internal/initrec/recommenders/cpp"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/dotnet"
+	"go.jetpack.io/devbox/internal/initrec/recommenders/elixir"
+	"go.jetpack.io/devbox/internal/initrec/recommenders/external"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/golang"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/haskell"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/java"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/javascript"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/nginx"
+	"go.jetpack.io/devbox/internal/initrec/recommenders/php"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/python"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/ruby"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/rust"
 	"go.jetpack.io/devbox/internal/initrec/recommenders/zig"
+	"go.jetpack.io/devbox/internal/searcher"
 )
 
-func getRecommenders(srcDir string) []recommenders.Recommender {
-	return []recommenders.Recommender{
-		&dotnet.Recommender{SrcDir: srcDir},
-		&golang.Recommender{SrcDir: srcDir},
-		&haskell.Recommender{SrcDir: srcDir},
-		&java.Recommender{SrcDir: srcDir},
-		&javascript.Recommender{SrcDir: srcDir},
-		&nginx.Recommender{SrcDir: srcDir},
-		&python.RecommenderPip{SrcDir: srcDir},
-		&python.RecommenderPoetry{SrcDir: srcDir},
-		&ruby.Recommender{SrcDir: srcDir},
-		&rust.Recommender{SrcDir: srcDir},
-		&zig.Recommender{SrcDir: srcDir},
-	}
-}
-
-func Get(srcDir string) ([]string, error) {
-	// Using a map of string-bool instead of array of strings to prevent duplication
-	result := map[string]bool{}
-	for _, sg := range getRecommenders(srcDir) {
-		if sg.IsRelevant() {
-			for _, pkg := range sg.Packages() {
-				result[pkg] = true
+// namedRecommender is a recommender and the name of the kind of project it
+// detects, which is used in messages.
+type namedRecommender struct {
+	name string
+	recommenders.Recommender
+}
+
+// getRecommenders returns the recommenders in the order their
+// recommendations are merged. External recommenders come first, so they take
+// precedence over the built-in ones.
+func getRecommenders(srcDir string) ([]namedRecommender, error) {
+	externals, err := external.Load(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	var result []namedRecommender
+	for _, r := range externals {
+		result = append(result, namedRecommender{r.Name(), r})
+	}
+	return append(result, builtinRecommenders(srcDir)...), nil
+}
+
+func builtinRecommenders(srcDir string) []namedRecommender {
+	return []namedRecommender{
+		{"C/C++", &cpp.Recommender{SrcDir: srcDir}},
+		{".NET", &dotnet.Recommender{SrcDir: srcDir}},
+		{"Elixir", &elixir.Recommender{SrcDir: srcDir}},
+		{"Go", &golang.Recommender{SrcDir: srcDir}},
+		{"Haskell", &haskell.Recommender{SrcDir: srcDir}},
+		{"Java", &java.Recommender{SrcDir: srcDir}},
+		{"Node.js", &javascript.Recommender{SrcDir: srcDir}},
+		{"nginx", &nginx.Recommender{SrcDir: srcDir}},
+		{"PHP", &php.Recommender{SrcDir: srcDir}},
+		{"Python (pip)", &python.RecommenderPip{SrcDir: srcDir}},
+		{"Python (pipenv)", &python.RecommenderPipenv{SrcDir: srcDir}},
+		{"Python (poetry)", &python.RecommenderPoetry{SrcDir: srcDir}},
+		{"Ruby", &ruby.Recommender{SrcDir: srcDir}},
+		{"Rust", &rust.Recommender{SrcDir: srcDir}},
+		{"Zig", &zig.Recommender{SrcDir: srcDir}},
+	}
+}
+
+// Recommendation is what devbox recommends for the projects it detects in a
+// directory. A directory can have several projects, such as a Go backend and
+// a Node.js frontend, so it merges the recommendations of every relevant
+// recommender in the order of getRecommenders:
+//
+//   - packages are combined. If two projects need different versions of the
+//     same package, the first version wins.
+//   - packages use the exact version in the project's version file (such as
+//     .nvmrc) if it's available. Otherwise, they use the closest version the
+//     recommender found and a warning is added to Warnings.
+//   - env variables are combined. PATH entries are joined, and for other
+//     variables the first value wins.
+//   - init hook commands are combined, without duplicates.
+//   - scripts with the same name run each project's commands in order. For
+//     example, build runs `go build ./...` and then `npm run build`.
+//   - script inputs are combined. If any project's commands for a script
+//     don't list their inputs, the script has none, since it may need every
+//     file in the project.
+//
+// Recommendations that lose are described in Conflicts.
+type Recommendation struct {
+	Projects  []string            `json:"projects"`
+	Packages  []string            `json:"packages"`
+	Env       map[string]string   `json:"env"`
+	InitHook  []string            `json:"init_hook"`
+	Scripts   map[string][]string `json:"scripts"`
+	Conflicts []string            `json:"conflicts"`
+
+	// Warnings describe versions that devbox approximated.
+	Warnings []string `json:"warnings"`
+
+	// ScriptInputs are the files that each script needs. Scripts that
+	// aren't listed may need the whole project.
+	ScriptInputs map[string][]string `json:"script_inputs"`
+}
+
+func newRecommendation() *Recommendation {
+	return &Recommendation{
+		Projects:  []string{},
+		Packages:  []string{},
+		Env:       map[string]string{},
+		InitHook:  []string{},
+		Scripts:   map[string][]string{},
+		Conflicts: []string{},
+		Warnings:  []string{},
+
+		ScriptInputs: map[string][]string{},
+	}
+}
+
+func Get(srcDir string) (*Recommendation, error) {
+	rec := newRecommendation()
+	// Who recommended each package and env variable, for conflicts.
+	pkgOwners := map[string]string{}
+	envOwners := map[string]string{}
+	// Scripts with commands that don't list their inputs.
+	needsAllFiles := map[string]bool{}
+
+	recs, err := getRecommenders(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range recs {
+		if !r.IsRelevant() {
+			continue
+		}
+		rec.Projects = append(rec.Projects, r.name)
+		// TODO: check for already installed packages
+		pkgs := r.Packages()
+		if versionRec, ok := r.Recommender.(recommenders.VersionRecommender); ok {
+			pkgs = rec.useRequestedVersions(pkgs, versionRec.RequestedVersions())
+		}
+		for _, pkg := range pkgs {
+			rec.addPackage(r.name, pkg, pkgOwners)
+		}
+		if envRec, ok := r.Recommender.(recommenders.EnvRecommender); ok {
+			env := envRec.Env()
+			for _, k := range sortedKeys(env) {
+				rec.addEnv(r.name, k, env[k], envOwners)
 			}
 		}
+		if hookRec, ok := r.Recommender.(recommenders.InitHookRecommender); ok {
+			for _, cmd := range hookRec.InitHook() {
+				if !slices.Contains(rec.InitHook, cmd) {
+					rec.InitHook = append(rec.InitHook, cmd)
+				}
+			}
+		}
+		if scriptRec, ok := r.Recommender.(recommenders.ScriptRecommender); ok {
+			var inputs map[string][]string
+			if inputsRec, ok := r.Recommender.(recommenders.ScriptInputsRecommender); ok {
+				inputs = inputsRec.ScriptInputs()
+			}
+			for name, cmd := range scriptRec.Scripts() {
+				if !slices.Contains(rec.Scripts[name], cmd) {
+					rec.Scripts[name] = append(rec.Scripts[name], cmd)
+				}
+				if len(inputs[name]) == 0 {
+					needsAllFiles[name] = true
+				}
+				for _, input := range inputs[name] {
+					if !slices.Contains(rec.ScriptInputs[name], input) {
+						rec.ScriptInputs[name] = append(rec.ScriptInputs[name], input)
+					}
+				}
+			}
+		}
+	}
+	for name := range needsAllFiles {
+		delete(rec.ScriptInputs, name)
+	}
+	return rec, nil
+}
+
+// resolveTimeout is how long devbox waits for the search service when it
+// checks whether a version is available.
+const resolveTimeout = 10 * time.Second
+
+// versionAvailable reports whether devbox can install the version of a
+// package. It's a variable so tests don't call the search service.
+var versionAvailable = func(name, version string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	_, err := searcher.Client().ResolveV2(ctx, name, version)
+	if errors.Is(err, searcher.ErrNotFound) {
+		return false, nil
 	}
-	// TODO: check for already installed packages
-	return lo.Keys(result), nil
+	return err == nil, err
+}
+
+// useRequestedVersions replaces the versions in pkgs with the exact versions
+// the project asks for when devbox can install them. Versions that it can't
+// install are kept, with a warning.
+func (r *Recommendation) useRequestedVersions(
+	pkgs []string,
+	requested []recommenders.RequestedVersion,
+) []string {
+	pkgs = slices.Clone(pkgs)
+	for _, req := range requested {
+		i := slices.IndexFunc(pkgs, func(p string) bool {
+			name, _, _ := strings.Cut(p, "@")
+			return name == req.Package
+		})
+		if i == -1 {
+			continue
+		}
+		if _, version, _ := strings.Cut(pkgs[i], "@"); version == req.Version {
+			continue
+		}
+		available, err := versionAvailable(req.Package, req.Version)
+		switch {
+		case available:
+			pkgs[i] = req.Package + "@" + req.Version
+		case err != nil:
+			r.Warnings = append(r.Warnings, fmt.Sprintf(
+				"%s asks for %s %s, but devbox couldn't check if it's available. Using %s.",
+				req.File, req.Package, req.Version, pkgs[i],
+			))
+		default:
+			r.Warnings = append(r.Warnings, fmt.Sprintf(
+				"%s asks for %s %s, which isn't available. Using %s, the closest version.",
+				req.File, req.Package, req.Version, pkgs[i],
+			))
+		}
+	}
+	return pkgs
+}
+
+func (r *Recommendation) addPackage(project, pkg string, owners map[string]string) {
+	name, _, _ := strings.Cut(pkg, "@")
+	i := slices.IndexFunc(r.Packages, func(p string) bool {
+		n, _, _ := strings.Cut(p, "@")
+		return n == name
+	})
+	switch {
+	case i == -1:
+		r.Packages = append(r.Packages, pkg)
+		owners[name] = project
+	case r.Packages[i] != pkg:
+		r.Conflicts = append(r.Conflicts, fmt.Sprintf(
+			"%s recommends %s, but %s recommends %s. Using %[4]s.",
+			project, pkg, owners[name], r.Packages[i],
+		))
+	}
+}
+
+func (r *Recommendation) addEnv(project, key, val string, owners map[string]string) {
+	existing, ok := r.Env[key]
+	switch {
+	case !ok:
+		r.Env[key] = val
+		owners[key] = project
+	case key == "PATH":
+		r.Env[key] = strings.TrimSuffix(existing, ":$PATH") + ":" + val
+	case existing != val:
+		r.Conflicts = append(r.Conflicts, fmt.Sprintf(
+			"%s sets %s=%s, but %s sets %[2]s=%[5]s. Using %[4]s's value.",
+			project, key, val, owners[key], existing,
+		))
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
 }
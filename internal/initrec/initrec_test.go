@@ -0,0 +1,104 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package initrec
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestGetMultipleProjects(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.21\n",
+		"package.json": `{"scripts": {"build": "vite build"}}`,
+	}
+	fileutil.WriteFilesForTest(t, dir, files)
+
+	rec, err := Get(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"Go", "Node.js"}, rec.Projects); diff != "" {
+		t.Errorf("wrong projects (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"go@1.21", "nodejs"}, rec.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantPath := "{{.DevboxDir}}/go/bin:{{.ProjectDir}}/node_modules/.bin:$PATH"
+	if got := rec.Env["PATH"]; got != wantPath {
+		t.Errorf("got PATH %q, want %q", got, wantPath)
+	}
+	wantBuild := []string{"go build ./...", "npm run build"}
+	if diff := cmp.Diff(wantBuild, rec.Scripts["build"]); diff != "" {
+		t.Errorf("wrong build script (-want +got):\n%s", diff)
+	}
+	wantInputs := map[string][]string{"install": {"go.mod", "package.json"}}
+	if diff := cmp.Diff(wantInputs, rec.ScriptInputs); diff != "" {
+		t.Errorf("wrong script inputs (-want +got):\n%s", diff)
+	}
+	if len(rec.Conflicts) != 0 {
+		t.Errorf("got conflicts %v, want none", rec.Conflicts)
+	}
+}
+
+func TestRecommendationConflicts(t *testing.T) {
+	rec := &Recommendation{Env: map[string]string{}}
+	pkgOwners := map[string]string{}
+	envOwners := map[string]string{}
+	rec.addPackage("Ruby", "gcc", pkgOwners)
+	rec.addPackage("C/C++", "gcc", pkgOwners)
+	rec.addPackage("Go", "go@1.21", pkgOwners)
+	rec.addPackage("Other", "go@1.20", pkgOwners)
+	rec.addEnv("Go", "GOPATH", "{{.DevboxDir}}/go", envOwners)
+	rec.addEnv("Other", "GOPATH", "$HOME/go", envOwners)
+
+	if diff := cmp.Diff([]string{"gcc", "go@1.21"}, rec.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	if got, want := rec.Env["GOPATH"], "{{.DevboxDir}}/go"; got != want {
+		t.Errorf("got GOPATH %q, want %q", got, want)
+	}
+	wantConflicts := []string{
+		"Other recommends go@1.20, but Go recommends go@1.21. Using go@1.21.",
+		"Other sets GOPATH=$HOME/go, but Go sets GOPATH={{.DevboxDir}}/go. Using Go's value.",
+	}
+	if diff := cmp.Diff(wantConflicts, rec.Conflicts); diff != "" {
+		t.Errorf("wrong conflicts (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetRequestedVersions(t *testing.T) {
+	available := map[string]bool{"nodejs@20.9.0": true}
+	orig := versionAvailable
+	versionAvailable = func(name, version string) (bool, error) {
+		return available[name+"@"+version], nil
+	}
+	t.Cleanup(func() { versionAvailable = orig })
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json":     `{}`,
+		".nvmrc":           "v20.9.0\n",
+		"requirements.txt": "flask\n",
+		".python-version":  "3.11.2\n",
+	}
+	fileutil.WriteFilesForTest(t, dir, files)
+
+	rec, err := Get(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"nodejs@20.9.0", "python@3.11"}, rec.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantWarnings := []string{
+		".python-version asks for python 3.11.2, which isn't available. Using python@3.11, the closest version.",
+	}
+	if diff := cmp.Diff(wantWarnings, rec.Warnings); diff != "" {
+		t.Errorf("wrong warnings (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package initrec
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// This file converts the shells in a shell.nix or flake.nix into a
+// recommendation. It doesn't evaluate Nix. Instead, it looks for the parts of
+// mkShell that have a devbox equivalent:
+//
+//   - the packages in buildInputs, nativeBuildInputs and packages.
+//   - the shellHook, which becomes the init hook.
+//   - upper case attributes set to a string, which become env variables.
+//
+// Anything it can't convert, such as python3.withPackages (ps: [...]), is
+// reported in Warnings so the user can add it by hand.
+
+var (
+	// Matches the start of a package list, such as "packages = with pkgs; [".
+	nixPackageListRegex = regexp.MustCompile(
+		`\b(?:buildInputs|nativeBuildInputs|packages)\s*=\s*(?:with\s+[\w.]+\s*;\s*)?\[`)
+	// Matches the start of the shellHook string.
+	nixShellHookRegex = regexp.MustCompile(`\bshellHook\s*=\s*(''|")`)
+	// Matches an env variable, such as FOO = "bar";
+	nixEnvRegex = regexp.MustCompile(`(?m)^\s*([A-Z_][A-Z0-9_]*)\s*=\s*"((?:[^"\\]|\\.)*)"\s*;`)
+	// Matches a package reference, such as pkgs.nodejs_20 or
+	// python311Packages.pip.
+	nixPackageRegex = regexp.MustCompile(`^[A-Za-z_][\w'-]*(?:\.[A-Za-z_][\w'-]*)*$`)
+)
+
+// fromNixFile converts the contents of a shell.nix or flake.nix.
+func fromNixFile(name string, content []byte, rec *Recommendation) {
+	src := stripNixComments(string(content))
+
+	for _, loc := range nixPackageListRegex.FindAllStringIndex(src, -1) {
+		for _, elem := range nixListElements(src[loc[1]:]) {
+			pkg, ok := nixPackageName(elem)
+			if !ok {
+				rec.Warnings = append(rec.Warnings, fmt.Sprintf(
+					"%s has a package that devbox can't convert: %s. Add it to devbox.json by hand.",
+					name, elem,
+				))
+				continue
+			}
+			if !slices.Contains(rec.Packages, pkg) {
+				rec.Packages = append(rec.Packages, pkg)
+			}
+		}
+	}
+
+	for _, m := range nixShellHookRegex.FindAllStringSubmatchIndex(src, -1) {
+		quote := src[m[2]:m[3]]
+		hook, ok := nixStringContents(src[m[1]:], quote)
+		if !ok {
+			continue
+		}
+		// ''${ is how '' strings escape a literal ${, so any other ${ is
+		// Nix interpolation, which the init hook can't do.
+		if quote == "''" {
+			if strings.Contains(strings.ReplaceAll(hook, "''${", ""), "${") {
+				rec.Warnings = append(rec.Warnings, fmt.Sprintf(
+					"%s has a shellHook that uses Nix interpolation. Add it to the init_hook in devbox.json by hand.",
+					name,
+				))
+				continue
+			}
+			hook = strings.ReplaceAll(hook, "''${", "${")
+		}
+		// Flakes often repeat the same shell for every system, so a hook
+		// that's already in the init hook is skipped as a whole. Lines
+		// like fi or done repeat in different hooks, so they're kept.
+		if lines := dedent(hook); !containsLines(rec.InitHook, lines) {
+			rec.InitHook = append(rec.InitHook, lines...)
+		}
+	}
+
+	for _, m := range nixEnvRegex.FindAllStringSubmatch(src, -1) {
+		key, val := m[1], m[2]
+		if strings.Contains(val, "${") {
+			rec.Warnings = append(rec.Warnings, fmt.Sprintf(
+				"%s sets %s to a value that uses Nix interpolation. Add it to devbox.json by hand.",
+				name, key,
+			))
+			continue
+		}
+		if _, ok := rec.Env[key]; !ok {
+			rec.Env[key] = strings.ReplaceAll(val, `\"`, `"`)
+		}
+	}
+}
+
+// containsLines reports whether lines appear in hook, in order and next to
+// each other.
+func containsLines(hook, lines []string) bool {
+	for i := 0; i+len(lines) <= len(hook); i++ {
+		if slices.Equal(hook[i:i+len(lines)], lines) {
+			return true
+		}
+	}
+	return false
+}
+
+// nixPackageName returns the devbox package for an element of a package list.
+// References are relative to nixpkgs, so the pkgs. prefix is dropped.
+func nixPackageName(elem string) (string, bool) {
+	if !nixPackageRegex.MatchString(elem) {
+		return "", false
+	}
+	for _, prefix := range []string{"pkgs.", "nixpkgs."} {
+		elem = strings.TrimPrefix(elem, prefix)
+	}
+	return elem, true
+}
+
+// nixListElements returns the elements of the list that starts at the
+// beginning of src, just after its opening bracket. Elements with nested
+// lists, parentheses or strings are returned as a single element.
+func nixListElements(src string) []string {
+	var elems []string
+	var current strings.Builder
+	depth := 0
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			elems = append(elems, strings.Join(strings.Fields(s), " "))
+		}
+		current.Reset()
+	}
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end == -1 {
+				return elems
+			}
+			current.WriteString(src[i : i+end+2])
+			i += end + 1
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == '}':
+			depth--
+		case c == ']':
+			if depth == 0 {
+				flush()
+				return elems
+			}
+			depth--
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			flush()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	return elems
+}
+
+// nixStringContents returns the contents of the string that starts at the
+// beginning of src, just after its opening quote (either a double quote or
+// the two single quotes of an indented string). It skips escaped quotes.
+func nixStringContents(src, quote string) (string, bool) {
+	for i := 0; i < len(src); i++ {
+		switch {
+		case quote == `"` && src[i] == '\\':
+			i++
+		case quote == `"` && src[i] == '"':
+			return src[:i], true
+		case quote == "''" && strings.HasPrefix(src[i:], "''"):
+			if i+2 < len(src) && strings.ContainsRune(`$'\`, rune(src[i+2])) {
+				i += 2
+				continue
+			}
+			return src[:i], true
+		}
+	}
+	return "", false
+}
+
+// stripNixComments removes # comments that aren't inside a string.
+func stripNixComments(src string) string {
+	var b strings.Builder
+	inString := ""
+	for i := 0; i < len(src); i++ {
+		switch {
+		case inString == "''" && strings.HasPrefix(src[i:], "''") &&
+			i+2 < len(src) && strings.ContainsRune(`$'\`, rune(src[i+2])):
+			b.WriteString(src[i : i+3])
+			i += 2
+			continue
+		case inString != "" && strings.HasPrefix(src[i:], inString):
+			b.WriteString(inString)
+			i += len(inString) - 1
+			inString = ""
+			continue
+		case inString == `"` && src[i] == '\\' && i+1 < len(src):
+			b.WriteString(src[i : i+2])
+			i++
+			continue
+		case inString == "" && strings.HasPrefix(src[i:], "''"):
+			inString = "''"
+			b.WriteString(inString)
+			i++
+			continue
+		case inString == "" && src[i] == '"':
+			inString = `"`
+		case inString == "" && src[i] == '#':
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				return b.String()
+			}
+			i += end - 1
+			continue
+		}
+		b.WriteByte(src[i])
+	}
+	return b.String()
+}
+
+// dedent splits an indented string into lines and removes the indentation
+// they have in common, like Nix does for indented strings. Blank lines are
+// dropped.
+func dedent(s string) []string {
+	var lines []string
+	indent := -1
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	for i, line := range lines {
+		lines[i] = line[indent:]
+	}
+	return lines
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package initrec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromShellNix(t *testing.T) {
+	shellNix := `{ pkgs ? import <nixpkgs> {} }:
+
+pkgs.mkShell {
+  # Tools for the backend.
+  buildInputs = with pkgs; [
+    go_1_21
+    pkgs.postgresql # the database
+    (python3.withPackages (ps: [ ps.requests ]))
+  ];
+  nativeBuildInputs = [ pkgs.pkg-config pkgs.go_1_21 ];
+
+  DATABASE_URL = "postgres://localhost:5432/app";
+  SSL_CERT_FILE = "${pkgs.cacert}/etc/ssl/certs/ca-bundle.crt";
+
+  shellHook = ''
+    # Comments in the hook are kept.
+    export GOFLAGS=-mod=mod
+    echo "''${USER} is in the shell"
+  '';
+}
+`
+	path := filepath.Join(t.TempDir(), "shell.nix")
+	if err := os.WriteFile(path, []byte(shellNix), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := FromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"go_1_21", "postgresql", "pkg-config"}, rec.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	wantHook := []string{
+		"# Comments in the hook are kept.",
+		"export GOFLAGS=-mod=mod",
+		`echo "${USER} is in the shell"`,
+	}
+	if diff := cmp.Diff(wantHook, rec.InitHook); diff != "" {
+		t.Errorf("wrong init hook (-want +got):\n%s", diff)
+	}
+	wantEnv := map[string]string{"DATABASE_URL": "postgres://localhost:5432/app"}
+	if diff := cmp.Diff(wantEnv, rec.Env); diff != "" {
+		t.Errorf("wrong env (-want +got):\n%s", diff)
+	}
+	wantWarnings := []string{
+		"shell.nix has a package that devbox can't convert: (python3.withPackages (ps: [ ps.requests ])). Add it to devbox.json by hand.",
+		"shell.nix sets SSL_CERT_FILE to a value that uses Nix interpolation. Add it to devbox.json by hand.",
+	}
+	if diff := cmp.Diff(wantWarnings, rec.Warnings); diff != "" {
+		t.Errorf("wrong warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestFromFlakeNix(t *testing.T) {
+	flakeNix := `{
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";
+  outputs = { self, nixpkgs }: let
+    pkgs = nixpkgs.legacyPackages.x86_64-linux;
+  in {
+    devShells.x86_64-linux.default = pkgs.mkShell {
+      packages = [ pkgs.nodejs_20 pkgs.nodePackages.pnpm ];
+    };
+  };
+}
+`
+	path := filepath.Join(t.TempDir(), "flake.nix")
+	if err := os.WriteFile(path, []byte(flakeNix), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := FromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"nodejs_20", "nodePackages.pnpm"}, rec.Packages); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	if len(rec.Warnings) != 0 {
+		t.Errorf("got warnings %v, want none", rec.Warnings)
+	}
+}
+
+func TestFromFlakeNixShellHooks(t *testing.T) {
+	flakeNix := `{
+  outputs = { self, nixpkgs }: {
+    devShells.x86_64-linux.default = pkgs.mkShell {
+      shellHook = ''
+        if [ -f .env ]; then
+          source .env
+        fi
+        if [ -d bin ]; then
+          export PATH=$PWD/bin:$PATH
+        fi
+      '';
+    };
+    devShells.aarch64-darwin.default = pkgs.mkShell {
+      shellHook = ''
+        if [ -f .env ]; then
+          source .env
+        fi
+        if [ -d bin ]; then
+          export PATH=$PWD/bin:$PATH
+        fi
+      '';
+    };
+  };
+}
+`
+	path := filepath.Join(t.TempDir(), "flake.nix")
+	if err := os.WriteFile(path, []byte(flakeNix), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := FromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHook := []string{
+		"if [ -f .env ]; then",
+		"  source .env",
+		"fi",
+		"if [ -d bin ]; then",
+		"  export PATH=$PWD/bin:$PATH",
+		"fi",
+	}
+	if diff := cmp.Diff(wantHook, rec.InitHook); diff != "" {
+		t.Errorf("wrong init hook (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package cpp
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+)
+
+const (
+	cmakeLists = "CMakeLists.txt"
+	makefile   = "Makefile"
+)
+
+// sourcePatterns match C and C++ sources. Makefiles are common in projects
+// written in other languages, so a Makefile alone doesn't make a C project.
+var sourcePatterns = []string{
+	"*.{c,cc,cpp,cxx,h,hh,hpp}",
+	"src/*.{c,cc,cpp,cxx,h,hh,hpp}",
+}
+
+// compiler is a C and C++ compiler and the package that provides it.
+type compiler struct {
+	pkg string
+	cc  string
+	cxx string
+}
+
+var (
+	gcc   = compiler{pkg: "gcc", cc: "gcc", cxx: "g++"}
+	clang = compiler{pkg: "clang", cc: "clang", cxx: "clang++"}
+)
+
+// compilerPattern matches the compiler that a Makefile (CC = clang) or
+// CMakeLists.txt (set(CMAKE_C_COMPILER clang)) asks for.
+var compilerPattern = regexp.MustCompile(
+	`(?m)(?:^\s*(?:export\s+)?(?:CC|CXX)\s*[:?]?=\s*|CMAKE_(?:C|CXX)_COMPILER[\s"]+)([\w.+/-]+)`,
+)
+
+type Recommender struct {
+	SrcDir string
+}
+
+// implements interfaces recommenders.EnvRecommender and
+// recommenders.ScriptRecommender (compile-time check)
+var (
+	_ recommenders.EnvRecommender    = (*Recommender)(nil)
+	_ recommenders.ScriptRecommender = (*Recommender)(nil)
+)
+
+func (r *Recommender) IsRelevant() bool {
+	if r.isCMakeProject() {
+		return true
+	}
+	if !fileutil.Exists(filepath.Join(r.SrcDir, makefile)) {
+		return false
+	}
+	a, err := analyzer.NewAnalyzer(r.SrcDir)
+	if err != nil {
+		// We should log that an error has occurred.
+		return false
+	}
+	return a.HasAnyFile(sourcePatterns...)
+}
+
+func (r *Recommender) Packages() []string {
+	pkgs := []string{r.compiler().pkg, "pkg-config"}
+	if r.isCMakeProject() {
+		return append(pkgs, "cmake", "ninja")
+	}
+	return append(pkgs, "gnumake")
+}
+
+// Env selects the compiler and lets pkg-config find the libraries installed
+// with devbox. To switch compilers, replace the compiler package and change
+// CC and CXX in devbox.json.
+func (r *Recommender) Env() map[string]string {
+	c := r.compiler()
+	return map[string]string{
+		"CC":              c.cc,
+		"CXX":             c.cxx,
+		"PKG_CONFIG_PATH": "{{.DevboxDir}}/nix/profile/default/lib/pkgconfig:{{.DevboxDir}}/nix/profile/default/share/pkgconfig:$PKG_CONFIG_PATH",
+	}
+}
+
+func (r *Recommender) Scripts() map[string]string {
+	if r.isCMakeProject() {
+		return map[string]string{
+			"configure": "cmake -S . -B build -G Ninja",
+			"build":     "cmake --build build",
+			"test":      "ctest --test-dir build",
+		}
+	}
+	return map[string]string{"build": "make"}
+}
+
+func (r *Recommender) isCMakeProject() bool {
+	return fileutil.Exists(filepath.Join(r.SrcDir, cmakeLists))
+}
+
+// compiler returns the compiler that the project's CMakeLists.txt or Makefile
+// asks for, or the default compiler if they don't set one.
+func (r *Recommender) compiler() compiler {
+	for _, name := range []string{cmakeLists, makefile} {
+		content, err := os.ReadFile(filepath.Join(r.SrcDir, name))
+		if err != nil {
+			continue
+		}
+		for _, match := range compilerPattern.FindAllSubmatch(content, -1) {
+			switch program := filepath.Base(string(match[1])); {
+			case strings.Contains(program, "clang"):
+				return clang
+			case strings.Contains(program, "gcc"), strings.Contains(program, "g++"):
+				return gcc
+			}
+		}
+	}
+	return defaultCompiler()
+}
+
+// defaultCompiler is clang on macOS, where it's the system compiler, and gcc
+// everywhere else.
+func defaultCompiler() compiler {
+	if runtime.GOOS == "darwin" {
+		return clang
+	}
+	return gcc
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package cpp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestRecommender(t *testing.T) {
+	cc := defaultCompiler().pkg
+	testCases := []struct {
+		name         string
+		files        []string
+		wantRelevant bool
+		wantPackages []string
+	}{
+		{
+			name:         "cmake",
+			files:        []string{"CMakeLists.txt"},
+			wantRelevant: true,
+			wantPackages: []string{cc, "pkg-config", "cmake", "ninja"},
+		},
+		{
+			name:         "make",
+			files:        []string{"Makefile", "src/main.c"},
+			wantRelevant: true,
+			wantPackages: []string{cc, "pkg-config", "gnumake"},
+		},
+		{
+			name:         "make-without-sources",
+			files:        []string{"Makefile", "main.go"},
+			wantRelevant: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tc.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := &Recommender{SrcDir: dir}
+			if got := r.IsRelevant(); got != tc.wantRelevant {
+				t.Fatalf("got IsRelevant() = %v, want %v", got, tc.wantRelevant)
+			}
+			if !tc.wantRelevant {
+				return
+			}
+			if diff := cmp.Diff(tc.wantPackages, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecommenderCompiler(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  compiler
+	}{
+		{
+			name:  "makefile-clang",
+			files: map[string]string{"Makefile": "CC ?= clang\nCFLAGS = -O2\n", "main.c": ""},
+			want:  clang,
+		},
+		{
+			name:  "makefile-gcc-path",
+			files: map[string]string{"Makefile": "export CXX := /usr/bin/g++-13\n", "main.cpp": ""},
+			want:  gcc,
+		},
+		{
+			name:  "cmake-clang",
+			files: map[string]string{"CMakeLists.txt": "set(CMAKE_CXX_COMPILER \"clang++\")\nproject(app)\n"},
+			want:  clang,
+		},
+		{
+			name:  "cmake-gcc",
+			files: map[string]string{"CMakeLists.txt": "set(CMAKE_C_COMPILER gcc)\n"},
+			want:  gcc,
+		},
+		{
+			name:  "unset",
+			files: map[string]string{"CMakeLists.txt": "project(app)\n"},
+			want:  defaultCompiler(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			r := &Recommender{SrcDir: dir}
+			if got := r.Packages()[0]; got != tc.want.pkg {
+				t.Errorf("got compiler package %q, want %q", got, tc.want.pkg)
+			}
+			env := r.Env()
+			if env["CC"] != tc.want.cc || env["CXX"] != tc.want.cxx {
+				t.Errorf("got CC=%q CXX=%q, want CC=%q CXX=%q", env["CC"], env["CXX"], tc.want.cc, tc.want.cxx)
+			}
+		})
+	}
+}
@@ -4,7 +4,11 @@
 package dotnet
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -23,16 +27,28 @@ type Project struct {
 const (
 	CSharpExtension = "csproj"
 	FSharpExtension = "fsproj"
+	globalJSON      = "global.json"
 )
 
+// Matches the major version of target frameworks like net8.0 and
+// net6.0-windows.
+var netTargetRegex = regexp.MustCompile(`^net([0-9]+)\.`)
+
 type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interfaces recommenders.EnvRecommender and
+// recommenders.ScriptRecommender (compile-time check)
+var (
+	_ recommenders.EnvRecommender    = (*Recommender)(nil)
+	_ recommenders.ScriptRecommender = (*Recommender)(nil)
+)
 
 func (r *Recommender) IsRelevant() bool {
+	if globalJSONPackage(r.SrcDir) != "" {
+		return true
+	}
 	a, err := analyzer.NewAnalyzer(r.SrcDir)
 	if err != nil {
 		// We should log that an error has occurred.
@@ -45,7 +61,12 @@ func (r *Recommender) IsRelevant() bool {
 	return isRelevant
 }
 
+// Packages returns the SDK that global.json pins or, if there's no
+// global.json, the SDK for the project's target framework.
 func (r *Recommender) Packages() []string {
+	if pkg := globalJSONPackage(r.SrcDir); pkg != "" {
+		return []string{pkg}
+	}
 	proj, err := project(r.SrcDir)
 	if err != nil {
 		return nil
@@ -57,6 +78,46 @@ func (r *Recommender) Packages() []string {
 	return []string{dotNetPkg}
 }
 
+// Env keeps the dotnet CLI's files and the NuGet package cache in .devbox
+// instead of the user's home directory.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"DOTNET_CLI_HOME": "{{.DevboxDir}}/dotnet",
+		"NUGET_PACKAGES":  "{{.DevboxDir}}/nuget/packages",
+	}
+}
+
+func (r *Recommender) Scripts() map[string]string {
+	return map[string]string{
+		"restore": "dotnet restore",
+		"build":   "dotnet build --no-restore",
+		"publish": "dotnet publish -c Release",
+	}
+}
+
+// globalJSONPackage returns the dotnet-sdk package for the SDK version in
+// global.json, or an empty string if there's no global.json or it doesn't set
+// a version.
+func globalJSONPackage(srcDir string) string {
+	content, err := os.ReadFile(filepath.Join(srcDir, globalJSON))
+	if err != nil {
+		return ""
+	}
+	var global struct {
+		SDK struct {
+			Version string `json:"version"`
+		} `json:"sdk"`
+	}
+	if err := json.Unmarshal(content, &global); err != nil {
+		return ""
+	}
+	v, err := analyzer.NewVersion(global.SDK.Version)
+	if err != nil {
+		return ""
+	}
+	return "dotnet-sdk_" + v.Major()
+}
+
 func project(srcDir string) (*Project, error) {
 	a, err := analyzer.NewAnalyzer(srcDir)
 	if err != nil {
@@ -90,14 +151,9 @@ func dotNetNixPackage(proj *Project) (string, error) {
 		return "", errors.New("Did not find Dot Net Framework in .csproj")
 	}
 
-	if strings.HasPrefix(proj.PropertyGroup.TargetFramework, "net7") { // for net7.x
-		return "dotnet-sdk_7", nil
-	}
-	if strings.HasPrefix(proj.PropertyGroup.TargetFramework, "net6") { // for net6.x
-		return "dotnet-sdk", nil
-	}
-	if strings.HasPrefix(proj.PropertyGroup.TargetFramework, "net5") { // for net5.x
-		return "dotnet-sdk_5", nil
+	// for net5.0 and later, including OS-specific ones like net8.0-windows
+	if matches := netTargetRegex.FindStringSubmatch(proj.PropertyGroup.TargetFramework); matches != nil {
+		return "dotnet-sdk_" + matches[1], nil
 	}
 	// NOTE: there is in fact NO dot-net_4. Reference: https://docs.microsoft.com/en-us/dotnet/core/whats-new/dotnet-5
 	if strings.HasPrefix(proj.PropertyGroup.TargetFramework, "netcoreapp3") {
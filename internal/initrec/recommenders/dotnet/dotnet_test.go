@@ -0,0 +1,51 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package dotnet
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestPackages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "net8",
+			files: map[string]string{"app.csproj": `<Project><PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup></Project>`},
+			want:  []string{"dotnet-sdk_8"},
+		},
+		{
+			name:  "net6-windows",
+			files: map[string]string{"app.fsproj": `<Project><PropertyGroup><TargetFramework>net6.0-windows</TargetFramework></PropertyGroup></Project>`},
+			want:  []string{"dotnet-sdk_6"},
+		},
+		{
+			name: "global-json",
+			files: map[string]string{
+				"app.csproj":  `<Project><PropertyGroup><TargetFramework>net6.0</TargetFramework></PropertyGroup></Project>`,
+				"global.json": `{"sdk": {"version": "7.0.403", "rollForward": "latestFeature"}}`,
+			},
+			want: []string{"dotnet-sdk_7"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			r := &Recommender{SrcDir: dir}
+			if !r.IsRelevant() {
+				t.Fatal("recommender isn't relevant")
+			}
+			if diff := cmp.Diff(tc.want, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
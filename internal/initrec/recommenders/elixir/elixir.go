@@ -0,0 +1,122 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package elixir
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+)
+
+const mixExs = "mix.exs"
+
+// default nix packages
+const (
+	defaultElixir = "elixir"
+	defaultErlang = "erlang"
+)
+
+// Matches elixir: "~> 1.15" in the project definition of mix.exs.
+var mixElixirRegex = regexp.MustCompile(`elixir:\s*"[~>=\s]*([0-9.]+)`)
+
+type Recommender struct {
+	SrcDir string
+}
+
+// implements interfaces recommenders.EnvRecommender,
+// recommenders.InitHookRecommender and
+// recommenders.ScriptInputsRecommender (compile-time check)
+var (
+	_ recommenders.EnvRecommender          = (*Recommender)(nil)
+	_ recommenders.InitHookRecommender     = (*Recommender)(nil)
+	_ recommenders.ScriptInputsRecommender = (*Recommender)(nil)
+)
+
+func (r *Recommender) IsRelevant() bool {
+	return fileutil.Exists(filepath.Join(r.SrcDir, mixExs))
+}
+
+// Packages returns elixir and erlang. The versions in .tool-versions (used by
+// asdf) win over the elixir requirement in mix.exs. An elixir version built
+// for a specific erlang, such as "1.15.7-otp-26", selects that erlang unless
+// .tool-versions also sets one.
+func (r *Recommender) Packages() []string {
+	tools := analyzer.ToolVersions(r.SrcDir)
+
+	elixirPkg := defaultElixir
+	elixirVersion, suffix, _ := strings.Cut(tools["elixir"], "-")
+	if elixirVersion == "" {
+		elixirVersion = r.mixElixirVersion()
+	}
+	if v, err := analyzer.NewVersion(elixirVersion); err == nil {
+		elixirPkg += "@" + v.MajorMinor()
+	}
+
+	erlangVersion := tools["erlang"]
+	if otp, ok := strings.CutPrefix(suffix, "otp-"); ok && erlangVersion == "" {
+		erlangVersion = otp
+	}
+	erlangPkg := defaultErlang
+	if v, err := analyzer.NewVersion(erlangVersion); err == nil {
+		erlangPkg += "@" + v.Major()
+	}
+	return []string{elixirPkg, erlangPkg}
+}
+
+// Env keeps mix archives, such as hex and rebar, and the hex package cache in
+// .devbox instead of the user's home directory.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"MIX_HOME": "{{.DevboxDir}}/mix",
+		"HEX_HOME": "{{.DevboxDir}}/hex",
+	}
+}
+
+// InitHook installs hex and rebar into the project's MIX_HOME, which mix
+// needs to fetch and build dependencies.
+func (r *Recommender) InitHook() []string {
+	return []string{
+		"mix local.hex --force --if-missing > /dev/null",
+		"mix local.rebar --force --if-missing > /dev/null",
+	}
+}
+
+// Scripts fetches dependencies and builds the project. Phoenix projects are
+// started with the phoenix server.
+func (r *Recommender) Scripts() map[string]string {
+	start := "mix run --no-halt"
+	if content, err := os.ReadFile(filepath.Join(r.SrcDir, mixExs)); err == nil &&
+		bytes.Contains(content, []byte("{:phoenix,")) {
+		start = "mix phx.server"
+	}
+	return map[string]string{
+		"install": "mix deps.get",
+		"build":   "mix compile",
+		"test":    "mix test",
+		"start":   start,
+	}
+}
+
+func (r *Recommender) ScriptInputs() map[string][]string {
+	return map[string][]string{
+		"install": recommenders.ExistingFiles(r.SrcDir, mixExs, "mix.lock"),
+	}
+}
+
+func (r *Recommender) mixElixirVersion() string {
+	content, err := os.ReadFile(filepath.Join(r.SrcDir, mixExs))
+	if err != nil {
+		return ""
+	}
+	if matches := mixElixirRegex.FindSubmatch(content); matches != nil {
+		return string(matches[1])
+	}
+	return ""
+}
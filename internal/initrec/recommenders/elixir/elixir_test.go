@@ -0,0 +1,64 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package elixir
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestPackages(t *testing.T) {
+	const mix = `defmodule App.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :app, version: "0.1.0", elixir: "~> 1.14"]
+  end
+end
+`
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "mix",
+			files: map[string]string{"mix.exs": mix},
+			want:  []string{"elixir@1.14", "erlang"},
+		},
+		{
+			name: "tool-versions",
+			files: map[string]string{
+				"mix.exs":        mix,
+				".tool-versions": "# pinned by asdf\nelixir 1.15.7-otp-26\nerlang 26.1.2\n",
+			},
+			want: []string{"elixir@1.15", "erlang@26"},
+		},
+		{
+			name: "tool-versions-otp-suffix",
+			files: map[string]string{
+				"mix.exs":        mix,
+				".tool-versions": "elixir 1.16.1-otp-25\n",
+			},
+			want: []string{"elixir@1.16", "erlang@25"},
+		},
+		{
+			name:  "no-version",
+			files: map[string]string{"mix.exs": "defmodule App.MixProject do\nend\n"},
+			want:  []string{"elixir", "erlang"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			r := &Recommender{SrcDir: dir}
+			if diff := cmp.Diff(tc.want, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+// Package external runs recommenders that users install as executables, so
+// that they can detect in-house frameworks without changing devbox.
+//
+// Devbox runs every executable in Dir with the project directory as its only
+// argument (and working directory). An executable that doesn't recognize the
+// project prints nothing. Otherwise, it prints a JSON object:
+//
+//	{
+//	  "name": "Acme service",
+//	  "packages": ["go@1.21", "protobuf"],
+//	  "env": {"ACME_HOME": "{{.DevboxDir}}/acme"},
+//	  "init_hook": ["acme login --check"],
+//	  "scripts": {"install": "acme deps", "start": "acme serve"},
+//	  "script_inputs": {"install": ["acme.yaml"]}
+//	}
+//
+// Every field is optional. name defaults to the executable's file name.
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+	"go.jetpack.io/devbox/internal/ux"
+	"go.jetpack.io/devbox/internal/xdg"
+)
+
+// timeout is how long a recommender can run before devbox gives up on it.
+const timeout = 30 * time.Second
+
+// Dir returns the directory that external recommenders are installed in.
+func Dir() string {
+	return xdg.ConfigSubpath(filepath.Join("devbox", "recommenders"))
+}
+
+type Recommender struct {
+	Path   string
+	SrcDir string
+
+	rec *recommendation
+}
+
+// recommendation is what an external recommender prints.
+type recommendation struct {
+	Name         string              `json:"name"`
+	Packages     []string            `json:"packages"`
+	Env          map[string]string   `json:"env"`
+	InitHook     []string            `json:"init_hook"`
+	Scripts      map[string]string   `json:"scripts"`
+	ScriptInputs map[string][]string `json:"script_inputs"`
+}
+
+// implements interfaces recommenders.EnvRecommender,
+// recommenders.InitHookRecommender and
+// recommenders.ScriptInputsRecommender (compile-time check)
+var (
+	_ recommenders.EnvRecommender          = (*Recommender)(nil)
+	_ recommenders.InitHookRecommender     = (*Recommender)(nil)
+	_ recommenders.ScriptInputsRecommender = (*Recommender)(nil)
+)
+
+// Load runs the external recommenders in Dir, in file name order, for the
+// project in srcDir. Hidden files and files that aren't executable are
+// skipped, and so are recommenders that fail or print invalid JSON, with a
+// warning, so that one broken recommender doesn't break devbox init.
+func Load(srcDir string) ([]*Recommender, error) {
+	entries, err := os.ReadDir(Dir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var result []*Recommender
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if info.Mode()&0o111 == 0 {
+			continue
+		}
+		r := &Recommender{Path: filepath.Join(Dir(), entry.Name()), SrcDir: srcDir}
+		if err := r.run(); err != nil {
+			ux.Fwarning(os.Stderr, "Skipping recommender %s: %v\n", r.Path, err)
+			continue
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (r *Recommender) run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.Path, r.SrcDir)
+	cmd.Dir = r.SrcDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if out := strings.TrimSpace(stderr.String()); out != "" {
+			return errors.Errorf("%v:\n%s", err, out)
+		}
+		return errors.WithStack(err)
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	rec := &recommendation{}
+	if err := json.Unmarshal(stdout.Bytes(), rec); err != nil {
+		return errors.Wrap(err, "invalid JSON")
+	}
+	r.rec = rec
+	return nil
+}
+
+// Name is the name of the kind of project that the recommender detected.
+func (r *Recommender) Name() string {
+	if r.rec != nil && r.rec.Name != "" {
+		return r.rec.Name
+	}
+	return filepath.Base(r.Path)
+}
+
+func (r *Recommender) IsRelevant() bool {
+	return r.rec != nil
+}
+
+func (r *Recommender) Packages() []string {
+	return r.rec.Packages
+}
+
+func (r *Recommender) Env() map[string]string {
+	return r.rec.Env
+}
+
+func (r *Recommender) InitHook() []string {
+	return r.rec.InitHook
+}
+
+func (r *Recommender) Scripts() map[string]string {
+	return r.rec.Scripts
+}
+
+func (r *Recommender) ScriptInputs() map[string][]string {
+	return r.rec.ScriptInputs
+}
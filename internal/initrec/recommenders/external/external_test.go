@@ -0,0 +1,90 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.jetpack.io/devbox/internal/envir"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv(envir.XDGConfigHome, t.TempDir())
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	scripts := map[string]string{
+		// Only recognizes projects with an acme.yaml.
+		"acme": `#!/bin/sh
+[ -f "$1/acme.yaml" ] || exit 0
+echo '{"name": "Acme", "packages": ["protobuf"], "scripts": {"install": "acme deps"}, "script_inputs": {"install": ["acme.yaml"]}}'
+`,
+		"other": "#!/bin/sh\n",
+	}
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(Dir(), name), []byte(content), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(Dir(), "README"), []byte("not executable"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "acme.yaml"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := Load(srcDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("got %d recommenders, want 2", len(recs))
+	}
+	acme, other := recs[0], recs[1]
+	if !acme.IsRelevant() || acme.Name() != "Acme" {
+		t.Errorf("got relevant=%v name=%q, want the Acme recommender to be relevant", acme.IsRelevant(), acme.Name())
+	}
+	if diff := cmp.Diff([]string{"protobuf"}, acme.Packages()); diff != "" {
+		t.Errorf("wrong packages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string][]string{"install": {"acme.yaml"}}, acme.ScriptInputs()); diff != "" {
+		t.Errorf("wrong script inputs (-want +got):\n%s", diff)
+	}
+	if other.IsRelevant() {
+		t.Error("got relevant recommender for a recommender that printed nothing")
+	}
+}
+
+func TestLoadSkipsBroken(t *testing.T) {
+	testCases := map[string]string{
+		"exit status":  "#!/bin/sh\necho oops >&2\nexit 1\n",
+		"invalid json": "#!/bin/sh\necho '{'\n",
+	}
+	for name, script := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(envir.XDGConfigHome, t.TempDir())
+			if err := os.MkdirAll(Dir(), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(Dir(), "broken"), []byte(script), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(Dir(), "working"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			recs, err := Load(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(recs) != 1 || recs[0].Name() != "working" {
+				t.Errorf("got %d recommenders, want only the working one", len(recs))
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package recommenders
+
+import (
+	"path/filepath"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+// ExistingFiles returns the names of the files in dir that exist, in the order
+// they're given.
+func ExistingFiles(dir string, names ...string) []string {
+	var existing []string
+	for _, name := range names {
+		if fileutil.Exists(filepath.Join(dir, name)) {
+			existing = append(existing, name)
+		}
+	}
+	return existing
+}
@@ -6,28 +6,29 @@ package golang
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/mod/modfile"
 
 	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
 
-var versionMap = map[string]string{
-	// Map go versions to the corresponding nixpkgs:
-	"1.19": "go_1_19",
-	"1.18": "go",
-	"1.17": "go_1_17",
-}
-
-const defaultPkg = "go_1_19" // Default to "latest" for cases where we can't determine a version.
+const defaultPkg = "go" // Default to "latest" for cases where we can't determine a version.
 
 type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interfaces recommenders.EnvRecommender,
+// recommenders.ScriptInputsRecommender and recommenders.VersionRecommender
+// (compile-time check)
+var (
+	_ recommenders.EnvRecommender          = (*Recommender)(nil)
+	_ recommenders.ScriptInputsRecommender = (*Recommender)(nil)
+	_ recommenders.VersionRecommender      = (*Recommender)(nil)
+)
 
 func (r *Recommender) IsRelevant() bool {
 	return fileutil.Exists(filepath.Join(r.SrcDir, "go.mod"))
@@ -39,29 +40,96 @@ func (r *Recommender) Packages() []string {
 	return []string{goPkg}
 }
 
+// Env keeps the project's GOPATH (and the tools installed with `go install`)
+// in .devbox instead of the user's home directory.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"GOPATH": "{{.DevboxDir}}/go",
+		"GOBIN":  "{{.DevboxDir}}/go/bin",
+		"PATH":   "{{.DevboxDir}}/go/bin:$PATH",
+	}
+}
+
+func (r *Recommender) Scripts() map[string]string {
+	return map[string]string{
+		"install": "go mod download",
+		"build":   "go build ./...",
+		"test":    "go test ./...",
+	}
+}
+
+func (r *Recommender) ScriptInputs() map[string][]string {
+	return map[string][]string{
+		"install": recommenders.ExistingFiles(r.SrcDir, "go.mod", "go.sum"),
+	}
+}
+
+// RequestedVersions returns the exact go version that the project pins in
+// .tool-versions or in the toolchain directive of go.mod. The go directive
+// isn't included because it's only the minimum version the module needs.
+func (r *Recommender) RequestedVersions() []recommenders.RequestedVersion {
+	version, file := goVersionFile(r.SrcDir)
+	if version == "" {
+		return nil
+	}
+	return []recommenders.RequestedVersion{{Package: defaultPkg, Version: version, File: file}}
+}
+
+// getGoPackage returns the go package for the version in .tool-versions, or
+// in the toolchain or go directive of go.mod. For example, "go 1.21.3"
+// becomes go@1.21, which resolves to the latest 1.21 patch release.
 func getGoPackage(srcDir string) string {
-	goModPath := filepath.Join(srcDir, "go.mod")
-	goVersion := parseGoVersion(goModPath)
-	v, ok := versionMap[goVersion]
-	if ok {
-		return v
+	version, _ := goVersionFile(srcDir)
+	if version == "" {
+		version = parseGoMod(filepath.Join(srcDir, "go.mod")).goVersion
+	}
+	v, err := analyzer.NewVersion(version)
+	if err != nil {
+		return defaultPkg
+	}
+	return defaultPkg + "@" + v.MajorMinor()
+}
+
+// goVersionFile returns the version in .tool-versions or in the toolchain
+// directive of go.mod, and the name of the file it's in.
+func goVersionFile(srcDir string) (version, file string) {
+	version = analyzer.ToolVersions(srcDir)["golang"]
+	if _, err := analyzer.NewVersion(version); err == nil {
+		return version, analyzer.ToolVersionsFile
+	}
+	version = parseGoMod(filepath.Join(srcDir, "go.mod")).toolchain
+	if _, err := analyzer.NewVersion(version); err == nil {
+		return version, "go.mod"
 	}
-	// Should we be throwing an error instead, if we don't have a nix package
-	// for the specified version of go?
-	return defaultPkg
+	return "", ""
 }
 
-func parseGoVersion(gomodPath string) string {
+type goMod struct {
+	goVersion string
+	// toolchain is the version in the toolchain directive, without the
+	// "go" prefix.
+	toolchain string
+}
+
+func parseGoMod(gomodPath string) goMod {
 	content, err := os.ReadFile(gomodPath)
 	if err != nil {
-		return ""
+		return goMod{}
 	}
-	parsed, err := modfile.ParseLax(gomodPath, content, nil)
+	// ParseLax skips the toolchain directive, so only fall back to it when
+	// go.mod has something the strict parser rejects.
+	parsed, err := modfile.Parse(gomodPath, content, nil)
 	if err != nil {
-		return ""
+		if parsed, err = modfile.ParseLax(gomodPath, content, nil); err != nil {
+			return goMod{}
+		}
+	}
+	var mod goMod
+	if parsed.Go != nil {
+		mod.goVersion = parsed.Go.Version
 	}
-	if parsed.Go == nil {
-		return ""
+	if parsed.Toolchain != nil {
+		mod.toolchain = strings.TrimPrefix(parsed.Toolchain.Name, "go")
 	}
-	return parsed.Go.Version
+	return mod
 }
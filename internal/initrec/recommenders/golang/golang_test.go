@@ -0,0 +1,61 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+)
+
+func TestGetGoPackage(t *testing.T) {
+	testCases := []struct {
+		gomod string
+		want  string
+	}{
+		{gomod: "module example.com/foo\n\ngo 1.21\n", want: "go@1.21"},
+		{gomod: "module example.com/foo\n\ngo 1.22.1\n\ntoolchain go1.22.3\n", want: "go@1.22"},
+		{gomod: "module example.com/foo\n", want: "go"},
+		{gomod: "not a go.mod", want: "go"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(tc.gomod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := getGoPackage(dir); got != tc.want {
+				t.Errorf("got package %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequestedVersions(t *testing.T) {
+	dir := t.TempDir()
+	gomod := "module example.com/foo\n\ngo 1.22.1\n\ntoolchain go1.22.3\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &Recommender{SrcDir: dir}
+	want := []recommenders.RequestedVersion{{Package: "go", Version: "1.22.3", File: "go.mod"}}
+	if diff := cmp.Diff(want, r.RequestedVersions()); diff != "" {
+		t.Errorf("wrong requested versions (-want +got):\n%s", diff)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".tool-versions"), []byte("golang 1.21.5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want = []recommenders.RequestedVersion{{Package: "go", Version: "1.21.5", File: ".tool-versions"}}
+	if diff := cmp.Diff(want, r.RequestedVersions()); diff != "" {
+		t.Errorf("wrong requested versions (-want +got):\n%s", diff)
+	}
+	if got := getGoPackage(dir); got != "go@1.21" {
+		t.Errorf("got package %q, want %q", got, "go@1.21")
+	}
+}
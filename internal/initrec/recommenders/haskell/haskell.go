@@ -4,6 +4,13 @@
 package haskell
 
 import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/fileutil"
 	"go.jetpack.io/devbox/internal/initrec/analyzer"
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
@@ -17,14 +24,42 @@ import (
 const (
 	packageYaml = "package.yaml"
 	stackYaml   = "stack.yaml"
+	cabalGlob   = "*.cabal"
+)
+
+// defaultGHC is the GHC of the haskellPackages set in nixpkgs. The haskell
+// plugin turns it, or one of the haskell.compiler.ghcXY packages, into a GHC
+// with the project's haskell packages.
+const defaultGHC = "ghc"
+
+// ltsGHCVersions maps Stackage LTS major versions to their GHC version.
+var ltsGHCVersions = map[string]string{
+	"18": "8.10",
+	"19": "9.0",
+	"20": "9.2",
+	"21": "9.4",
+	"22": "9.6",
+	"23": "9.8",
+	"24": "9.10",
+}
+
+var (
+	// Matches "resolver: lts-21.13" and "snapshot: ghc-9.4.7" in stack.yaml.
+	stackResolverRegex = regexp.MustCompile(`^(?:resolver|snapshot):\s*(lts|ghc)-([0-9.]+)`)
+	// Matches "tested-with: GHC == 9.4.7" in a .cabal file.
+	cabalTestedWithRegex = regexp.MustCompile(`(?i)^tested-with:\s*GHC\s*==\s*([0-9.]+)`)
 )
 
 type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interfaces recommenders.EnvRecommender and
+// recommenders.ScriptRecommender (compile-time check)
+var (
+	_ recommenders.EnvRecommender    = (*Recommender)(nil)
+	_ recommenders.ScriptRecommender = (*Recommender)(nil)
+)
 
 func (r *Recommender) IsRelevant() bool {
 	a, err := analyzer.NewAnalyzer(r.SrcDir)
@@ -33,9 +68,96 @@ func (r *Recommender) IsRelevant() bool {
 		return false
 	}
 
-	return a.HasAnyFile(stackYaml)
+	return a.HasAnyFile(stackYaml, cabalGlob)
 }
 
 func (r *Recommender) Packages() []string {
-	return []string{"stack", "libiconv", "libffi", "binutils", "ghc"}
+	ghc := ghcPackage(r.ghcVersion())
+	if r.isStackProject() {
+		return []string{"stack", "libiconv", "libffi", "binutils", ghc}
+	}
+	return []string{"cabal-install", ghc}
+}
+
+// Env keeps stack's and cabal's package stores in .devbox instead of the
+// user's home directory.
+func (r *Recommender) Env() map[string]string {
+	if r.isStackProject() {
+		return map[string]string{"STACK_ROOT": "{{.DevboxDir}}/stack"}
+	}
+	return map[string]string{"CABAL_DIR": "{{.DevboxDir}}/cabal"}
+}
+
+// Scripts builds and runs the project with the GHC from devbox. stack is told
+// not to download its own GHC.
+func (r *Recommender) Scripts() map[string]string {
+	if r.isStackProject() {
+		const stack = "stack --system-ghc --no-install-ghc"
+		return map[string]string{
+			"build": stack + " build",
+			"test":  stack + " test",
+			"start": stack + " run",
+		}
+	}
+	return map[string]string{
+		"build": "cabal build",
+		"test":  "cabal test",
+		"start": "cabal run",
+	}
+}
+
+func (r *Recommender) isStackProject() bool {
+	return fileutil.Exists(filepath.Join(r.SrcDir, stackYaml))
+}
+
+// ghcVersion returns the GHC version of the stack resolver or, for cabal
+// projects, the tested-with field of the .cabal file.
+func (r *Recommender) ghcVersion() *analyzer.Version {
+	if matches := matchFileLine(filepath.Join(r.SrcDir, stackYaml), stackResolverRegex); matches != nil {
+		version := matches[2]
+		if matches[1] == "lts" {
+			lts, _, _ := strings.Cut(version, ".")
+			version = ltsGHCVersions[lts]
+		}
+		v, _ := analyzer.NewVersion(version)
+		return v
+	}
+
+	a, err := analyzer.NewAnalyzer(r.SrcDir)
+	if err != nil {
+		return nil
+	}
+	for _, path := range a.GlobFiles(cabalGlob) {
+		if matches := matchFileLine(path, cabalTestedWithRegex); matches != nil {
+			v, _ := analyzer.NewVersion(matches[1])
+			return v
+		}
+	}
+	return nil
+}
+
+// ghcPackage returns the haskell.compiler package for a GHC version, for
+// example haskell.compiler.ghc94 for 9.4.7.
+func ghcPackage(v *analyzer.Version) string {
+	if v == nil {
+		return defaultGHC
+	}
+	return "haskell.compiler.ghc" + v.MajorMinorConcatenated()
+}
+
+// matchFileLine returns the submatches of the first line in a file that
+// matches re.
+func matchFileLine(path string, re *regexp.Regexp) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if matches := re.FindStringSubmatch(strings.TrimSpace(s.Text())); matches != nil {
+			return matches
+		}
+	}
+	return nil
 }
@@ -0,0 +1,58 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package haskell
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestPackages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "stack-lts",
+			files: map[string]string{"stack.yaml": "resolver: lts-21.13\npackages:\n- .\n"},
+			want:  []string{"stack", "libiconv", "libffi", "binutils", "haskell.compiler.ghc94"},
+		},
+		{
+			name:  "stack-ghc-snapshot",
+			files: map[string]string{"stack.yaml": "snapshot: ghc-9.6.3\n"},
+			want:  []string{"stack", "libiconv", "libffi", "binutils", "haskell.compiler.ghc96"},
+		},
+		{
+			name:  "stack-nightly",
+			files: map[string]string{"stack.yaml": "resolver: nightly-2023-10-30\n"},
+			want:  []string{"stack", "libiconv", "libffi", "binutils", "ghc"},
+		},
+		{
+			name:  "cabal",
+			files: map[string]string{"app.cabal": "name: app\ntested-with: GHC == 8.10.7\n"},
+			want:  []string{"cabal-install", "haskell.compiler.ghc810"},
+		},
+		{
+			name:  "cabal-no-version",
+			files: map[string]string{"app.cabal": "name: app\n"},
+			want:  []string{"cabal-install", "ghc"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			r := &Recommender{SrcDir: dir}
+			if !r.IsRelevant() {
+				t.Fatal("recommender isn't relevant")
+			}
+			if diff := cmp.Diff(tc.want, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
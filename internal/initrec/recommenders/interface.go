@@ -7,3 +7,53 @@ type Recommender interface {
 	IsRelevant() bool
 	Packages() []string
 }
+
+// EnvRecommender is implemented by recommenders that also recommend env
+// variables for the project. Values can use the same $PWD, $PATH and
+// {{.DevboxDir}} style references as the env field in devbox.json.
+type EnvRecommender interface {
+	Recommender
+	Env() map[string]string
+}
+
+// InitHookRecommender is implemented by recommenders that also recommend
+// commands for the project's init hook.
+type InitHookRecommender interface {
+	Recommender
+	InitHook() []string
+}
+
+// ScriptRecommender is implemented by recommenders that also recommend
+// scripts, such as the commands that install the project's dependencies or
+// build it.
+type ScriptRecommender interface {
+	Recommender
+	Scripts() map[string]string
+}
+
+// ScriptInputsRecommender is implemented by recommenders whose scripts only
+// need some of the project's files, such as an install script that only reads
+// the lockfile. Generated Dockerfiles copy just those files before running the
+// script so that its layer stays cached until they change.
+type ScriptInputsRecommender interface {
+	ScriptRecommender
+	ScriptInputs() map[string][]string
+}
+
+// VersionRecommender is implemented by recommenders that read the exact
+// version of a package from a version file, such as .nvmrc. Devbox recommends
+// that version if it's available, and otherwise keeps the version in Packages
+// and warns that it's an approximation.
+type VersionRecommender interface {
+	Recommender
+	RequestedVersions() []RequestedVersion
+}
+
+// RequestedVersion is a version of a package that a project asks for.
+type RequestedVersion struct {
+	// Package is the name of the package in Packages, such as "nodejs".
+	Package string
+	Version string
+	// File is the file that asks for the version, such as ".nvmrc".
+	File string
+}
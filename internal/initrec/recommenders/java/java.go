@@ -5,8 +5,10 @@ package java
 
 import (
 	"bufio"
+	"bytes"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/creekorful/mvnparser"
@@ -23,38 +25,51 @@ const binUtils = "binutils"
 
 // builder tool specific names
 const (
-	MavenType      = "maven"
-	GradleType     = "gradle"
-	mavenFileName  = "pom.xml"
-	gradleFileName = "build.gradle"
+	MavenType         = "maven"
+	GradleType        = "gradle"
+	mavenFileName     = "pom.xml"
+	gradleFileName    = "build.gradle"
+	gradleKtsFileName = "build.gradle.kts"
+	gradleWrapperName = "gradlew"
+	sdkmanrcFileName  = ".sdkmanrc"
 )
 
-// jdk nix packages
-var jVersionMap = map[string]string{
-	"8":  "jdk8",
-	"11": "jdk11",
-	"17": "jdk17_headless",
-	"18": "jdk18_headless",
-}
-
 // default nix packages
 const (
-	defaultJava   = "jdk" // "jdk" points to openJDK version 17
+	defaultJava   = "jdk" // "jdk" points to the latest openJDK
 	defaultMaven  = "maven"
 	defaultGradle = "gradle"
 )
 
+// setJavaHome points JAVA_HOME at the JDK of the java on the PATH, which is
+// the project's JDK inside the devbox shell.
+const setJavaHome = `export JAVA_HOME="$(dirname "$(dirname "$(readlink -f "$(command -v java)")")")"`
+
+var (
+	// Matches java=17.0.8-tem in .sdkmanrc.
+	sdkmanJavaRegex = regexp.MustCompile(`^\s*java\s*=\s*([0-9.]+)`)
+	// Matches languageVersion = JavaLanguageVersion.of(17) and
+	// languageVersion.set(JavaLanguageVersion.of(17)) in a gradle toolchain.
+	gradleToolchainRegex = regexp.MustCompile(`JavaLanguageVersion\.of\(\s*"?([0-9]+)"?\s*\)`)
+	// Matches sourceCompatibility = 17, = '1.8' and = JavaVersion.VERSION_17.
+	gradleSourceCompatibilityRegex = regexp.MustCompile(
+		`sourceCompatibility\s*=\s*(?:JavaVersion\.VERSION_)?['"]?([0-9._]+)`)
+)
+
 type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interfaces recommenders.InitHookRecommender and
+// recommenders.ScriptRecommender (compile-time check)
+var (
+	_ recommenders.InitHookRecommender = (*Recommender)(nil)
+	_ recommenders.ScriptRecommender   = (*Recommender)(nil)
+)
 
 func (r *Recommender) IsRelevant() bool {
-	pomXMLPath := filepath.Join(r.SrcDir, mavenFileName)
-	buildGradlePath := filepath.Join(r.SrcDir, gradleFileName)
-	return fileutil.Exists(pomXMLPath) || fileutil.Exists(buildGradlePath)
+	_, err := r.packageManager()
+	return err == nil
 }
 
 func (r *Recommender) Packages() []string {
@@ -62,28 +77,67 @@ func (r *Recommender) Packages() []string {
 	if err != nil {
 		return nil
 	}
-	devPackages, _ := r.devPackages(builderTool)
-	// if err is not nil, devPackages will be nil
-	return devPackages
+	return r.devPackages(builderTool)
+}
+
+func (r *Recommender) InitHook() []string {
+	return []string{setJavaHome}
+}
+
+// Scripts builds and tests the project with its build tool. Gradle projects
+// use the gradle wrapper when they have one, and get a start script when they
+// use the application plugin.
+func (r *Recommender) Scripts() map[string]string {
+	builderTool, err := r.packageManager()
+	if err != nil {
+		return nil
+	}
+	if builderTool == MavenType {
+		return map[string]string{
+			"build": "mvn package",
+			"test":  "mvn test",
+		}
+	}
+
+	gradle := "gradle"
+	if fileutil.Exists(filepath.Join(r.SrcDir, gradleWrapperName)) {
+		gradle = "./" + gradleWrapperName
+	}
+	scripts := map[string]string{
+		"build": gradle + " build",
+		"test":  gradle + " test",
+	}
+	if content, err := os.ReadFile(r.gradleFilePath()); err == nil &&
+		bytes.Contains(content, []byte("application")) {
+		scripts["start"] = gradle + " run"
+	}
+	return scripts
 }
 
 func (r *Recommender) packageManager() (string, error) {
 	pomXMLPath := filepath.Join(r.SrcDir, mavenFileName)
-	buildGradlePath := filepath.Join(r.SrcDir, gradleFileName)
 	if fileutil.Exists(pomXMLPath) {
 		return MavenType, nil
 	}
-	if fileutil.Exists(buildGradlePath) {
+	if r.gradleFilePath() != "" {
 		return GradleType, nil
 	}
 	return "", errors.New("could not locate a Maven or Gradle file")
 }
 
-func (r *Recommender) devPackages(builderTool string) ([]string, error) {
-	javaPkg, err := getJavaPackage(r.SrcDir, builderTool)
-	if err != nil {
-		return nil, errors.WithStack(err)
+// gradleFilePath returns the path of build.gradle or build.gradle.kts, or an
+// empty string if the project has neither.
+func (r *Recommender) gradleFilePath() string {
+	for _, name := range []string{gradleFileName, gradleKtsFileName} {
+		if path := filepath.Join(r.SrcDir, name); fileutil.Exists(path) {
+			return path
+		}
 	}
+	return ""
+}
+
+func (r *Recommender) devPackages(builderTool string) []string {
+	javaPkg := r.javaPackage(builderTool)
 
 	devPackagesMap := map[string][]string{
 		MavenType: {
@@ -98,61 +152,98 @@ func (r *Recommender) devPackages(builderTool string) ([]string, error) {
 		},
 	}
 
-	return devPackagesMap[builderTool], nil
+	return devPackagesMap[builderTool]
 }
 
-func getJavaPackage(srcDir, builderTool string) (string, error) {
-	javaVersion, err := parseJavaVersion(srcDir, builderTool)
-	if err != nil {
-		return "", errors.WithStack(err)
-	}
-	v, ok := jVersionMap[javaVersion.Major()]
-	if ok {
-		return v, nil
+// javaPackage returns the JDK package for the project's java version, for
+// example jdk@17.
+func (r *Recommender) javaPackage(builderTool string) string {
+	if v := r.javaVersion(builderTool); v != nil && v.Major() != "0" {
+		return defaultJava + "@" + v.Major()
 	}
-	return defaultJava, nil
+	return defaultJava
 }
 
-func parseJavaVersion(srcDir, builderTool string) (*analyzer.Version, error) {
-	sourceVersion, _ := analyzer.NewVersion("0")
+// javaVersion returns the java version in .sdkmanrc or, if the project
+// doesn't use SDKMAN!, the version in its build file.
+func (r *Recommender) javaVersion(builderTool string) *analyzer.Version {
+	if v := parseSdkmanrc(filepath.Join(r.SrcDir, sdkmanrcFileName)); v != nil {
+		return v
+	}
+	var v *analyzer.Version
+	switch builderTool {
+	case MavenType:
+		v, _ = parseMavenJavaVersion(filepath.Join(r.SrcDir, mavenFileName))
+	case GradleType:
+		v, _ = parseGradleJavaVersion(r.gradleFilePath())
+	}
+	return v
+}
 
-	if builderTool == MavenType {
-		pomXMLPath := filepath.Join(srcDir, mavenFileName)
-		var parsedPom mvnparser.MavenProject
-		// parsing pom.xml and putting its content in 'project'
-		err := cuecfg.ParseFile(pomXMLPath, &parsedPom)
-		if err != nil {
-			return nil, errors.WithMessage(err, "error parsing java version from pom file")
+func parseSdkmanrc(path string) *analyzer.Version {
+	readFile, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer readFile.Close()
+	fileScanner := bufio.NewScanner(readFile)
+	for fileScanner.Scan() {
+		matches := sdkmanJavaRegex.FindStringSubmatch(fileScanner.Text())
+		if matches == nil {
+			continue
 		}
-		compilerSourceVersion, ok := parsedPom.Properties["maven.compiler.source"]
-		if ok {
-			sourceVersion, err = analyzer.NewVersion(compilerSourceVersion)
+		if v, err := newJavaVersion(matches[1]); err == nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func parseMavenJavaVersion(pomXMLPath string) (*analyzer.Version, error) {
+	var parsedPom mvnparser.MavenProject
+	// parsing pom.xml and putting its content in 'project'
+	err := cuecfg.ParseFile(pomXMLPath, &parsedPom)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error parsing java version from pom file")
+	}
+	// maven.compiler.release replaces source and target since Java 9.
+	for _, property := range []string{"maven.compiler.release", "maven.compiler.source"} {
+		if compilerVersion, ok := parsedPom.Properties[property]; ok {
+			v, err := newJavaVersion(compilerVersion)
 			if err != nil {
 				return nil, errors.WithMessage(err, "error parsing java version from pom file")
 			}
+			return v, nil
 		}
-	} else if builderTool == GradleType {
-		buildGradlePath := filepath.Join(srcDir, gradleFileName)
-		readFile, err := os.Open(buildGradlePath)
-		if err != nil {
-			return nil, errors.WithMessage(err, "error parsing java version from gradle file")
-		}
-		fileScanner := bufio.NewScanner(readFile)
-		fileScanner.Split(bufio.ScanLines)
-		// parsing gradle file line by line
-		for fileScanner.Scan() {
-			line := fileScanner.Text()
-			if strings.Contains(line, "sourceCompatibility = ") {
-				compilerSourceVersion := strings.TrimSpace(strings.Split(line, "=")[1])
-				sourceVersion, err = analyzer.NewVersion(compilerSourceVersion)
-				if err != nil {
-					return nil, errors.WithMessage(err, "error parsing java version from gradle file")
-				}
-				break
+	}
+	return nil, nil
+}
+
+// parseGradleJavaVersion returns the version of the project's java toolchain
+// or, if it doesn't configure one, its sourceCompatibility.
+func parseGradleJavaVersion(buildGradlePath string) (*analyzer.Version, error) {
+	content, err := os.ReadFile(buildGradlePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error parsing java version from gradle file")
+	}
+	for _, re := range []*regexp.Regexp{gradleToolchainRegex, gradleSourceCompatibilityRegex} {
+		if matches := re.FindSubmatch(content); matches != nil {
+			v, err := newJavaVersion(string(matches[1]))
+			if err != nil {
+				return nil, errors.WithMessage(err, "error parsing java version from gradle file")
 			}
+			return v, nil
 		}
-		readFile.Close()
 	}
+	return nil, nil
+}
 
-	return sourceVersion, nil
+// newJavaVersion parses a java version, including the legacy "1.8" and
+// "1_8" names of Java 8 and older.
+func newJavaVersion(version string) (*analyzer.Version, error) {
+	version = strings.ReplaceAll(strings.TrimSpace(version), "_", ".")
+	if legacy, ok := strings.CutPrefix(version, "1."); ok {
+		version = legacy
+	}
+	return analyzer.NewVersion(version)
 }
@@ -0,0 +1,71 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package java
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestPackages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "maven-release",
+			files: map[string]string{
+				"pom.xml": `<project><properties><maven.compiler.release>21</maven.compiler.release></properties></project>`,
+			},
+			want: []string{"maven", "jdk@21", "binutils"},
+		},
+		{
+			name: "maven-legacy-source",
+			files: map[string]string{
+				"pom.xml": `<project><properties><maven.compiler.source>1.8</maven.compiler.source></properties></project>`,
+			},
+			want: []string{"maven", "jdk@8", "binutils"},
+		},
+		{
+			name: "gradle-toolchain",
+			files: map[string]string{
+				"build.gradle.kts": "java {\n  toolchain {\n    languageVersion.set(JavaLanguageVersion.of(17))\n  }\n}\n",
+			},
+			want: []string{"gradle", "jdk@17", "binutils"},
+		},
+		{
+			name: "gradle-source-compatibility",
+			files: map[string]string{
+				"build.gradle": "sourceCompatibility = JavaVersion.VERSION_11\n",
+			},
+			want: []string{"gradle", "jdk@11", "binutils"},
+		},
+		{
+			name: "sdkmanrc",
+			files: map[string]string{
+				"build.gradle": "sourceCompatibility = '11'\n",
+				".sdkmanrc":    "# Enable auto-env through the sdkman_auto_env config\njava=17.0.8-tem\n",
+			},
+			want: []string{"gradle", "jdk@17", "binutils"},
+		},
+		{
+			name:  "no-version",
+			files: map[string]string{"pom.xml": `<project></project>`},
+			want:  []string{"maven", "jdk", "binutils"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			r := &Recommender{SrcDir: dir}
+			if diff := cmp.Diff(tc.want, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@
 package javascript
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 
 	"go.jetpack.io/devbox/internal/cuecfg"
 	"go.jetpack.io/devbox/internal/fileutil"
@@ -16,21 +18,59 @@ type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interfaces recommenders.EnvRecommender,
+// recommenders.ScriptInputsRecommender and recommenders.VersionRecommender
+// (compile-time check)
+var (
+	_ recommenders.EnvRecommender          = (*Recommender)(nil)
+	_ recommenders.ScriptInputsRecommender = (*Recommender)(nil)
+	_ recommenders.VersionRecommender      = (*Recommender)(nil)
+)
 
 func (r *Recommender) IsRelevant() bool {
 	return fileutil.Exists(filepath.Join(r.SrcDir, "package.json"))
 }
 
 func (r *Recommender) Packages() []string {
-	pkgManager := r.packageManager()
 	project := r.nodeProject()
+	pkgManager := r.packageManager(project)
 	packages := r.packages(pkgManager, project)
 
 	return packages
 }
 
+// Env puts the binaries of the project's node modules on the PATH, so tools
+// like tsc and eslint can be run without npx.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"PATH": "{{.ProjectDir}}/node_modules/.bin:$PATH",
+	}
+}
+
+// Scripts installs the project's dependencies and runs the build and start
+// scripts of package.json, if it has them.
+func (r *Recommender) Scripts() map[string]string {
+	project := r.nodeProject()
+	pkgManager := r.packageManager(project)
+	scripts := map[string]string{"install": pkgManager + " install"}
+	if project.Scripts.Build != "" {
+		scripts["build"] = pkgManager + " run build"
+	}
+	if project.Scripts.Start != "" {
+		scripts["start"] = pkgManager + " run start"
+	}
+	return scripts
+}
+
+// ScriptInputs lists the files the install script reads: package.json and
+// the project's lockfiles.
+func (r *Recommender) ScriptInputs() map[string][]string {
+	return map[string][]string{
+		"install": recommenders.ExistingFiles(r.SrcDir,
+			"package.json", "package-lock.json", "pnpm-lock.yaml", "yarn.lock", ".npmrc"),
+	}
+}
+
 type nodeProject struct {
 	Scripts struct {
 		Build string `json:"build,omitempty"`
@@ -39,41 +79,80 @@ type nodeProject struct {
 	Engines struct {
 		Node string `json:"node,omitempty"`
 	} `json:"engines,omitempty"`
+	// PackageManager is set by corepack, for example "pnpm@8.6.0".
+	PackageManager string `json:"packageManager,omitempty"`
 }
 
-var versionMap = map[string]string{
-	// Map node versions to the corresponding nixpkgs:
-	"10": "nodejs-10_x",
-	"12": "nodejs-12_x",
-	"16": "nodejs-16_x",
-	"18": "nodejs-18_x",
-}
 var defaultNodeJSPkg = "nodejs"
 
+// nodePackage returns the nodejs package for the major version in .nvmrc,
+// .tool-versions or the engines field of package.json. For example, ">=18"
+// becomes nodejs@18.
 func (r *Recommender) nodePackage(project *nodeProject) string {
 	v := r.nodeVersion(project)
 	if v != nil {
-		pkg, ok := versionMap[v.Major()]
-		if ok {
-			return pkg
-		}
+		return defaultNodeJSPkg + "@" + v.Major()
 	}
 
 	return defaultNodeJSPkg
 }
 
 func (r *Recommender) nodeVersion(project *nodeProject) *analyzer.Version {
-	if r != nil {
-		if v, err := analyzer.NewVersion(project.Engines.Node); err == nil {
-			return v
-		}
+	if r == nil {
+		return nil
+	}
+	// The version files are what the developer actually runs, so they win
+	// over the range in engines.
+	version, _ := r.versionFile()
+	if version == "" {
+		version = project.Engines.Node
+	}
+	if v, err := analyzer.NewVersion(version); err == nil {
+		return v
 	}
 
 	return nil
 }
 
-func (r *Recommender) packageManager() string {
-	if fileutil.Exists(filepath.Join(r.SrcDir, "yarn.lock")) {
+// versionFile returns the version in .nvmrc or .tool-versions, and the name
+// of the file it's in. .nvmrc may also hold an alias like "lts/*", which is
+// ignored.
+func (r *Recommender) versionFile() (version, file string) {
+	if nvmrc, err := os.ReadFile(filepath.Join(r.SrcDir, ".nvmrc")); err == nil {
+		version := strings.TrimPrefix(strings.TrimSpace(string(nvmrc)), "v")
+		if _, err := analyzer.NewVersion(version); err == nil {
+			return version, ".nvmrc"
+		}
+	}
+	version = analyzer.ToolVersions(r.SrcDir)["nodejs"]
+	if _, err := analyzer.NewVersion(version); err == nil {
+		return version, analyzer.ToolVersionsFile
+	}
+	return "", ""
+}
+
+// RequestedVersions returns the exact node version in the project's version
+// file, if it has one.
+func (r *Recommender) RequestedVersions() []recommenders.RequestedVersion {
+	version, file := r.versionFile()
+	if version == "" {
+		return nil
+	}
+	return []recommenders.RequestedVersion{
+		{Package: defaultNodeJSPkg, Version: version, File: file},
+	}
+}
+
+// packageManager returns the package manager set in package.json or, if
+// there isn't one, the one that matches the project's lockfile.
+func (r *Recommender) packageManager(project *nodeProject) string {
+	if name, _, _ := strings.Cut(project.PackageManager, "@"); name != "" {
+		return name
+	}
+	switch {
+	case fileutil.Exists(filepath.Join(r.SrcDir, "pnpm-lock.yaml")):
+		return "pnpm"
+	case fileutil.Exists(filepath.Join(r.SrcDir, "yarn.lock")):
 		return "yarn"
 	}
 	return "npm"
@@ -83,8 +162,12 @@ func (r *Recommender) packages(pkgManager string, project *nodeProject) []string
 	nodeJSPkg := r.nodePackage(project)
 	pkgs := []string{nodeJSPkg}
 
-	if pkgManager == "yarn" {
+	// npm comes with nodejs.
+	switch pkgManager {
+	case "yarn":
 		return append(pkgs, "yarn")
+	case "pnpm":
+		return append(pkgs, "nodePackages.pnpm")
 	}
 	return pkgs
 }
@@ -0,0 +1,98 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package javascript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestPackages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "no-version",
+			files: map[string]string{"package.json": `{}`},
+			want:  []string{"nodejs"},
+		},
+		{
+			name: "engines-yarn",
+			files: map[string]string{
+				"package.json": `{"engines": {"node": ">=18"}}`,
+				"yarn.lock":    "",
+			},
+			want: []string{"nodejs@18", "yarn"},
+		},
+		{
+			name: "nvmrc-pnpm",
+			files: map[string]string{
+				"package.json":   `{"engines": {"node": ">=18"}}`,
+				".nvmrc":         "v20.9.0\n",
+				"pnpm-lock.yaml": "",
+			},
+			want: []string{"nodejs@20", "nodePackages.pnpm"},
+		},
+		{
+			name: "nvmrc-alias",
+			files: map[string]string{
+				"package.json": `{"engines": {"node": "^16.0.0"}}`,
+				".nvmrc":       "lts/*",
+			},
+			want: []string{"nodejs@16"},
+		},
+		{
+			name: "tool-versions",
+			files: map[string]string{
+				"package.json":   `{"engines": {"node": ">=18"}}`,
+				".tool-versions": "nodejs 21.1.0\npython 3.12.0\n",
+			},
+			want: []string{"nodejs@21"},
+		},
+		{
+			name: "package-manager-field",
+			files: map[string]string{
+				"package.json": `{"packageManager": "pnpm@8.6.0"}`,
+				"yarn.lock":    "",
+			},
+			want: []string{"nodejs", "nodePackages.pnpm"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			r := &Recommender{SrcDir: dir}
+			if diff := cmp.Diff(tc.want, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScripts(t *testing.T) {
+	dir := t.TempDir()
+	packageJSON := `{"scripts": {"build": "tsc", "test": "jest"}}`
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(packageJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "yarn.lock"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &Recommender{SrcDir: dir}
+	want := map[string]string{"install": "yarn install", "build": "yarn run build"}
+	if diff := cmp.Diff(want, r.Scripts()); diff != "" {
+		t.Errorf("wrong scripts (-want +got):\n%s", diff)
+	}
+	wantInputs := map[string][]string{"install": {"package.json", "yarn.lock"}}
+	if diff := cmp.Diff(wantInputs, r.ScriptInputs()); diff != "" {
+		t.Errorf("wrong script inputs (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package php
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+)
+
+const (
+	composerJSON = "composer.json"
+	defaultPkg   = "php" // Default to "latest" for cases where we can't determine a version.
+)
+
+// builtinExtensions are compiled into the php package, so they don't need to
+// be added. Others, like redis or imagick, are added as phpXYExtensions
+// packages, which the php plugin builds into php.
+var builtinExtensions = []string{
+	"bcmath", "calendar", "ctype", "curl", "date", "dom", "exif", "fileinfo",
+	"filter", "ftp", "gd", "gettext", "gmp", "hash", "iconv", "imap", "intl",
+	"json", "ldap", "libxml", "mbstring", "mysqli", "mysqlnd", "opcache",
+	"openssl", "pcntl", "pcre", "pdo", "pdo_mysql", "pdo_odbc", "pdo_pgsql",
+	"pdo_sqlite", "pgsql", "posix", "readline", "reflection", "session",
+	"simplexml", "soap", "sockets", "sodium", "spl", "sqlite3", "standard",
+	"tokenizer", "xml", "xmlreader", "xmlwriter", "zip", "zlib",
+}
+
+type Recommender struct {
+	SrcDir string
+}
+
+// implements interfaces recommenders.EnvRecommender and
+// recommenders.ScriptInputsRecommender (compile-time check)
+var (
+	_ recommenders.EnvRecommender          = (*Recommender)(nil)
+	_ recommenders.ScriptInputsRecommender = (*Recommender)(nil)
+)
+
+func (r *Recommender) IsRelevant() bool {
+	return fileutil.Exists(filepath.Join(r.SrcDir, composerJSON))
+}
+
+// Packages returns php and the extensions that composer.json requires. The
+// php plugin installs composer itself.
+func (r *Recommender) Packages() []string {
+	project := r.composerProject()
+	v := project.phpVersion()
+
+	pkgs := []string{defaultPkg}
+	extPrefix := "phpExtensions."
+	if v != nil {
+		pkgs[0] = defaultPkg + "@" + v.MajorMinor()
+		extPrefix = "php" + v.MajorMinorConcatenated() + "Extensions."
+	}
+	for _, ext := range project.extensions() {
+		pkgs = append(pkgs, extPrefix+ext)
+	}
+	return pkgs
+}
+
+// Env keeps composer's cache and global packages in .devbox and puts the
+// binaries of the project's dependencies on the PATH.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"COMPOSER_HOME": "{{.DevboxDir}}/composer",
+		"PATH":          "{{.ProjectDir}}/vendor/bin:$PATH",
+	}
+}
+
+// Scripts skips the autoloader and the project's scripts on install, since
+// install runs before the project's sources are copied in; build generates
+// the autoloader, which also runs the post-autoload-dump scripts.
+func (r *Recommender) Scripts() map[string]string {
+	return map[string]string{
+		"install": "composer install --no-scripts --no-autoloader",
+		"build":   "composer dump-autoload --optimize",
+	}
+}
+
+func (r *Recommender) ScriptInputs() map[string][]string {
+	return map[string][]string{
+		"install": recommenders.ExistingFiles(r.SrcDir, composerJSON, "composer.lock"),
+	}
+}
+
+type composerProject struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+func (r *Recommender) composerProject() *composerProject {
+	project := &composerProject{}
+	content, err := os.ReadFile(filepath.Join(r.SrcDir, composerJSON))
+	if err != nil {
+		return project
+	}
+	_ = json.Unmarshal(content, project)
+	return project
+}
+
+// phpVersion returns the lowest version allowed by the php constraint in
+// require. For example, "^8.1 || ^8.2" returns 8.1.
+func (p *composerProject) phpVersion() *analyzer.Version {
+	constraint := p.Require["php"]
+	constraint, _, _ = strings.Cut(constraint, "|")
+	constraint, _, _ = strings.Cut(strings.TrimSpace(constraint), " ")
+	constraint = strings.TrimLeft(constraint, "^~>=v")
+	constraint = strings.TrimSuffix(constraint, ".*")
+	v, err := analyzer.NewVersion(constraint)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// extensions returns the sorted names of the ext-* requirements that aren't
+// built into php.
+func (p *composerProject) extensions() []string {
+	var exts []string
+	for _, require := range []map[string]string{p.Require, p.RequireDev} {
+		for name := range require {
+			ext, ok := strings.CutPrefix(strings.ToLower(name), "ext-")
+			if !ok || slices.Contains(builtinExtensions, ext) || slices.Contains(exts, ext) {
+				continue
+			}
+			exts = append(exts, ext)
+		}
+	}
+	slices.Sort(exts)
+	return exts
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package php
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPackages(t *testing.T) {
+	testCases := []struct {
+		name         string
+		composerJSON string
+		want         []string
+	}{
+		{
+			name:         "no-version",
+			composerJSON: `{"require": {"monolog/monolog": "^3.0"}}`,
+			want:         []string{"php"},
+		},
+		{
+			name:         "caret",
+			composerJSON: `{"require": {"php": "^8.1", "ext-mbstring": "*", "ext-redis": "*"}}`,
+			want:         []string{"php@8.1", "php81Extensions.redis"},
+		},
+		{
+			name: "alternatives",
+			composerJSON: `{
+  "require": {"php": "^8.2 || ^8.3", "ext-imagick": "*"},
+  "require-dev": {"ext-xdebug": "*"}
+}`,
+			want: []string{"php@8.2", "php82Extensions.imagick", "php82Extensions.xdebug"},
+		},
+		{
+			name:         "range",
+			composerJSON: `{"require": {"php": ">=7.4 <9"}}`,
+			want:         []string{"php@7.4"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, composerJSON), []byte(tc.composerJSON), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			r := &Recommender{SrcDir: dir}
+			if diff := cmp.Diff(tc.want, r.Packages()); diff != "" {
+				t.Errorf("wrong packages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -10,19 +10,67 @@ import (
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
 
+// activateVenv activates the virtual environment that the python plugin
+// creates in .devbox/virtenv/python.
+const activateVenv = ". $VENV_DIR/bin/activate"
+
 type RecommenderPip struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*RecommenderPip)(nil)
+// implements interfaces recommenders.InitHookRecommender,
+// recommenders.ScriptInputsRecommender and recommenders.VersionRecommender
+// (compile-time check)
+var (
+	_ recommenders.InitHookRecommender     = (*RecommenderPip)(nil)
+	_ recommenders.ScriptInputsRecommender = (*RecommenderPip)(nil)
+	_ recommenders.VersionRecommender      = (*RecommenderPip)(nil)
+)
 
+// IsRelevant is true for projects with a requirements.txt, and for
+// pyproject.toml projects that aren't managed by poetry or pipenv.
 func (r *RecommenderPip) IsRelevant() bool {
-	return fileutil.Exists(filepath.Join(r.SrcDir, "requirements.txt"))
+	if r.hasRequirements() {
+		return true
+	}
+	if fileutil.Exists(filepath.Join(r.SrcDir, "Pipfile")) {
+		return false
+	}
+	project := loadPyProject(r.SrcDir)
+	return project != nil && !project.isPoetryProject()
 }
 
 func (r *RecommenderPip) Packages() []string {
 	return []string{
-		"python3",
+		pythonPackage(r.SrcDir),
+	}
+}
+
+func (r *RecommenderPip) RequestedVersions() []recommenders.RequestedVersion {
+	return pythonRequestedVersions(r.SrcDir)
+}
+
+func (r *RecommenderPip) InitHook() []string {
+	return []string{activateVenv}
+}
+
+func (r *RecommenderPip) Scripts() map[string]string {
+	if r.hasRequirements() {
+		return map[string]string{"install": "pip install -r requirements.txt"}
 	}
+	return map[string]string{"install": "pip install -e ."}
+}
+
+// ScriptInputs lists the files the install script reads. Installing a
+// pyproject.toml project in editable mode needs the whole project, so it has
+// no inputs.
+func (r *RecommenderPip) ScriptInputs() map[string][]string {
+	if r.hasRequirements() {
+		return map[string][]string{"install": {"requirements.txt"}}
+	}
+	return nil
+}
+
+func (r *RecommenderPip) hasRequirements() bool {
+	return fileutil.Exists(filepath.Join(r.SrcDir, "requirements.txt"))
 }
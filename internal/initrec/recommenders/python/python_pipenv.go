@@ -0,0 +1,55 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package python
+
+import (
+	"path/filepath"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+)
+
+type RecommenderPipenv struct {
+	SrcDir string
+}
+
+// implements interfaces recommenders.InitHookRecommender,
+// recommenders.ScriptInputsRecommender and recommenders.VersionRecommender
+// (compile-time check)
+var (
+	_ recommenders.InitHookRecommender     = (*RecommenderPipenv)(nil)
+	_ recommenders.ScriptInputsRecommender = (*RecommenderPipenv)(nil)
+	_ recommenders.VersionRecommender      = (*RecommenderPipenv)(nil)
+)
+
+func (r *RecommenderPipenv) IsRelevant() bool {
+	return fileutil.Exists(filepath.Join(r.SrcDir, "Pipfile"))
+}
+
+func (r *RecommenderPipenv) Packages() []string {
+	return []string{
+		pythonPackage(r.SrcDir),
+		"pipenv",
+	}
+}
+
+func (r *RecommenderPipenv) RequestedVersions() []recommenders.RequestedVersion {
+	return pythonRequestedVersions(r.SrcDir)
+}
+
+// InitHook activates the python plugin's virtual environment. pipenv installs
+// into the active virtual environment instead of creating its own.
+func (r *RecommenderPipenv) InitHook() []string {
+	return []string{activateVenv}
+}
+
+func (r *RecommenderPipenv) Scripts() map[string]string {
+	return map[string]string{"install": "pipenv install --dev"}
+}
+
+func (r *RecommenderPipenv) ScriptInputs() map[string][]string {
+	return map[string][]string{
+		"install": recommenders.ExistingFiles(r.SrcDir, "Pipfile", "Pipfile.lock"),
+	}
+}
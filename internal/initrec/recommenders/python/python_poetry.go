@@ -4,77 +4,52 @@
 package python
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
 
-	"github.com/pelletier/go-toml/v2"
-	"go.jetpack.io/devbox/internal/initrec/analyzer"
-
 	"go.jetpack.io/devbox/internal/fileutil"
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
 
+// RecommenderPoetry doesn't recommend an init hook because the poetry plugin
+// already sets up poetry's virtual environment.
 type RecommenderPoetry struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*RecommenderPoetry)(nil)
+// implements interfaces recommenders.ScriptInputsRecommender and
+// recommenders.VersionRecommender (compile-time check)
+var (
+	_ recommenders.ScriptInputsRecommender = (*RecommenderPoetry)(nil)
+	_ recommenders.VersionRecommender      = (*RecommenderPoetry)(nil)
+)
 
 func (r *RecommenderPoetry) IsRelevant() bool {
 	return fileutil.Exists(filepath.Join(r.SrcDir, "poetry.lock")) ||
-		fileutil.Exists(filepath.Join(r.SrcDir, "pyproject.toml"))
+		loadPyProject(r.SrcDir).isPoetryProject()
 }
 
 func (r *RecommenderPoetry) Packages() []string {
-	version := r.PythonVersion()
-	pythonPkg := fmt.Sprintf("python%s", version.MajorMinorConcatenated())
-
 	return []string{
-		pythonPkg,
+		pythonPackage(r.SrcDir),
 		"poetry",
 	}
 }
 
-// TODO: This can be generalized to all python planners
-func (r *RecommenderPoetry) PythonVersion() *analyzer.Version {
-	defaultVersion, _ := analyzer.NewVersion("3.10.6")
-	project := r.pyProject()
-
-	if project == nil {
-		return defaultVersion
-	}
-
-	if v, err := analyzer.NewVersion(project.Tool.Poetry.Dependencies.Python); err == nil {
-		return v
-	}
-	return defaultVersion
+func (r *RecommenderPoetry) RequestedVersions() []recommenders.RequestedVersion {
+	return pythonRequestedVersions(r.SrcDir)
 }
 
-type pyProject struct {
-	Tool struct {
-		Poetry struct {
-			Name         string `toml:"name"`
-			Dependencies struct {
-				Python string `toml:"python"`
-			} `toml:"dependencies"`
-			Packages []struct {
-				Include string `toml:"include"`
-				From    string `toml:"from"`
-			} `toml:"packages"`
-			Scripts map[string]string `toml:"scripts"`
-		} `toml:"poetry"`
-	} `toml:"tool"`
+// Scripts installs only the dependencies, since install runs before the
+// project's sources are copied in; build installs the project itself.
+func (r *RecommenderPoetry) Scripts() map[string]string {
+	return map[string]string{
+		"install": "poetry install --no-root",
+		"build":   "poetry install && poetry build",
+	}
 }
 
-func (r *RecommenderPoetry) pyProject() *pyProject {
-	pyProjectPath := filepath.Join(r.SrcDir, "pyproject.toml")
-	content, err := os.ReadFile(pyProjectPath)
-	if err != nil {
-		return nil
+func (r *RecommenderPoetry) ScriptInputs() map[string][]string {
+	return map[string][]string{
+		"install": recommenders.ExistingFiles(r.SrcDir, "pyproject.toml", "poetry.lock"),
 	}
-	proj := pyProject{}
-	_ = toml.Unmarshal(content, &proj)
-	return &proj
 }
@@ -0,0 +1,151 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package python
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
+	"go.jetpack.io/devbox/internal/initrec/recommenders"
+)
+
+// defaultPythonPkg is used when the project doesn't ask for a version.
+const defaultPythonPkg = "python3"
+
+// pythonPackage returns the python package for the project's python version,
+// for example python@3.11.
+func pythonPackage(srcDir string) string {
+	if v := pythonVersion(srcDir); v != nil {
+		return "python@" + v.MajorMinor()
+	}
+	return defaultPythonPkg
+}
+
+// pythonRequestedVersions returns the exact python version in the project's
+// version file, if it has one.
+func pythonRequestedVersions(srcDir string) []recommenders.RequestedVersion {
+	version, file := pythonVersionFile(srcDir)
+	if version == "" {
+		return nil
+	}
+	return []recommenders.RequestedVersion{{Package: "python", Version: version, File: file}}
+}
+
+// pythonVersion returns the python version the project asks for. It looks,
+// in order, at .python-version (used by pyenv), .tool-versions (used by
+// asdf), requires-python in pyproject.toml, the python dependency of poetry
+// and the requires section of the Pipfile.
+func pythonVersion(srcDir string) *analyzer.Version {
+	var candidates []string
+	if version, _ := pythonVersionFile(srcDir); version != "" {
+		candidates = append(candidates, version)
+	}
+	if project := loadPyProject(srcDir); project != nil {
+		candidates = append(candidates, project.Project.RequiresPython)
+		if project.isPoetryProject() {
+			candidates = append(candidates, project.Tool.Poetry.Dependencies.Python)
+		}
+	}
+	if pipfile := loadPipfile(srcDir); pipfile != nil {
+		candidates = append(candidates,
+			pipfile.Requires.PythonFullVersion,
+			pipfile.Requires.PythonVersion,
+		)
+	}
+
+	for _, c := range candidates {
+		if v, err := analyzer.NewVersion(normalizeConstraint(c)); err == nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// pythonVersionFile returns the version in .python-version or .tool-versions,
+// and the name of the file it's in.
+func pythonVersionFile(srcDir string) (version, file string) {
+	if b, err := os.ReadFile(filepath.Join(srcDir, ".python-version")); err == nil {
+		// pyenv allows several versions, one per line. The first one is the
+		// default.
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		if scanner.Scan() {
+			version := strings.TrimSpace(scanner.Text())
+			if _, err := analyzer.NewVersion(version); err == nil {
+				return version, ".python-version"
+			}
+		}
+	}
+	version = analyzer.ToolVersions(srcDir)["python"]
+	if _, err := analyzer.NewVersion(version); err == nil {
+		return version, analyzer.ToolVersionsFile
+	}
+	return "", ""
+}
+
+// normalizeConstraint turns a PEP 440 or poetry constraint like "~=3.10",
+// "==3.11.*" or ">=3.9,<4" into the lowest version it allows.
+func normalizeConstraint(constraint string) string {
+	constraint = strings.TrimSpace(constraint)
+	constraint, _, _ = strings.Cut(constraint, ",")
+	constraint = strings.TrimLeft(constraint, "~=^> ")
+	return strings.TrimSuffix(constraint, ".*")
+}
+
+type pyProject struct {
+	Project struct {
+		RequiresPython string `toml:"requires-python"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry *struct {
+			Name         string `toml:"name"`
+			Dependencies struct {
+				Python string `toml:"python"`
+			} `toml:"dependencies"`
+			Packages []struct {
+				Include string `toml:"include"`
+				From    string `toml:"from"`
+			} `toml:"packages"`
+			Scripts map[string]string `toml:"scripts"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+func loadPyProject(srcDir string) *pyProject {
+	content, err := os.ReadFile(filepath.Join(srcDir, "pyproject.toml"))
+	if err != nil {
+		return nil
+	}
+	proj := pyProject{}
+	_ = toml.Unmarshal(content, &proj)
+	return &proj
+}
+
+// isPoetryProject reports whether pyproject.toml is managed by poetry, as
+// opposed to another build backend like setuptools or hatch.
+func (p *pyProject) isPoetryProject() bool {
+	return p != nil && p.Tool.Poetry != nil
+}
+
+type pipfile struct {
+	Requires struct {
+		PythonVersion     string `toml:"python_version"`
+		PythonFullVersion string `toml:"python_full_version"`
+	} `toml:"requires"`
+}
+
+func loadPipfile(srcDir string) *pipfile {
+	content, err := os.ReadFile(filepath.Join(srcDir, "Pipfile"))
+	if err != nil {
+		return nil
+	}
+	p := pipfile{}
+	_ = toml.Unmarshal(content, &p)
+	return &p
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package python
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestPythonPackage(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "requirements",
+			files: map[string]string{"requirements.txt": "flask\n"},
+			want:  "python3",
+		},
+		{
+			name: "python-version",
+			files: map[string]string{
+				".python-version": "3.11.4\n3.10.12\n",
+				"pyproject.toml":  "[project]\nrequires-python = \">=3.9\"\n",
+			},
+			want: "python@3.11",
+		},
+		{
+			name:  "requires-python",
+			files: map[string]string{"pyproject.toml": "[project]\nrequires-python = \">=3.9,<4\"\n"},
+			want:  "python@3.9",
+		},
+		{
+			name:  "poetry",
+			files: map[string]string{"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\n"},
+			want:  "python@3.12",
+		},
+		{
+			name: "pipfile",
+			files: map[string]string{
+				".python-version": "system\n",
+				"Pipfile":         "[requires]\npython_version = \"3.10\"\n",
+			},
+			want: "python@3.10",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			if got := pythonPackage(dir); got != tc.want {
+				t.Errorf("got package %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecommendersRelevance(t *testing.T) {
+	dir := t.TempDir()
+	pyproject := "[project]\nname = \"app\"\n\n[tool.hatch.build]\n"
+	if err := os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte(pyproject), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !(&RecommenderPip{SrcDir: dir}).IsRelevant() {
+		t.Error("pip recommender isn't relevant for a hatch pyproject.toml")
+	}
+	if (&RecommenderPoetry{SrcDir: dir}).IsRelevant() {
+		t.Error("poetry recommender is relevant for a hatch pyproject.toml")
+	}
+}
@@ -8,10 +8,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-
-	"golang.org/x/mod/semver"
+	"strings"
 
 	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
 
@@ -19,16 +19,16 @@ type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
-
-var nixPackages = map[string]string{
-	"3.1": "ruby_3_1",
-	"3.0": "ruby_3_0",
-	"2.7": "ruby",
-}
+// implements interfaces recommenders.EnvRecommender,
+// recommenders.InitHookRecommender and recommenders.VersionRecommender
+// (compile-time check)
+var (
+	_ recommenders.EnvRecommender      = (*Recommender)(nil)
+	_ recommenders.InitHookRecommender = (*Recommender)(nil)
+	_ recommenders.VersionRecommender  = (*Recommender)(nil)
+)
 
-const defaultPkg = "ruby_3_1"
+const defaultPkg = "ruby" // Default to "latest" for cases where we can't determine a version.
 
 var rubyVersionRegex = regexp.MustCompile(`ruby\s+"(<|>|<=|>=|~>|=|)\s*([\d|\\.]+)"`)
 
@@ -37,24 +37,82 @@ func (r *Recommender) IsRelevant() bool {
 }
 
 func (r *Recommender) Packages() []string {
-	gemfile := filepath.Join(r.SrcDir, "Gemfile")
-	v := parseRubyVersion(gemfile)
-	pkg, ok := nixPackages[semver.MajorMinor(v)]
-	if !ok {
-		pkg = defaultPkg
-	}
 	return []string{
-		pkg,
+		rubyPackage(r.SrcDir),
 		"gcc",     // for rails
 		"gnumake", // for rails
 	}
 }
 
+// Env installs the project's gems in .devbox instead of the ruby plugin's
+// shared GEM_HOME.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"BUNDLE_PATH": "{{.DevboxDir}}/bundle",
+	}
+}
+
+// InitHook installs the gems in the Gemfile when they're missing, so the
+// shell is ready to run the project.
+func (r *Recommender) InitHook() []string {
+	return []string{"bundle check > /dev/null || bundle install"}
+}
+
+// RequestedVersions returns the exact ruby version in the project's version
+// file, if it has one.
+func (r *Recommender) RequestedVersions() []recommenders.RequestedVersion {
+	version, file := rubyVersionFile(r.SrcDir)
+	if version == "" {
+		return nil
+	}
+	return []recommenders.RequestedVersion{{Package: defaultPkg, Version: version, File: file}}
+}
+
+// rubyPackage returns the ruby package for the version in .ruby-version,
+// .tool-versions or the Gemfile, for example ruby@3.2.
+func rubyPackage(srcDir string) string {
+	fileVersion, _ := rubyVersionFile(srcDir)
+	for _, version := range []string{
+		fileVersion,
+		parseRubyVersion(filepath.Join(srcDir, "Gemfile")),
+	} {
+		if v, err := analyzer.NewVersion(version); err == nil {
+			return defaultPkg + "@" + v.MajorMinor()
+		}
+	}
+	return defaultPkg
+}
+
+// rubyVersionFile returns the version in .ruby-version or .tool-versions, and
+// the name of the file it's in.
+func rubyVersionFile(srcDir string) (version, file string) {
+	version = parseRubyVersionFile(filepath.Join(srcDir, ".ruby-version"))
+	if _, err := analyzer.NewVersion(version); err == nil {
+		return version, ".ruby-version"
+	}
+	version = analyzer.ToolVersions(srcDir)["ruby"]
+	if _, err := analyzer.NewVersion(version); err == nil {
+		return version, analyzer.ToolVersionsFile
+	}
+	return "", ""
+}
+
+// parseRubyVersionFile parses a .ruby-version file, which version managers
+// like rbenv and chruby write as "3.2.2" or "ruby-3.2.2".
+func parseRubyVersionFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(content)), "ruby-")
+}
+
 func parseRubyVersion(gemfile string) string {
 	f, err := os.Open(gemfile)
 	if err != nil {
 		return ""
 	}
+	defer f.Close()
 	s := bufio.NewScanner(f)
 	for s.Scan() {
 		line := s.Text()
@@ -0,0 +1,46 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package ruby
+
+import (
+	"testing"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestRubyPackage(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "no-version",
+			files: map[string]string{"Gemfile": "source \"https://rubygems.org\"\n"},
+			want:  "ruby",
+		},
+		{
+			name:  "gemfile",
+			files: map[string]string{"Gemfile": "source \"https://rubygems.org\"\nruby \"~> 3.1.4\"\n"},
+			want:  "ruby@3.1",
+		},
+		{
+			name: "ruby-version",
+			files: map[string]string{
+				"Gemfile":       "ruby \"3.1.4\"\n",
+				".ruby-version": "ruby-3.2.2\n",
+			},
+			want: "ruby@3.2",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			if got := rubyPackage(dir); got != tc.want {
+				t.Errorf("got package %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
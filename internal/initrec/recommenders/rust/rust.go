@@ -4,10 +4,15 @@
 package rust
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/alessio/shellescape"
+	"github.com/pelletier/go-toml/v2"
+
 	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/initrec/analyzer"
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
 
@@ -18,8 +23,14 @@ type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interfaces recommenders.EnvRecommender,
+// recommenders.InitHookRecommender and recommenders.ScriptRecommender
+// (compile-time check)
+var (
+	_ recommenders.EnvRecommender      = (*Recommender)(nil)
+	_ recommenders.InitHookRecommender = (*Recommender)(nil)
+	_ recommenders.ScriptRecommender   = (*Recommender)(nil)
+)
 
 func (r *Recommender) IsRelevant() bool {
 	return cargoTomlPath(r.SrcDir) != ""
@@ -29,6 +40,64 @@ func (r *Recommender) Packages() []string {
 	return []string{"rustup"}
 }
 
+// Env keeps cargo's registry cache and the binaries installed with
+// `cargo install` in .devbox instead of the user's home directory.
+func (r *Recommender) Env() map[string]string {
+	return map[string]string{
+		"CARGO_HOME": "{{.DevboxDir}}/cargo",
+		"PATH":       "{{.DevboxDir}}/cargo/bin:$PATH",
+	}
+}
+
+// InitHook installs the toolchain that the project's rust-toolchain file asks
+// for, if it isn't installed yet, so that starting a shell doesn't check for
+// updates every time. rustup then picks it automatically inside the project.
+// Projects without a toolchain file use the stable channel.
+func (r *Recommender) InitHook() []string {
+	if channel := toolchainChannel(r.SrcDir); channel != "" {
+		quoted := shellescape.Quote(channel)
+		// rustup lists toolchains with the host triple, such as
+		// stable-x86_64-unknown-linux-gnu.
+		return []string{
+			"rustup toolchain list | grep -q " + shellescape.Quote("^"+channel+"-") +
+				" || rustup toolchain install " + quoted,
+		}
+	}
+	return []string{"rustup default stable"}
+}
+
+func (r *Recommender) Scripts() map[string]string {
+	return map[string]string{
+		"build": "cargo build",
+		"test":  "cargo test",
+	}
+}
+
+// toolchainChannel returns the channel (such as "stable", "nightly-2023-10-01"
+// or "1.73.0") in rust-toolchain.toml, in the legacy rust-toolchain file,
+// which is either TOML or just the channel name, or in .tool-versions.
+func toolchainChannel(srcDir string) string {
+	for _, name := range []string{"rust-toolchain.toml", "rust-toolchain"} {
+		content, err := os.ReadFile(filepath.Join(srcDir, name))
+		if err != nil {
+			continue
+		}
+		var toolchain struct {
+			Toolchain struct {
+				Channel string `toml:"channel"`
+			} `toml:"toolchain"`
+		}
+		if err := toml.Unmarshal(content, &toolchain); err == nil {
+			return toolchain.Toolchain.Channel
+		}
+		if name == "rust-toolchain" {
+			return strings.TrimSpace(string(content))
+		}
+		return ""
+	}
+	return analyzer.ToolVersions(srcDir)["rust"]
+}
+
 // Tries to find Cargo.toml or cargo.toml. Returns the path with srcDir if found
 // and empty-string if not found.
 //
@@ -0,0 +1,67 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package rust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestToolchainChannel(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{
+			name:    "toml",
+			file:    "rust-toolchain.toml",
+			content: "[toolchain]\nchannel = \"1.73.0\"\ncomponents = [\"clippy\"]\n",
+			want:    "1.73.0",
+		},
+		{
+			name:    "legacy-toml",
+			file:    "rust-toolchain",
+			content: "[toolchain]\nchannel = \"nightly-2023-10-01\"\n",
+			want:    "nightly-2023-10-01",
+		},
+		{
+			name:    "legacy-plain",
+			file:    "rust-toolchain",
+			content: "nightly-2023-10-01\n",
+			want:    "nightly-2023-10-01",
+		},
+		{
+			name:    "no-channel",
+			file:    "rust-toolchain.toml",
+			content: "[toolchain]\nprofile = \"minimal\"\n",
+			want:    "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tc.file), []byte(tc.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := toolchainChannel(dir); got != tc.want {
+				t.Errorf("got channel %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInitHookInstallsMissingToolchain(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rust-toolchain"), []byte("1.73.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &Recommender{SrcDir: dir}
+	want := []string{"rustup toolchain list | grep -q '^1.73.0-' || rustup toolchain install 1.73.0"}
+	if got := r.InitHook(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("got init hook %q, want %q", got, want)
+	}
+}
@@ -4,16 +4,26 @@
 package zig
 
 import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
 	"go.jetpack.io/devbox/internal/initrec/analyzer"
 	"go.jetpack.io/devbox/internal/initrec/recommenders"
 )
 
+const defaultPkg = "zig" // Default to "latest" for cases where we can't determine a version.
+
+// Matches .minimum_zig_version = "0.12.0" in build.zig.zon.
+var minimumZigVersionRegex = regexp.MustCompile(`\.minimum_zig_version\s*=\s*"([0-9.]+)`)
+
 type Recommender struct {
 	SrcDir string
 }
 
-// implements interface recommenders.Recommender (compile-time check)
-var _ recommenders.Recommender = (*Recommender)(nil)
+// implements interface recommenders.ScriptRecommender (compile-time check)
+var _ recommenders.ScriptRecommender = (*Recommender)(nil)
 
 func (r *Recommender) IsRelevant() bool {
 	a, err := analyzer.NewAnalyzer(r.SrcDir)
@@ -26,6 +36,35 @@ func (r *Recommender) IsRelevant() bool {
 
 func (r *Recommender) Packages() []string {
 	return []string{
-		"zig",
+		zigPackage(r.SrcDir),
+	}
+}
+
+func (r *Recommender) Scripts() map[string]string {
+	return map[string]string{
+		"build": "zig build",
+		"test":  "zig build test",
+		"start": "zig build run",
+	}
+}
+
+// zigPackage returns the zig package for the version pinned in .zig-version
+// (used by zig version managers) or the minimum version in build.zig.zon,
+// for example zig@0.11.
+func zigPackage(srcDir string) string {
+	var candidates []string
+	if content, err := os.ReadFile(filepath.Join(srcDir, ".zig-version")); err == nil {
+		candidates = append(candidates, strings.TrimSpace(string(content)))
+	}
+	if content, err := os.ReadFile(filepath.Join(srcDir, "build.zig.zon")); err == nil {
+		if matches := minimumZigVersionRegex.FindSubmatch(content); matches != nil {
+			candidates = append(candidates, string(matches[1]))
+		}
+	}
+	for _, c := range candidates {
+		if v, err := analyzer.NewVersion(c); err == nil {
+			return defaultPkg + "@" + v.MajorMinor()
+		}
 	}
+	return defaultPkg
 }
@@ -0,0 +1,58 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package zig
+
+import (
+	"testing"
+
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+func TestZigPackage(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "no-version",
+			files: map[string]string{"build.zig": ""},
+			want:  "zig",
+		},
+		{
+			name: "zig-version",
+			files: map[string]string{
+				"build.zig":     "",
+				".zig-version":  "0.11.0\n",
+				"build.zig.zon": ".{\n    .minimum_zig_version = \"0.12.0\",\n}\n",
+			},
+			want: "zig@0.11",
+		},
+		{
+			name: "zon",
+			files: map[string]string{
+				"build.zig":     "",
+				"build.zig.zon": ".{\n    .name = \"app\",\n    .minimum_zig_version = \"0.12.0\",\n}\n",
+			},
+			want: "zig@0.12",
+		},
+		{
+			name: "master",
+			files: map[string]string{
+				"build.zig":    "",
+				".zig-version": "master\n",
+			},
+			want: "zig",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileutil.WriteFilesForTest(t, dir, tc.files)
+			if got := zigPackage(dir); got != tc.want {
+				t.Errorf("got package %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+// Package keychain stores secrets in the OS keychain. It uses the security
+// command on macOS and secret-tool (libsecret) on Linux, so it doesn't need
+// cgo.
+package keychain
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/redact"
+)
+
+// ErrNotFound is returned by Get when the keychain has no secret for the
+// service and account.
+var ErrNotFound = errors.New("secret not found in keychain")
+
+// Get returns the secret stored for the service and account.
+func Get(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	}
+	if err := checkTool(cmd); err != nil {
+		return "", err
+	}
+	out, err := cmd.Output()
+	secret := strings.TrimSuffix(string(out), "\n")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) || (err == nil && secret == "") {
+		// Both tools exit with an error when there's no secret, although
+		// secret-tool only does so in recent versions.
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", redact.Errorf("read secret from keychain: %w", err)
+	}
+	return secret, nil
+}
+
+// Set stores the secret for the service and account, replacing any secret
+// that's already stored.
+func Set(service, account, secret string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// With -w last and no value, security prompts for the secret
+		// (twice, to confirm it) instead of taking it as an argument,
+		// which other processes could see.
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", account, "-w")
+		cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label", service+" "+account,
+			"service", service, "account", account)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+	return run(cmd, "store secret in keychain")
+}
+
+// Delete removes the secret for the service and account. It isn't an error
+// if there's no secret.
+func Delete(service, account string) error {
+	if _, err := Get(service, account); errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "delete-generic-password", "-s", service, "-a", account)
+	default:
+		cmd = exec.Command("secret-tool", "clear", "service", service, "account", account)
+	}
+	return run(cmd, "delete secret from keychain")
+}
+
+func run(cmd *exec.Cmd, action string) error {
+	if err := checkTool(cmd); err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return redact.Errorf("%s: %w: %s", redact.Safe(action), err, stderr.String())
+	}
+	return nil
+}
+
+// checkTool returns a user error if the command that talks to the keychain
+// isn't installed.
+func checkTool(cmd *exec.Cmd) error {
+	if cmd.Err == nil {
+		return nil
+	}
+	if runtime.GOOS == "darwin" {
+		return usererr.New("Devbox couldn't find the security command to access your keychain.")
+	}
+	return usererr.New(
+		"Devbox stores credentials in your keychain with secret-tool, which isn't installed. " +
+			"Install it with your package manager (it's usually in the libsecret-tools or libsecret package).",
+	)
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// ResolveChannel is like Resolve for a package that devbox.json installs from
+// a specific nixpkgs channel, such as nixos-unstable, or a nixpkgs revision.
+// The package is pinned to the revision the channel points to when it's first
+// resolved, and resolved again if devbox.json changes its channel.
+func (f *File) ResolveChannel(pkg, channel string) (*Package, error) {
+	if entry := f.Get(pkg); entry != nil && entry.Channel == channel {
+		return entry, nil
+	}
+	locked, err := f.FetchResolvedChannel(pkg, channel)
+	if err != nil {
+		return nil, err
+	}
+	f.Packages[pkg] = locked
+	return locked, nil
+}
+
+// FetchResolvedChannel pins a package from a nixpkgs channel to the revision
+// the channel points to now, but doesn't write it to the lockfile. devbox
+// update uses it to move packages to the latest revision of their channel.
+func (f *File) FetchResolvedChannel(pkg, channel string) (*Package, error) {
+	installable, err := ChannelInstallable(pkg, channel)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := resolveFlake(installable)
+	if err != nil {
+		return nil, err
+	}
+	locked.Channel = channel
+	return locked, nil
+}
+
+// ChannelInstallable returns the installable for the attribute path attrPath
+// in a nixpkgs channel, such as nixos-unstable or nixos-23.11. The channel can
+// also be a nixpkgs commit hash.
+func ChannelInstallable(attrPath, channel string) (flake.Installable, error) {
+	ref, err := flake.ParseRef("github:NixOS/nixpkgs/" + channel)
+	if err != nil {
+		return flake.Installable{}, usererr.New("Invalid nixpkgs channel %q for package %s.", channel, attrPath)
+	}
+	return flake.Installable{Ref: ref, AttrPath: attrPath}, nil
+}
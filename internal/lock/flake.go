@@ -0,0 +1,69 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// flakeSource is the source of packages that are flake installables, such as
+// github:numtide/devshell#default.
+const flakeSource string = "flake"
+
+// lockableFlake returns the parsed installable if pkg is a flake installable
+// that devbox pins in the lockfile. Local (path) flakes aren't pinned because
+// they're part of the project or the user's machine. Flakes that use
+// a scheme or an #attrpath are never devbox package names.
+func lockableFlake(pkg string) (flake.Installable, bool) {
+	installable, err := flake.ParseInstallable(pkg)
+	if err != nil {
+		return flake.Installable{}, false
+	}
+	if installable.AttrPath == "" && !strings.Contains(pkg, ":") {
+		return flake.Installable{}, false
+	}
+	switch installable.Ref.Type {
+	case flake.TypeIndirect, flake.TypeGitHub, flake.TypeGit:
+		return installable, true
+	default:
+		return flake.Installable{}, false
+	}
+}
+
+// resolveFlake pins a flake installable to the revision its flake reference
+// currently points to. Installables that already have a revision are kept
+// as they are.
+func resolveFlake(installable flake.Installable) (*Package, error) {
+	locked := &Package{Source: flakeSource}
+	if installable.Ref.Type != flake.TypeIndirect && installable.Ref.Rev != "" {
+		locked.Resolved = installable.String()
+		return locked, nil
+	}
+	metadata, err := nix.ResolveFlakeRef(installable.Ref.String())
+	if err != nil {
+		return nil, err
+	}
+	installable.Ref = metadata.Locked
+	locked.Resolved = installable.String()
+	if metadata.LastModified != 0 {
+		locked.LastModified = time.Unix(metadata.LastModified, 0).UTC().Format(time.RFC3339)
+	}
+	return locked, nil
+}
+
+// FetchResolvedFlake pins a flake installable to the revision its flake
+// reference points to now, but doesn't write it to the lockfile. devbox
+// update uses it to move flakes to their latest revision.
+func (f *File) FetchResolvedFlake(pkg string) (*Package, error) {
+	installable, ok := lockableFlake(pkg)
+	if !ok {
+		return nil, errors.Errorf("%s isn't a flake that devbox pins", pkg)
+	}
+	return resolveFlake(installable)
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import "testing"
+
+func TestLockableFlake(t *testing.T) {
+	cases := map[string]bool{
+		"github:numtide/devshell#default":       true,
+		"github:numtide/devshell":               true,
+		"nixpkgs/nixos-22.11#postgresql_15":     true,
+		"flake:nixpkgs#hello":                   true,
+		"git+https://example.com/repo.git#tool": true,
+		"path:./my-flake#my-package":            false,
+		"./my-flake#my-package":                 false,
+		"hello":                                 false,
+		"go@1.21":                               false,
+	}
+	for pkg, want := range cases {
+		if _, got := lockableFlake(pkg); got != want {
+			t.Errorf("got lockableFlake(%q) = %t, want %t", pkg, got, want)
+		}
+	}
+}
+
+func TestResolveFlakeWithRev(t *testing.T) {
+	installable, ok := lockableFlake("github:numtide/devshell/0123456789abcdef0123456789abcdef01234567#default")
+	if !ok {
+		t.Fatal("got unlockable flake")
+	}
+	locked, err := resolveFlake(installable)
+	if err != nil {
+		t.Fatal("got resolveFlake error:", err)
+	}
+	want := "github:numtide/devshell/0123456789abcdef0123456789abcdef01234567#default"
+	if locked.Resolved != want {
+		t.Errorf("got resolved %q, want %q", locked.Resolved, want)
+	}
+	if !locked.IsFlake() {
+		t.Errorf("got source %q, want %q", locked.Source, flakeSource)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"slices"
+
+	"github.com/samber/lo"
+	"go.jetpack.io/devbox/internal/devpkg/pkgtype"
+	"go.jetpack.io/devbox/internal/searcher"
+)
+
+// OutOfSync compares devbox.lock with the packages in devbox.json. missing
+// are the packages that devbox.lock should have an entry for, but doesn't,
+// and extra are the entries in devbox.lock for packages that aren't in
+// devbox.json. Local packages are left out, since they're locked in
+// devbox.local.lock. Neither is written to the lockfile.
+func (f *File) OutOfSync() (missing, extra []string) {
+	names := f.devboxProject.PackageNames()
+	for _, name := range names {
+		if isLocked(name) && f.Get(name) == nil {
+			missing = append(missing, name)
+		}
+	}
+	local := f.localPackageNames()
+	for _, key := range lo.Keys(f.Packages) {
+		if !slices.Contains(names, key) && !slices.Contains(local, key) {
+			extra = append(extra, key)
+		}
+	}
+	slices.Sort(extra)
+	return missing, extra
+}
+
+// isLocked reports whether Resolve records pkg in the lockfile. Local flakes
+// and .nix files aren't locked.
+func isLocked(pkg string) bool {
+	if _, _, versioned := searcher.ParseVersionedPackage(pkg); versioned || pkgtype.IsRunX(pkg) {
+		return true
+	}
+	if _, ok := lockableFlake(pkg); ok {
+		return true
+	}
+	return IsLegacyPackage(pkg)
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"slices"
+	"testing"
+)
+
+type testProject struct {
+	packages      []string
+	localPackages []string
+	dir           string
+}
+
+func (p testProject) ConfigHash() (string, error) { return "", nil }
+func (p testProject) NixPkgsCommitHash() string   { return "" }
+func (p testProject) PackageNames() []string      { return p.packages }
+func (p testProject) LocalPackageNames() []string { return p.localPackages }
+func (p testProject) ProjectDir() string          { return p.dir }
+
+func TestOutOfSync(t *testing.T) {
+	f := &File{
+		devboxProject: testProject{packages: []string{
+			"go@1.21",
+			"python@3.12",
+			"github:numtide/devshell",
+			"path:./my-flake#hello",
+		}},
+		Packages: map[string]*Package{
+			"go@1.21":     {Resolved: "github:NixOS/nixpkgs/abc#go_1_21"},
+			"hello@2.12":  {},
+			"curl@latest": {},
+		},
+	}
+	missing, extra := f.OutOfSync()
+	if want := []string{"python@3.12", "github:numtide/devshell"}; !slices.Equal(missing, want) {
+		t.Errorf("got missing = %v, want %v", missing, want)
+	}
+	if want := []string{"curl@latest", "hello@2.12"}; !slices.Equal(extra, want) {
+		t.Errorf("got extra = %v, want %v", extra, want)
+	}
+}
@@ -7,6 +7,9 @@ type devboxProject interface {
 	ConfigHash() (string, error)
 	NixPkgsCommitHash() string
 	PackageNames() []string
+	// LocalPackageNames returns the packages from devbox.local.json and
+	// the user-level defaults, which are locked in devbox.local.lock.
+	LocalPackageNames() []string
 	ProjectDir() string
 }
 
@@ -15,4 +18,5 @@ type Locker interface {
 	LegacyNixpkgsPath(string) string
 	ProjectDir() string
 	Resolve(string) (*Package, error)
+	ResolveChannel(pkg, channel string) (*Package, error)
 }
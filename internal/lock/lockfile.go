@@ -7,7 +7,9 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -28,7 +30,9 @@ type File struct {
 
 	LockFileVersion string `json:"lockfile_version"`
 
-	// Packages is keyed by "canonicalName@version"
+	// Packages is keyed by "canonicalName@version". It also has the entries
+	// of the project's local packages, which are saved to devbox.local.lock
+	// instead of devbox.lock.
 	Packages map[string]*Package `json:"packages"`
 }
 
@@ -40,12 +44,20 @@ func GetFile(project devboxProject) (*File, error) {
 		Packages:        map[string]*Package{},
 	}
 	err := cuecfg.ParseFile(lockFilePath(project.ProjectDir()), lockFile)
-	if errors.Is(err, fs.ErrNotExist) {
-		return lockFile, nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
 	}
-	if err != nil {
+
+	localLockFile := &File{Packages: map[string]*Package{}}
+	err = cuecfg.ParseFile(localLockFilePath(project.ProjectDir()), localLockFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return nil, err
 	}
+	for name, pkg := range localLockFile.Packages {
+		if _, ok := lockFile.Packages[name]; !ok {
+			lockFile.Packages[name] = pkg
+		}
+	}
 	return lockFile, nil
 }
 
@@ -78,6 +90,11 @@ func (f *File) Resolve(pkg string) (*Package, error) {
 			if err != nil {
 				return nil, err
 			}
+		} else if installable, ok := lockableFlake(pkg); ok {
+			locked, err = resolveFlake(installable)
+			if err != nil {
+				return nil, err
+			}
 		} else if IsLegacyPackage(pkg) {
 			// These are legacy packages without a version. Resolve to nixpkgs with
 			// whatever hash is in the devbox.json
@@ -97,8 +114,32 @@ func (f *File) ForceResolve(pkg string) (*Package, error) {
 	return f.Resolve(pkg)
 }
 
+// Save writes the entries of the project's packages to devbox.lock, and the
+// entries of its local packages to devbox.local.lock. The local lockfile is
+// removed when there aren't any local packages.
 func (f *File) Save() error {
-	return cuecfg.WriteFile(lockFilePath(f.devboxProject.ProjectDir()), f)
+	projectDir := f.devboxProject.ProjectDir()
+	local := f.localPackageNames()
+	shared := &File{LockFileVersion: f.LockFileVersion, Packages: lo.OmitByKeys(f.Packages, local)}
+	if err := cuecfg.WriteFile(lockFilePath(projectDir), shared); err != nil {
+		return err
+	}
+
+	localPath := localLockFilePath(projectDir)
+	localPkgs := lo.PickByKeys(f.Packages, local)
+	if len(localPkgs) == 0 {
+		if err := os.Remove(localPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	return cuecfg.WriteFile(localPath, &File{LockFileVersion: f.LockFileVersion, Packages: localPkgs})
+}
+
+// localPackageNames returns the local packages that aren't also in the
+// project's config.
+func (f *File) localPackageNames() []string {
+	return lo.Without(f.devboxProject.LocalPackageNames(), f.devboxProject.PackageNames()...)
 }
 
 func (f *File) LegacyNixpkgsPath(pkg string) string {
@@ -132,6 +173,8 @@ func IsLegacyPackage(pkg string) bool {
 	_, _, versioned := searcher.ParseVersionedPackage(pkg)
 	return !versioned &&
 		!strings.Contains(pkg, ":") &&
+		// Relative paths are local flakes or .nix files.
+		!strings.HasPrefix(pkg, ".") &&
 		// We don't support absolute paths without "path:" prefix, but adding here
 		// just in case we ever do.
 		// Landau note: I don't think we should support it, it's hard to read and a
@@ -139,10 +182,11 @@ func IsLegacyPackage(pkg string) bool {
 		!strings.HasPrefix(pkg, "/")
 }
 
-// Tidy ensures that the lockfile has the set of packages corresponding to the devbox.json config.
-// It gets rid of older packages that are no longer needed.
+// Tidy ensures that the lockfile has the set of packages corresponding to the devbox.json config
+// and the local packages. It gets rid of older packages that are no longer needed.
 func (f *File) Tidy() {
-	f.Packages = lo.PickByKeys(f.Packages, f.devboxProject.PackageNames())
+	names := slices.Concat(f.devboxProject.PackageNames(), f.devboxProject.LocalPackageNames())
+	f.Packages = lo.PickByKeys(f.Packages, names)
 }
 
 // IsUpToDateAndInstalled returns true if the lockfile is up to date and the
@@ -185,6 +229,13 @@ func lockFilePath(projectDir string) string {
 	return filepath.Join(projectDir, "devbox.lock")
 }
 
+// localLockFilePath returns the path of the lockfile for the packages from
+// devbox.local.json and the user-level defaults. Like devbox.local.json, it's
+// meant to be gitignored.
+func localLockFilePath(projectDir string) string {
+	return filepath.Join(projectDir, "devbox.local.lock")
+}
+
 func ResolveRunXPackage(ctx context.Context, pkg string) (types.PkgRef, error) {
 	ref, err := types.NewPkgRef(strings.TrimPrefix(pkg, pkgtype.RunXPrefix))
 	if err != nil {
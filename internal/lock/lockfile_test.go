@@ -0,0 +1,71 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/cuecfg"
+)
+
+func TestSaveLocalPackages(t *testing.T) {
+	dir := t.TempDir()
+	project := testProject{
+		packages:      []string{"go@1.21"},
+		localPackages: []string{"ripgrep@14"},
+		dir:           dir,
+	}
+	f := &File{
+		devboxProject:   project,
+		LockFileVersion: lockFileVersion,
+		Packages: map[string]*Package{
+			"go@1.21":    {Resolved: "github:NixOS/nixpkgs/abc#go_1_21"},
+			"ripgrep@14": {Resolved: "github:NixOS/nixpkgs/abc#ripgrep"},
+		},
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	shared := &File{}
+	if err := cuecfg.ParseFile(lockFilePath(dir), shared); err != nil {
+		t.Fatal(err)
+	}
+	if got := lo.Keys(shared.Packages); !slices.Equal(got, []string{"go@1.21"}) {
+		t.Errorf("got devbox.lock packages %v, want only go@1.21", got)
+	}
+	local := &File{}
+	if err := cuecfg.ParseFile(localLockFilePath(dir), local); err != nil {
+		t.Fatal(err)
+	}
+	if got := lo.Keys(local.Packages); !slices.Equal(got, []string{"ripgrep@14"}) {
+		t.Errorf("got devbox.local.lock packages %v, want only ripgrep@14", got)
+	}
+
+	// A new process gets the local entries back, so it doesn't resolve
+	// the local packages again.
+	loaded, err := GetFile(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Get("ripgrep@14") == nil {
+		t.Error("got no entry for ripgrep@14 after loading the lockfiles")
+	}
+
+	// The local lockfile is removed with the last local package.
+	project.localPackages = nil
+	loaded.devboxProject = project
+	loaded.Tidy()
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "devbox.local.lock")); !os.IsNotExist(err) {
+		t.Errorf("got devbox.local.lock without local packages (stat error %v)", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/nix"
+)
+
+// flakeLock is the part of a flake.lock file that has the locked inputs.
+type flakeLock struct {
+	Nodes map[string]struct {
+		Locked struct {
+			Type    string `json:"type"`
+			Owner   string `json:"owner"`
+			Repo    string `json:"repo"`
+			Rev     string `json:"rev"`
+			NarHash string `json:"narHash"`
+		} `json:"locked"`
+	} `json:"nodes"`
+}
+
+// SetNarHashes records the narHash of the nixpkgs revision each package
+// resolves to. The hashes come from the flake.lock that Nix writes when it
+// evaluates the generated flake, so recording them doesn't download anything.
+// Packages that already have a narHash keep it. The lockfile isn't saved.
+func (f *File) SetNarHashes(flakeLockPath string) error {
+	data, err := os.ReadFile(flakeLockPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var fl flakeLock
+	if err := json.Unmarshal(data, &fl); err != nil {
+		return errors.Wrapf(err, "parse %s", flakeLockPath)
+	}
+
+	narHashes := map[string]string{}
+	for _, node := range fl.Nodes {
+		locked := node.Locked
+		if locked.Type == "github" && locked.NarHash != "" &&
+			strings.EqualFold(locked.Owner, "NixOS") && strings.EqualFold(locked.Repo, "nixpkgs") {
+			narHashes[locked.Rev] = locked.NarHash
+		}
+	}
+
+	for _, pkg := range f.Packages {
+		if pkg.NarHash != "" || !nix.IsGithubNixpkgsURL(pkg.Resolved) {
+			continue
+		}
+		pkg.NarHash = narHashes[nix.HashFromNixPkgsURL(pkg.Resolved)]
+	}
+	return nil
+}
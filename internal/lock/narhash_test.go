@@ -0,0 +1,77 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetNarHashes(t *testing.T) {
+	flakeLockPath := filepath.Join(t.TempDir(), "flake.lock")
+	err := os.WriteFile(flakeLockPath, []byte(`{
+  "nodes": {
+    "nixpkgs": {
+      "locked": {
+        "narHash": "sha256-default",
+        "owner": "NixOS",
+        "repo": "nixpkgs",
+        "rev": "1111111111111111111111111111111111111111",
+        "type": "github"
+      }
+    },
+    "nixpkgs_2": {
+      "locked": {
+        "narHash": "sha256-hello",
+        "owner": "NixOS",
+        "repo": "nixpkgs",
+        "rev": "2222222222222222222222222222222222222222",
+        "type": "github"
+      }
+    },
+    "root": {}
+  },
+  "root": "root",
+  "version": 7
+}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &File{Packages: map[string]*Package{
+		"hello@latest": {
+			Resolved: "github:NixOS/nixpkgs/2222222222222222222222222222222222222222#hello",
+		},
+		"go@latest": {
+			Resolved: "github:NixOS/nixpkgs/2222222222222222222222222222222222222222#go",
+			NarHash:  "sha256-kept",
+		},
+		"ruby@latest": {
+			Resolved: "github:NixOS/nixpkgs/3333333333333333333333333333333333333333#ruby",
+		},
+		"github:numtide/flake-utils": {
+			Resolved: "github:numtide/flake-utils",
+		},
+	}}
+	if err := f.SetNarHashes(flakeLockPath); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"hello@latest":               "sha256-hello",
+		"go@latest":                  "sha256-kept",
+		"ruby@latest":                "",
+		"github:numtide/flake-utils": "",
+	}
+	for pkg, narHash := range want {
+		if got := f.Packages[pkg].NarHash; got != narHash {
+			t.Errorf("got narHash %q for %s, want %q", got, pkg, narHash)
+		}
+	}
+
+	if err := f.SetNarHashes(filepath.Join(t.TempDir(), "flake.lock")); err != nil {
+		t.Errorf("got error %v for a missing flake.lock, want nil", err)
+	}
+}
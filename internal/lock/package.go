@@ -9,12 +9,19 @@ const (
 )
 
 type Package struct {
-	AllowInsecure bool   `json:"allow_insecure,omitempty"`
+	AllowInsecure bool `json:"allow_insecure,omitempty"`
+	// Channel is the nixpkgs channel or revision that devbox.json picks for
+	// the package, if any. Resolved is pinned to its latest revision.
+	Channel       string `json:"channel,omitempty"`
 	LastModified  string `json:"last_modified,omitempty"`
 	PluginVersion string `json:"plugin_version,omitempty"`
 	Resolved      string `json:"resolved,omitempty"`
 	Source        string `json:"source,omitempty"`
 	Version       string `json:"version,omitempty"`
+	// NarHash is the hash of the nixpkgs source that Resolved points to.
+	// Generated flakes pin their nixpkgs inputs to it so that Nix fails
+	// instead of evaluating different sources for the same revision.
+	NarHash string `json:"nar_hash,omitempty"`
 	// Systems is keyed by the system name
 	Systems map[string]*SystemInfo `json:"systems,omitempty"`
 
@@ -36,6 +43,12 @@ func (p *Package) GetSource() string {
 	return p.Source
 }
 
+// IsFlake reports whether the package is a flake installable that's pinned
+// in the lockfile.
+func (p *Package) IsFlake() bool {
+	return p != nil && p.Source == flakeSource
+}
+
 func (p *Package) IsAllowInsecure() bool {
 	if p == nil {
 		return false
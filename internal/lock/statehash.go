@@ -124,5 +124,15 @@ func printDevEnvCacheHash(profileDir string) (string, error) {
 }
 
 func getLockfileHash(projectDir string) (string, error) {
-	return cachehash.JSONFile(lockFilePath(projectDir))
+	hash, err := cachehash.JSONFile(lockFilePath(projectDir))
+	if err != nil {
+		return "", err
+	}
+	// Projects without local packages keep the hash they had before
+	// devbox.local.lock existed.
+	localHash, err := cachehash.JSONFile(localLockFilePath(projectDir))
+	if err != nil || localHash == "" {
+		return hash, err
+	}
+	return hash + localHash, nil
 }
@@ -0,0 +1,62 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Builder is a remote machine that nix builds packages on.
+type Builder struct {
+	URI         string
+	Systems     []string
+	SSHKey      string
+	MaxJobs     int
+	SpeedFactor int
+	Features    []string
+}
+
+// SetBuilders sets the remote builders for the nix commands that devbox runs.
+// They replace the builders in nix.conf. Builders are told to download
+// dependencies from their own substituters instead of copying them from the
+// local machine, which is usually faster.
+func SetBuilders(builders []Builder) {
+	if len(builders) == 0 {
+		SetOption("builders", "")
+		SetOption("builders-use-substitutes", "")
+		return
+	}
+	specs := make([]string, 0, len(builders))
+	for _, b := range builders {
+		specs = append(specs, b.spec())
+	}
+	SetOption("builders", strings.Join(specs, " ; "))
+	SetOption("builders-use-substitutes", "true")
+}
+
+// spec returns the builder in the machine specification format of nix's
+// builders setting, with "-" for the fields that keep their defaults.
+func (b Builder) spec() string {
+	field := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	number := func(n int) string {
+		if n == 0 {
+			return "-"
+		}
+		return strconv.Itoa(n)
+	}
+	return strings.Join([]string{
+		b.URI,
+		field(strings.Join(b.Systems, ",")),
+		field(b.SSHKey),
+		number(b.MaxJobs),
+		number(b.SpeedFactor),
+		field(strings.Join(b.Features, ",")),
+	}, " ")
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import "testing"
+
+func TestBuilderSpec(t *testing.T) {
+	cases := []struct {
+		builder Builder
+		want    string
+	}{
+		{
+			builder: Builder{URI: "ssh://builder"},
+			want:    "ssh://builder - - - - -",
+		},
+		{
+			builder: Builder{
+				URI:         "ssh-ng://me@builder",
+				Systems:     []string{"x86_64-linux", "aarch64-linux"},
+				SSHKey:      "/home/me/.ssh/builder",
+				MaxJobs:     8,
+				SpeedFactor: 2,
+				Features:    []string{"kvm", "big-parallel"},
+			},
+			want: "ssh-ng://me@builder x86_64-linux,aarch64-linux /home/me/.ssh/builder 8 2 kvm,big-parallel",
+		},
+	}
+	for _, tc := range cases {
+		if got := tc.builder.spec(); got != tc.want {
+			t.Errorf("got spec %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/redact"
+)
+
+// ExportClosure writes the store paths and everything they depend on to w in
+// the format of nix-store --export. ImportClosure adds them to another
+// machine's store.
+func ExportClosure(ctx context.Context, w io.Writer, storePaths ...string) error {
+	query := storeCommand(ctx, "--query", "--requisites")
+	query.Args = append(query.Args, storePaths...)
+	stderr := &bytes.Buffer{}
+	query.Stderr = stderr
+	out, err := query.Output()
+	if err != nil {
+		return redact.Errorf("nix-store --query: %w: %s", err, stderr)
+	}
+
+	cmd := storeCommand(ctx, "--export")
+	cmd.Args = append(cmd.Args, strings.Fields(string(out))...)
+	cmd.Stdout = w
+	stderr.Reset()
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return redact.Errorf("nix-store --export: %w: %s", err, stderr)
+	}
+	return nil
+}
+
+// ImportClosure adds the store paths that ExportClosure wrote to r to the
+// Nix store. Nix only imports unsigned paths for trusted users.
+func ImportClosure(ctx context.Context, r io.Reader) error {
+	cmd := storeCommand(ctx, "--import")
+	cmd.Stdin = r
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return redact.Errorf("nix-store --import: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeNixStore puts a nix-store on the PATH that rejects --offline like the
+// real one, and logs its arguments to the returned file.
+func fakeNixStore(t *testing.T) string {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "args.log")
+	script := `#!/bin/sh
+echo "$@" >> "` + log + `"
+for arg in "$@"; do
+  if [ "$arg" = --offline ]; then
+    echo "error: unrecognised flag '--offline'" >&2
+    exit 1
+  fi
+done
+case "$1" in
+  --query) echo /nix/store/aaa-hello ;;
+  --export) echo archive ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "nix-store"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestClosureOffline(t *testing.T) {
+	log := fakeNixStore(t)
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	var archive bytes.Buffer
+	if err := ExportClosure(context.Background(), &archive, "/nix/store/aaa-hello"); err != nil {
+		t.Fatal("got ExportClosure error:", err)
+	}
+	if got := archive.String(); got != "archive\n" {
+		t.Errorf("got exported archive %q, want %q", got, "archive\n")
+	}
+	if err := ImportClosure(context.Background(), &archive); err != nil {
+		t.Fatal("got ImportClosure error:", err)
+	}
+
+	out, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(calls) != 3 {
+		t.Fatalf("got %d nix-store calls, want 3:\n%s", len(calls), out)
+	}
+	for _, call := range calls {
+		if !strings.Contains(call, "--option substitute false") {
+			t.Errorf("got nix-store call %q, want substituters turned off", call)
+		}
+	}
+}
@@ -11,11 +11,24 @@ func command(args ...string) *exec.Cmd {
 
 func commandContext(ctx context.Context, args ...string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, "nix", args...)
-	cmd.Args = append(cmd.Args, ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, Flags()...)
+	return cmd
+}
+
+// storeCommand returns a nix-store command with the options set with
+// SetOption and SetOffline.
+func storeCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "nix-store", args...)
+	cmd.Args = append(cmd.Args, storeOptionFlags()...)
 	return cmd
 }
 
 func allowUnfreeEnv(curEnv []string) []string {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	if disallowUnfree {
+		return curEnv
+	}
 	return append(curEnv, "NIXPKGS_ALLOW_UNFREE=1")
 }
 
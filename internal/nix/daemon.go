@@ -0,0 +1,70 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/fileutil"
+)
+
+const (
+	daemonSocket = "/nix/var/nix/daemon-socket/socket"
+
+	// accessWrite is W_OK for syscall.Access, which the syscall package
+	// doesn't export.
+	accessWrite = 0x2
+)
+
+// useLocalStoreWithoutDaemon makes nix use the store directly when the nix
+// daemon's socket exists but the daemon isn't running, such as in containers
+// and CI runners that install Nix in multi-user mode but don't start the
+// daemon. Nix would otherwise fail to connect to it instead of falling back
+// to the local store like it does when there's no socket. It doesn't change
+// anything if the user chose a store with NIX_REMOTE or nix.conf.
+func useLocalStoreWithoutDaemon() error {
+	if !fileutil.Exists(daemonSocket) || daemonRunning() {
+		return nil
+	}
+	if os.Getenv("NIX_REMOTE") != "" || nixConfSets(readNixConf(), "store") {
+		return nil
+	}
+	if !storeWritable() {
+		return usererr.New(
+			"The Nix daemon isn't running, and you don't have permission to use the Nix store " +
+				"without it. Start the daemon with `sudo systemctl start nix-daemon` (Linux) or " +
+				"`sudo launchctl kickstart -k system/org.nixos.nix-daemon` (macOS), or run " +
+				"`sudo nix-daemon &` if your system doesn't use a service manager.",
+		)
+	}
+	debug.Log("nix daemon isn't running, using the local store")
+	SetOption("store", "local")
+	return nil
+}
+
+// daemonRunning reports whether the nix daemon accepts connections.
+func daemonRunning() bool {
+	conn, err := net.DialTimeout("unix", daemonSocket, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// storeWritable reports whether the user can add paths to the Nix store and
+// its database without the daemon.
+func storeWritable() bool {
+	for _, dir := range []string{"/nix/store", "/nix/var/nix/db"} {
+		if syscall.Access(dir, accessWrite) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,15 @@
 package nix
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"os/exec"
 	"strconv"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/redact"
 )
 
 func EvalPackageName(path string) (string, error) {
@@ -62,3 +68,22 @@ func IsInsecureAllowed() bool {
 	allowed, _ := strconv.ParseBool(os.Getenv("NIXPKGS_ALLOW_INSECURE"))
 	return allowed
 }
+
+// EvalOutPath evaluates the store path of an installable's default output
+// without using the evaluation cache, so the result reflects the installable's
+// current source rather than an earlier evaluation.
+func EvalOutPath(ctx context.Context, installable string, allowInsecure bool) (string, error) {
+	cmd := commandContext(ctx, "eval", "--raw", "--impure", "--option", "eval-cache", "false", installable+".outPath")
+	cmd.Env = allowUnfreeEnv(os.Environ())
+	if allowInsecure {
+		cmd.Env = allowInsecureEnv(cmd.Env)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr := (&exec.ExitError{}); errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", redact.Errorf("nix eval %s: %s", installable, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", redact.Errorf("nix command: %s: %w", redact.Safe(cmd), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
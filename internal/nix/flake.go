@@ -0,0 +1,84 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/redact"
+	"go.jetpack.io/devbox/nix/flake"
+)
+
+// FlakeMetadata is the subset of the output of nix flake metadata --json that
+// devbox uses.
+type FlakeMetadata struct {
+	// Locked is the flake reference pinned to the revision that nix
+	// fetched.
+	Locked flake.Ref `json:"locked"`
+
+	// LastModified is the time of the flake's revision, in seconds since
+	// the Unix epoch.
+	LastModified int64 `json:"lastModified"`
+}
+
+// ResolveFlakeRef pins a flake reference, such as github:numtide/devshell or
+// nixpkgs/nixos-23.11, to the revision it currently points to. It fetches the
+// flake if it isn't in the Nix store.
+func ResolveFlakeRef(ref string) (*FlakeMetadata, error) {
+	cmd := command("flake", "metadata", "--json", ref)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, usererr.New(
+				"Devbox couldn't resolve the flake %s:\n\n%s",
+				ref, strings.TrimSpace(string(exitErr.Stderr)),
+			)
+		}
+		return nil, redact.Errorf("nix command: %s: %w", redact.Safe(cmd), err)
+	}
+	metadata := &FlakeMetadata{}
+	if err := json.Unmarshal(out, metadata); err != nil {
+		return nil, redact.Errorf("unmarshal nix flake metadata output: %w", redact.Safe(err))
+	}
+	return metadata, nil
+}
+
+// FetchFlakeInputs copies the sources of a flake's inputs to the Nix store, so
+// nix can evaluate the flake offline.
+func FetchFlakeInputs(ctx context.Context, flakeRef string) error {
+	cmd := commandContext(ctx, "flake", "archive", flakeRef)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return redact.Errorf("nix command: %s: %w: %s", redact.Safe(cmd), err, out)
+	}
+	return nil
+}
+
+// FlakeNarHash downloads a flake's source again, even if it's cached, and
+// returns its narHash.
+func FlakeNarHash(ctx context.Context, ref string) (string, error) {
+	cmd := commandContext(ctx, "flake", "metadata", "--json", "--refresh", ref)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", redact.Errorf("nix flake metadata %s: %s", ref, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", redact.Errorf("nix command: %s: %w", redact.Safe(cmd), err)
+	}
+	metadata := struct {
+		Locked struct {
+			NarHash string `json:"narHash"`
+		} `json:"locked"`
+	}{}
+	if err := json.Unmarshal(out, &metadata); err != nil {
+		return "", redact.Errorf("unmarshal nix flake metadata output: %w", redact.Safe(err))
+	}
+	return metadata.Locked.NarHash, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"go.jetpack.io/devbox/internal/redact"
+)
+
+// AddGCRoot makes link a symlink to storePath and registers it as an
+// indirect garbage collector root, so nix-collect-garbage keeps storePath and
+// its closure for as long as link exists. Deleting link, or the directory
+// it's in, unregisters the root.
+func AddGCRoot(ctx context.Context, link, storePath string) error {
+	cmd := storeCommand(ctx, "--add-root", link, "--realise", storePath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return redact.Errorf("nix-store --add-root: %w: %s", err, out)
+	}
+	return nil
+}
+
+// FlakeInputPaths returns the store paths of the sources of a flake's inputs,
+// including the inputs of its inputs. It doesn't fetch inputs that aren't in
+// the store.
+func FlakeInputPaths(ctx context.Context, flakeRef string) ([]string, error) {
+	cmd := commandContext(ctx, "flake", "archive", "--json", "--dry-run", flakeRef)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, redact.Errorf("nix command: %s: %w", redact.Safe(cmd), err)
+	}
+
+	return parseFlakeArchive(out)
+}
+
+func parseFlakeArchive(out []byte) ([]string, error) {
+	type archive struct {
+		Path   string              `json:"path"`
+		Inputs map[string]*archive `json:"inputs"`
+	}
+	root := &archive{}
+	if err := json.Unmarshal(out, root); err != nil {
+		return nil, redact.Errorf("unmarshal nix flake archive output: %w", redact.Safe(err))
+	}
+
+	// Skip the flake itself, which is a copy of the project's generated
+	// flake that changes whenever devbox.json does.
+	paths := []string{}
+	var walk func(inputs map[string]*archive)
+	walk = func(inputs map[string]*archive) {
+		for _, input := range inputs {
+			if input.Path != "" && !slices.Contains(paths, input.Path) {
+				paths = append(paths, input.Path)
+			}
+			walk(input.Inputs)
+		}
+	}
+	walk(root.Inputs)
+	slices.Sort(paths)
+	return paths, nil
+}
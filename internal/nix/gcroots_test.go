@@ -0,0 +1,33 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFlakeArchive(t *testing.T) {
+	out := []byte(`{
+  "path": "/nix/store/aaa-source",
+  "inputs": {
+    "nixpkgs": {"inputs": {}, "path": "/nix/store/ccc-source"},
+    "devshell": {
+      "inputs": {
+        "nixpkgs": {"inputs": {}, "path": "/nix/store/ccc-source"},
+        "systems": {"inputs": {}, "path": "/nix/store/ddd-source"}
+      },
+      "path": "/nix/store/bbb-source"
+    }
+  }
+}`)
+	got, err := parseFlakeArchive(out)
+	if err != nil {
+		t.Fatal("got parseFlakeArchive error:", err)
+	}
+	want := []string{"/nix/store/bbb-source", "/nix/store/ccc-source", "/nix/store/ddd-source"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got paths %v, want %v", got, want)
+	}
+}
@@ -11,17 +11,18 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"syscall"
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 
 	"go.jetpack.io/devbox/internal/boxcli/usererr"
 	"go.jetpack.io/devbox/internal/build"
 	"go.jetpack.io/devbox/internal/cmdutil"
 	"go.jetpack.io/devbox/internal/fileutil"
 	"go.jetpack.io/devbox/internal/ux"
-	"go.jetpack.io/devbox/internal/vercheck"
 )
 
 const (
@@ -95,6 +96,13 @@ func dirExists() bool {
 	return fileutil.Exists("/nix")
 }
 
+// nixDirWritableAndEmpty reports whether /nix is an empty directory that the
+// user can write to.
+func nixDirWritableAndEmpty() bool {
+	entries, err := os.ReadDir("/nix")
+	return err == nil && len(entries) == 0 && syscall.Access("/nix", accessWrite) == nil
+}
+
 func isRoot() bool {
 	return os.Geteuid() == 0
 }
@@ -111,21 +119,14 @@ func EnsureNixInstalled(writer io.Writer, withDaemonFunc func() *bool) (err erro
 		if err != nil {
 			return
 		}
-		version := ""
-		version, err = Version()
-		if err != nil {
+		if err = useLocalStoreWithoutDaemon(); err != nil {
+			return
+		}
+		if _, err = Version(); err != nil {
 			err = fmt.Errorf("failed to get nix version: %w", err)
 			return
 		}
-
-		// ensure minimum nix version installed
-		if vercheck.SemverCompare(version, minNixVersion) < 0 {
-			err = usererr.New(
-				"Devbox requires nix of version >= %s. Your version is %s. "+
-					"Please upgrade nix and try again.\n",
-				minNixVersion,
-				version,
-			)
+		if err = EnsureVersion(minNixVersion, "Devbox"); err != nil {
 			return
 		}
 		// call ComputeSystem to ensure its value is internally cached so other
@@ -136,7 +137,12 @@ func EnsureNixInstalled(writer io.Writer, withDaemonFunc func() *bool) (err erro
 	if BinaryInstalled() {
 		return nil
 	}
-	if dirExists() {
+	// An empty /nix that the user owns lets devbox install nix in
+	// single-user mode without sudo. Devbox doesn't manage a store outside
+	// /nix (like nix-portable does), since the environments built in it
+	// reference /nix/store paths that only exist inside its namespace.
+	ownsNixDir := !isRoot() && nixDirWritableAndEmpty()
+	if dirExists() && !ownsNixDir {
 		if err = SourceNixEnv(); err != nil {
 			return err
 		} else if BinaryInstalled() {
@@ -159,7 +165,18 @@ func EnsureNixInstalled(writer io.Writer, withDaemonFunc func() *bool) (err erro
 		fmt.Scanln()
 	}
 
-	if err = Install(writer, withDaemonFunc()); err != nil {
+	daemon := withDaemonFunc()
+	if ownsNixDir {
+		daemon = lo.ToPtr(false)
+	} else if !isRoot() && !cmdutil.Exists("sudo") {
+		return usererr.New(
+			"Nix is not installed, and installing it requires root to create the /nix " +
+				"directory. Ask an administrator to create an empty /nix directory that you own " +
+				"(`sudo mkdir -m 0755 /nix && sudo chown $USER /nix`) and devbox will install " +
+				"Nix there in single-user mode without sudo.",
+		)
+	}
+	if err = Install(writer, daemon); err != nil {
 		return err
 	}
 
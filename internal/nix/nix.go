@@ -75,7 +75,7 @@ func (*Nix) PrintDevEnv(ctx context.Context, args *PrintDevEnvArgs) (*PrintDevEn
 			"nix", "print-dev-env",
 			"path:"+flakeDirResolved,
 		)
-		cmd.Args = append(cmd.Args, ExperimentalFlags()...)
+		cmd.Args = append(cmd.Args, Flags()...)
 		cmd.Args = append(cmd.Args, "--json")
 		debug.Log("Running print-dev-env cmd: %s\n", cmd)
 		data, err = cmd.Output()
@@ -115,15 +115,17 @@ func FlakeNixpkgs(commit string) string {
 	return "nixpkgs/" + commit
 }
 
-func ExperimentalFlags() []string {
+// Flags returns the flags that devbox passes to every nix command: the
+// experimental features it needs, followed by the options set with SetOption.
+func Flags() []string {
 	options := []string{"nix-command", "flakes"}
 	if featureflag.RemoveNixpkgs.Enabled() {
 		options = append(options, "fetch-closure")
 	}
-	return []string{
+	return append([]string{
 		"--extra-experimental-features", "ca-derivations",
 		"--option", "experimental-features", strings.Join(options, " "),
-	}
+	}, optionFlags()...)
 }
 
 func System() string {
@@ -151,15 +153,18 @@ func ComputeSystem() error {
 	if override != "" {
 		cachedSystem = override
 	} else {
-		cmd := exec.Command(
-			"nix", "eval", "--impure", "--raw", "--expr", "builtins.currentSystem",
-		)
-		cmd.Args = append(cmd.Args, ExperimentalFlags()...)
-		out, err := cmd.Output()
+		system, err := cachedNixInfo("system", func() (string, error) {
+			cmd := exec.Command(
+				"nix", "eval", "--impure", "--raw", "--expr", "builtins.currentSystem",
+			)
+			cmd.Args = append(cmd.Args, Flags()...)
+			out, err := cmd.Output()
+			return string(out), err
+		})
 		if err != nil {
 			return err
 		}
-		cachedSystem = string(out)
+		cachedSystem = system
 	}
 	return nil
 }
@@ -178,18 +183,22 @@ func Version() (string, error) {
 		return version, nil
 	}
 
-	cmd := command("--version")
-	outBytes, err := cmd.Output()
+	v, err := cachedNixInfo("version", func() (string, error) {
+		cmd := command("--version")
+		outBytes, err := cmd.Output()
+		if err != nil {
+			return "", redact.Errorf("nix command: %s", redact.Safe(cmd))
+		}
+		v, err := parseVersionOutput(string(outBytes))
+		if err != nil {
+			return "", redact.Errorf("nix command %s: %w", redact.Safe(cmd), err)
+		}
+		return v, nil
+	})
 	if err != nil {
-		return "", redact.Errorf("nix command: %s", redact.Safe(cmd))
-	}
-	out := string(outBytes)
-	const prefix = "nix (Nix) "
-	if !strings.HasPrefix(out, prefix) {
-		return "", redact.Errorf(`nix command %s: expected %q prefix, but output was: %s`,
-			redact.Safe(cmd), redact.Safe(prefix), redact.Safe(out))
+		return "", err
 	}
-	version = strings.TrimSpace(strings.TrimPrefix(out, prefix))
+	version = v
 	return version, nil
 }
 
@@ -0,0 +1,68 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"go.jetpack.io/devbox/internal/cachehash"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/xdg"
+	"go.jetpack.io/pkg/filecache"
+)
+
+// cachedNixInfo returns the cached result of compute, which must only depend
+// on the installed nix binary, such as `nix --version`. Running nix takes a
+// noticeable part of devbox shell's startup, even when the environment itself
+// comes from the print-dev-env cache, so the results are cached until nix
+// changes.
+func cachedNixInfo(name string, compute func() (string, error)) (string, error) {
+	binaryKey, err := nixBinaryKey()
+	if err != nil {
+		debug.Log("not caching nix %s: %v", name, err)
+		return compute()
+	}
+	key, err := cachehash.Bytes([]byte(binaryKey))
+	if err != nil {
+		return compute()
+	}
+	key = name + "-" + key
+
+	cache := filecache.New("devbox/nix", filecache.WithCacheDir(xdg.CacheSubpath("")))
+	if value, err := cache.Get(key); err == nil {
+		return string(value), nil
+	}
+	value, err := compute()
+	if err != nil {
+		return "", err
+	}
+	const oneYear = 12 * 30 * 24 * time.Hour
+	if err := cache.Set(key, []byte(value), oneYear); err != nil {
+		debug.Log("failed to cache nix %s: %v", name, err)
+	}
+	return value, nil
+}
+
+// nixBinaryKey identifies the nix binary in the PATH. It's usually a symlink
+// into the Nix store, and the store path changes with every nix upgrade, so
+// the resolved path and its modification time change whenever nix does.
+func nixBinaryKey() (string, error) {
+	path, err := exec.LookPath("nix")
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %d %d", resolved, info.Size(), info.ModTime().UnixNano()), nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNixBinaryKey(t *testing.T) {
+	storeDir := t.TempDir()
+	nixPath := filepath.Join(storeDir, "nix")
+	if err := os.WriteFile(nixPath, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	binDir := t.TempDir()
+	if err := os.Symlink(nixPath, filepath.Join(binDir, "nix")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	key, err := nixBinaryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := nixBinaryKey(); again != key {
+		t.Errorf("got key %q, then %q for the same binary", key, again)
+	}
+
+	// Upgrading nix changes the binary, so the cached values must change.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(nixPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := nixBinaryKey(); changed == key {
+		t.Errorf("got the same key %q after the binary changed", key)
+	}
+}
@@ -42,7 +42,7 @@ func EnsureNixpkgsPrefetched(w io.Writer, commit string) error {
 		"nix", "flake", "prefetch",
 		FlakeNixpkgs(commit),
 	)
-	cmd.Args = append(cmd.Args, ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, Flags()...)
 	cmd.Stdout = w
 	cmd.Stderr = cmd.Stdout
 	if err := cmd.Run(); err != nil {
@@ -76,7 +76,7 @@ func saveToNixpkgsCommitFile(commit string, commitToLocation map[string]string)
 	cmd := exec.Command("nix", "flake", "prefetch", "--json",
 		FlakeNixpkgs(commit),
 	)
-	cmd.Args = append(cmd.Args, ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, Flags()...)
 	out, err := cmd.Output()
 	if err != nil {
 		return errors.WithStack(err)
@@ -0,0 +1,158 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	optionsMu sync.Mutex
+	// options are nix.conf settings, keyed by name, that override the
+	// user's nix.conf for the nix commands devbox runs.
+	options = map[string]string{}
+	// offline is true when nix commands must not use the network.
+	offline bool
+	// disallowUnfree is true when nixpkgs must not evaluate packages with
+	// unfree licenses.
+	disallowUnfree bool
+	// netrcFile is the temporary netrc file written by SetNetrc.
+	netrcFile string
+)
+
+// SetOption sets a nix.conf setting for every nix command that devbox runs
+// from now on. Setting an option to "" removes it.
+func SetOption(name, value string) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	if value == "" {
+		delete(options, name)
+		return
+	}
+	options[name] = value
+}
+
+// SetListOption is like SetOption for settings that are lists, such as
+// extra-substituters.
+func SetListOption(name string, values []string) {
+	SetOption(name, strings.Join(values, " "))
+}
+
+// SetOffline makes every nix command that devbox runs from now on use only
+// the Nix store and the sources nix has already downloaded.
+func SetOffline(value bool) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	offline = value
+}
+
+// SetAllowUnfree sets whether nixpkgs may evaluate packages with unfree
+// licenses in the nix commands that devbox runs. They're allowed by default.
+func SetAllowUnfree(value bool) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	disallowUnfree = !value
+}
+
+// SetNetrc makes every nix command that devbox runs from now on read binary
+// cache credentials from a netrc file with the given content. The file is a
+// temporary file that only the user can read, and RemoveNetrc deletes it, so
+// the credentials don't outlive the devbox command. An empty netrc removes
+// the file.
+func SetNetrc(netrc []byte) error {
+	RemoveNetrc()
+	if len(netrc) == 0 {
+		return nil
+	}
+	f, err := os.CreateTemp("", "devbox-netrc-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := f.Write(netrc); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return errors.WithStack(err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return errors.WithStack(err)
+	}
+
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	netrcFile = f.Name()
+	options["netrc-file"] = netrcFile
+	return nil
+}
+
+// RemoveNetrc deletes the netrc file written by SetNetrc, if any.
+func RemoveNetrc() {
+	optionsMu.Lock()
+	path := netrcFile
+	netrcFile = ""
+	delete(options, "netrc-file")
+	optionsMu.Unlock()
+	if path != "" {
+		os.Remove(path)
+	}
+}
+
+// AllowUnfree reports whether nixpkgs may evaluate packages with unfree
+// licenses, as set with SetAllowUnfree.
+func AllowUnfree() bool {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	return !disallowUnfree
+}
+
+// IsOffline reports whether nix commands run with --offline.
+func IsOffline() bool {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	return offline
+}
+
+// optionFlags returns the --option flags for the settings set with SetOption,
+// sorted by name, and --offline if nix is offline.
+func optionFlags() []string {
+	flags, offline := settingFlags()
+	if offline {
+		flags = append(flags, "--offline")
+	}
+	return flags
+}
+
+// storeOptionFlags is like optionFlags for the nix-store command, which
+// doesn't have --offline. Turning off substituters keeps it from downloading
+// store paths instead.
+func storeOptionFlags() []string {
+	flags, offline := settingFlags()
+	if offline {
+		flags = append(flags, "--option", "substitute", "false")
+	}
+	return flags
+}
+
+// settingFlags returns the --option flags for the settings set with
+// SetOption, sorted by name, and whether nix is offline.
+func settingFlags() ([]string, bool) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	flags := make([]string, 0, 3*len(names))
+	for _, name := range names {
+		flags = append(flags, "--option", name, options[name])
+	}
+	return flags, offline
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestOptionFlags(t *testing.T) {
+	t.Cleanup(func() {
+		SetOption("extra-substituters", "")
+		SetOption("max-jobs", "")
+		SetOffline(false)
+	})
+	SetListOption("extra-substituters", []string{"https://a.example.com", "https://b.example.com"})
+	SetOption("max-jobs", "4")
+	SetListOption("extra-trusted-public-keys", nil)
+	SetOffline(true)
+
+	want := []string{
+		"--option", "extra-substituters", "https://a.example.com https://b.example.com",
+		"--option", "max-jobs", "4",
+		"--offline",
+	}
+	if got := optionFlags(); !slices.Equal(got, want) {
+		t.Errorf("got flags %q, want %q", got, want)
+	}
+}
+
+func TestSetNetrc(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Cleanup(RemoveNetrc)
+	if err := SetNetrc([]byte("machine cache.example.com password token\n")); err != nil {
+		t.Fatal(err)
+	}
+	flags := optionFlags()
+	if len(flags) != 3 || flags[1] != "netrc-file" {
+		t.Fatalf("got flags %q, want a netrc-file option", flags)
+	}
+	path := flags[2]
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("got netrc file mode %o, want 600", perm)
+	}
+
+	RemoveNetrc()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got stat error %v after RemoveNetrc, want the file removed", err)
+	}
+	if flags := optionFlags(); len(flags) != 0 {
+		t.Errorf("got flags %q after RemoveNetrc, want none", flags)
+	}
+}
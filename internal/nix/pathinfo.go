@@ -0,0 +1,66 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"go.jetpack.io/devbox/internal/redact"
+)
+
+// PathInfo is the size information that nix path-info reports for a store
+// path.
+type PathInfo struct {
+	Path string `json:"path"`
+
+	// NARSize is the size of the store path by itself, in bytes.
+	NARSize int64 `json:"narSize"`
+
+	// ClosureSize is the size of the store path and everything it
+	// depends on at runtime, in bytes.
+	ClosureSize int64 `json:"closureSize"`
+}
+
+// ClosurePathInfo returns the size information of the store paths and of every
+// store path in their closures. Each store path is in the result once.
+func ClosurePathInfo(ctx context.Context, storePaths ...string) ([]PathInfo, error) {
+	if len(storePaths) == 0 {
+		return nil, nil
+	}
+	cmd := commandContext(ctx, "path-info", "--json", "--recursive", "--closure-size")
+	cmd.Args = append(cmd.Args, storePaths...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, redact.Errorf("nix command: %s: %w", redact.Safe(cmd), err)
+	}
+	return parsePathInfo(out)
+}
+
+// parsePathInfo parses the output of nix path-info --json. Nix 2.19 and later
+// print an object keyed by store path, and earlier versions print an array.
+func parsePathInfo(out []byte) ([]PathInfo, error) {
+	var infos []PathInfo
+	if bytes.HasPrefix(bytes.TrimSpace(out), []byte("[")) {
+		if err := json.Unmarshal(out, &infos); err != nil {
+			return nil, redact.Errorf("unmarshal nix path-info output: %w", redact.Safe(err))
+		}
+		return infos, nil
+	}
+
+	byPath := map[string]*PathInfo{}
+	if err := json.Unmarshal(out, &byPath); err != nil {
+		return nil, redact.Errorf("unmarshal nix path-info output: %w", redact.Safe(err))
+	}
+	for path, info := range byPath {
+		// Paths that aren't valid are null.
+		if info == nil {
+			continue
+		}
+		info.Path = path
+		infos = append(infos, *info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParsePathInfo(t *testing.T) {
+	want := []PathInfo{
+		{Path: "/nix/store/aaa-go-1.21.5", NARSize: 100, ClosureSize: 150},
+		{Path: "/nix/store/bbb-glibc-2.38", NARSize: 50, ClosureSize: 50},
+	}
+	outputs := map[string]string{
+		"Array": `[
+  {"path": "/nix/store/aaa-go-1.21.5", "narSize": 100, "closureSize": 150},
+  {"path": "/nix/store/bbb-glibc-2.38", "narSize": 50, "closureSize": 50}
+]`,
+		"Object": `{
+  "/nix/store/aaa-go-1.21.5": {"narSize": 100, "closureSize": 150},
+  "/nix/store/bbb-glibc-2.38": {"narSize": 50, "closureSize": 50},
+  "/nix/store/ccc-missing": null
+}`,
+	}
+	for name, out := range outputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := parsePathInfo([]byte(out))
+			if err != nil {
+				t.Fatal("got parsePathInfo error:", err)
+			}
+			slices.SortFunc(got, func(a, b PathInfo) int { return strings.Compare(a.Path, b.Path) })
+			if !slices.Equal(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -100,7 +100,7 @@ func searchSystem(url, system string) (map[string]*Info, error) {
 
 	// The `^` is added to indicate we want to show all packages
 	cmd := exec.Command("nix", "search", url, "^" /*regex*/, "--json")
-	cmd.Args = append(cmd.Args, ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, Flags()...)
 	if system != "" {
 		cmd.Args = append(cmd.Args, "--system", system)
 	}
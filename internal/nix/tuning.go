@@ -0,0 +1,99 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/xdg"
+)
+
+// Tuning holds the nix settings that control how many builds and downloads
+// run at once. Zero values mean devbox picks a value for the machine.
+type Tuning struct {
+	MaxJobs         int
+	Cores           int
+	HTTPConnections int
+}
+
+// defaultTuning returns the settings devbox uses when neither devbox.json nor
+// nix.conf sets them. Nix's own defaults are one build at a time (max-jobs)
+// with every core (cores) and 25 connections at once. Devbox runs a build
+// for every two cores, with two cores each, so that builds use the machine
+// without oversubscribing it, and allows more connections because most
+// packages are downloaded rather than built.
+func defaultTuning() Tuning {
+	return Tuning{
+		MaxJobs:         max(1, runtime.NumCPU()/2),
+		Cores:           2,
+		HTTPConnections: 50,
+	}
+}
+
+// SetTuning sets max-jobs, cores and http-connections for the nix commands
+// that devbox runs. Settings that are zero in t get devbox's default, unless
+// the user's nix.conf (or NIX_CONFIG) sets them.
+func SetTuning(t Tuning) {
+	defaults := defaultTuning()
+	nixConf := readNixConf()
+	for _, s := range []struct {
+		name            string
+		value, fallback int
+	}{
+		{"max-jobs", t.MaxJobs, defaults.MaxJobs},
+		{"cores", t.Cores, defaults.Cores},
+		{"http-connections", t.HTTPConnections, defaults.HTTPConnections},
+	} {
+		switch {
+		case s.value != 0:
+			SetOption(s.name, strconv.Itoa(s.value))
+		case !nixConfSets(nixConf, s.name):
+			SetOption(s.name, strconv.Itoa(s.fallback))
+		default:
+			SetOption(s.name, "")
+		}
+	}
+}
+
+// readNixConf returns the contents of the system and user nix.conf files and
+// of NIX_CONFIG. Missing files are skipped.
+func readNixConf() []byte {
+	confDir := os.Getenv("NIX_CONF_DIR")
+	if confDir == "" {
+		confDir = "/etc/nix"
+	}
+	var conf []byte
+	for _, path := range []string{
+		filepath.Join(confDir, "nix.conf"),
+		xdg.ConfigSubpath(filepath.Join("nix", "nix.conf")),
+	} {
+		if data, err := os.ReadFile(path); err == nil {
+			conf = append(append(conf, data...), '\n')
+		}
+	}
+	return append(conf, os.Getenv("NIX_CONFIG")...)
+}
+
+// nixConfSets reports whether a nix.conf sets the setting, either directly or
+// with the extra- prefix.
+func nixConfSets(conf []byte, name string) bool {
+	s := bufio.NewScanner(bytes.NewReader(conf))
+	for s.Scan() {
+		key, _, ok := strings.Cut(s.Text(), "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == name || key == "extra-"+name {
+			return true
+		}
+	}
+	return false
+}
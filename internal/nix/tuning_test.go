@@ -0,0 +1,25 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import "testing"
+
+func TestNixConfSets(t *testing.T) {
+	conf := []byte(`
+# max-jobs = 8
+substituters = https://cache.nixos.org
+  cores = 4
+extra-http-connections = 100
+`)
+	for name, want := range map[string]bool{
+		"max-jobs":         false,
+		"cores":            true,
+		"http-connections": true,
+		"substituters":     true,
+	} {
+		if got := nixConfSets(conf, name); got != want {
+			t.Errorf("got nixConfSets(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"go.jetpack.io/devbox/internal/redact"
 	"go.jetpack.io/devbox/internal/ux"
-	"go.jetpack.io/devbox/internal/vercheck"
 )
 
 func ProfileUpgrade(ProfileDir, indexOrName string) error {
@@ -28,17 +27,13 @@ func ProfileUpgrade(ProfileDir, indexOrName string) error {
 }
 
 func FlakeUpdate(ProfileDir string) error {
-	version, err := Version()
-	if err != nil {
-		return err
-	}
 	ux.Finfo(os.Stderr, "Running \"nix flake update\"\n")
 	cmd := exec.Command("nix", "flake", "update")
-	if vercheck.SemverCompare(version, "2.19.0") >= 0 {
+	if AtLeast(VersionFlakeUpdateFlake) {
 		cmd.Args = append(cmd.Args, "--flake")
 	}
 	cmd.Args = append(cmd.Args, ProfileDir)
-	cmd.Args = append(cmd.Args, ExperimentalFlags()...)
+	cmd.Args = append(cmd.Args, Flags()...)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return redact.Errorf(
@@ -0,0 +1,95 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import (
+	"regexp"
+	"strings"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/fileutil"
+	"go.jetpack.io/devbox/internal/redact"
+	"go.jetpack.io/devbox/internal/vercheck"
+)
+
+// Versions of nix that devbox features need, beyond minNixVersion.
+const (
+	// VersionFetchClosureInputAddressed is the first version that can
+	// fetch input-addressed paths with builtins.fetchClosure.
+	VersionFetchClosureInputAddressed = "2.17.0"
+
+	// VersionFlakeUpdateFlake is the first version where nix flake update
+	// takes the flake with --flake instead of as an argument.
+	VersionFlakeUpdateFlake = "2.19.0"
+)
+
+// versionOutputRegex matches the output of nix --version for Nix and for
+// distributions that report the Nix version they're compatible with, such
+// as "nix (Nix) 2.18.1", "nix (Lix, like Nix) 2.90.0" and
+// "nix (Determinate Nix 3.0.0) 2.26.3".
+var versionOutputRegex = regexp.MustCompile(`^nix \(.*\) (\S+)`)
+
+// parseVersionOutput returns the version in the output of nix --version.
+func parseVersionOutput(out string) (string, error) {
+	out = strings.TrimSpace(out)
+	firstLine, _, _ := strings.Cut(out, "\n")
+	match := versionOutputRegex.FindStringSubmatch(firstLine)
+	if match == nil {
+		return "", redact.Errorf("expected nix --version output like \"nix (Nix) 2.18.1\", but it was: %s", redact.Safe(out))
+	}
+	return match[1], nil
+}
+
+// semverCoreRegex matches the major.minor.patch part of a nix version.
+// Unstable builds of nix have versions such as 2.19.0pre20231023_e1d8a35
+// that aren't valid semver.
+var semverCoreRegex = regexp.MustCompile(`^\d+(\.\d+){0,2}`)
+
+// AtLeast reports whether the installed nix is minVersion or newer. It's
+// false if devbox can't tell the version of nix.
+func AtLeast(minVersion string) bool {
+	version, err := Version()
+	if err != nil {
+		return false
+	}
+	return compareVersions(version, minVersion) >= 0
+}
+
+// EnsureVersion returns an error that explains how to upgrade nix if the
+// installed nix is older than minVersion. feature describes what needs it,
+// such as "devbox update".
+func EnsureVersion(minVersion, feature string) error {
+	version, err := Version()
+	if err != nil {
+		return err
+	}
+	if compareVersions(version, minVersion) >= 0 {
+		return nil
+	}
+	return usererr.New(
+		"%s requires Nix %s or newer, but you have Nix %s. %s",
+		feature, minVersion, version, upgradeGuidance(),
+	)
+}
+
+func compareVersions(v1, v2 string) int {
+	core := func(v string) string {
+		if c := semverCoreRegex.FindString(v); c != "" {
+			return c
+		}
+		return v
+	}
+	return vercheck.SemverCompare(core(v1), core(v2))
+}
+
+// upgradeGuidance tells the user how to upgrade their installation of nix.
+func upgradeGuidance() string {
+	if fileutil.Exists("/nix/receipt.json") {
+		return "Upgrade it with `sudo -i nix upgrade-nix`, or reinstall it with the Determinate Nix Installer."
+	}
+	if fileutil.Exists(daemonSocket) {
+		return "Upgrade it with `sudo -i nix upgrade-nix`."
+	}
+	return "Upgrade it with `nix upgrade-nix`."
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package nix
+
+import "testing"
+
+func TestParseVersionOutput(t *testing.T) {
+	cases := map[string]string{
+		"nix (Nix) 2.18.1\n":                                               "2.18.1",
+		"nix (Lix, like Nix) 2.90.0\n":                                     "2.90.0",
+		"nix (Determinate Nix 3.0.0) 2.26.3":                               "2.26.3",
+		"nix (Nix) 2.19.0pre20231023_e1d8a35\nSystem type: x86_64-linux\n": "2.19.0pre20231023_e1d8a35",
+	}
+	for out, want := range cases {
+		got, err := parseVersionOutput(out)
+		if err != nil {
+			t.Errorf("got parseVersionOutput(%q) error: %v", out, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("got parseVersionOutput(%q) = %q, want %q", out, got, want)
+		}
+	}
+	if _, err := parseVersionOutput("command not found"); err == nil {
+		t.Error("got nil error for output without a version")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{"2.18.1", "2.17.0", 1},
+		{"2.12.0", "2.17.0", -1},
+		{"2.19.0pre20231023_e1d8a35", "2.19.0", 0},
+		{"2.19", "2.19.0", 0},
+	}
+	for _, tc := range cases {
+		if got := compareVersions(tc.v1, tc.v2); got != tc.want {
+			t.Errorf("got compareVersions(%q, %q) = %d, want %d", tc.v1, tc.v2, got, tc.want)
+		}
+	}
+}
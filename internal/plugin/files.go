@@ -16,8 +16,12 @@ func getConfigIfAny(pkg Includable, projectDir string) (*config, error) {
 	switch pkg := pkg.(type) {
 	case *devpkg.Package:
 		return getBuiltinPluginConfigIfExists(pkg, projectDir)
+	case *instance:
+		return pkg.buildConfig(projectDir)
 	case *githubPlugin:
 		return pkg.buildConfig(projectDir)
+	case *urlPlugin:
+		return pkg.buildConfig(projectDir)
 	case *localPlugin:
 		content, err := os.ReadFile(pkg.path)
 		if err != nil && !os.IsNotExist(err) {
@@ -0,0 +1,109 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package plugin
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/cachehash"
+	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/ux"
+	"go.jetpack.io/devbox/internal/xdg"
+)
+
+var commitRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// fetchedRepos holds the git plugin checkouts that this process already
+// updated, so each repository is only fetched once per command.
+var fetchedRepos sync.Map
+
+// newGitPlugin returns a plugin in a git repository. rawURL is of the form
+// git+https://host/org/repo.git?ref=<ref>&dir=<dir>, where ref (a branch, tag
+// or commit) and dir (the directory with the plugin.json) are optional. It also
+// accepts git+ssh and git+file URLs.
+func newGitPlugin(rawURL string) (*localPlugin, error) {
+	pluginURL, err := url.Parse(strings.TrimPrefix(rawURL, "git+"))
+	if err != nil {
+		return nil, usererr.New("invalid git plugin url %q: %v", rawURL, err)
+	}
+	ref := pluginURL.Query().Get("ref")
+	dir := pluginURL.Query().Get("dir")
+	pluginURL.RawQuery = ""
+	repo := pluginURL.String()
+
+	checkout, err := gitCheckout(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalPlugin(filepath.Join(checkout, dir, "plugin.json"))
+}
+
+// gitCheckout clones repo at ref to the devbox cache, or updates the existing
+// clone, and returns its directory. Clones of a commit are never updated. If
+// updating a clone fails, such as when the machine is offline, it uses the
+// clone as it is.
+func gitCheckout(repo, ref string) (string, error) {
+	key, _ := cachehash.Bytes([]byte(repo + "#" + ref))
+	dir := filepath.Join(xdg.CacheSubpath("devbox/plugins"), key)
+	if _, fetched := fetchedRepos.Load(dir); fetched {
+		return dir, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		if commitRegex.MatchString(ref) {
+			fetchedRepos.Store(dir, true)
+			return dir, nil
+		}
+		if err := gitFetch(dir, ref); err != nil {
+			ux.Fwarning(os.Stderr, "Couldn't update the plugin %s, using the copy from %s: %v\n", repo, dir, err)
+		}
+		fetchedRepos.Store(dir, true)
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if err := git(dir, "init", "--quiet"); err != nil {
+		return "", err
+	}
+	if err := git(dir, "remote", "add", "origin", repo); err != nil {
+		return "", err
+	}
+	if err := gitFetch(dir, ref); err != nil {
+		os.RemoveAll(dir)
+		return "", usererr.New("Devbox couldn't fetch the plugin %s: %v", repo, err)
+	}
+	fetchedRepos.Store(dir, true)
+	return dir, nil
+}
+
+// gitFetch checks out the latest commit of ref, or of the default branch if
+// ref is empty.
+func gitFetch(dir, ref string) error {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if err := git(dir, "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+		return err
+	}
+	return git(dir, "checkout", "--quiet", "--force", "FETCH_HEAD")
+}
+
+func git(dir string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	debug.Log("running command: %s", cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -12,10 +12,7 @@ func (m *Manager) InitHooks(
 	includes []string,
 ) ([]string, error) {
 	hooks := []string{}
-	allPkgs := []Includable{}
-	for _, pkg := range pkgs {
-		allPkgs = append(allPkgs, pkg)
-	}
+	allPkgs := m.withInstances(pkgs)
 	for _, include := range includes {
 		name, err := m.ParseInclude(include)
 		if err != nil {
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/tailscale/hujson"
 	"go.jetpack.io/devbox/internal/boxcli/usererr"
 	"go.jetpack.io/devbox/internal/cachehash"
 	"go.jetpack.io/devbox/internal/devpkg"
@@ -29,6 +30,10 @@ func (m *Manager) ParseInclude(include string) (Includable, error) {
 		return newLocalPlugin(absPath)
 	} else if includeType == "github" {
 		return newGithubPlugin(name)
+	} else if includeType == "https" {
+		return newURLPlugin(include)
+	} else if strings.HasPrefix(includeType, "git+") {
+		return newGitPlugin(include)
 	}
 	return nil, usererr.New("unknown include type %q", includeType)
 }
@@ -45,6 +50,10 @@ func newLocalPlugin(path string) (*localPlugin, error) {
 	if err != nil {
 		return nil, err
 	}
+	content, err = hujson.Standardize(content)
+	if err != nil {
+		return nil, err
+	}
 	m := map[string]any{}
 	if err := json.Unmarshal(content, &m); err != nil {
 		return nil, err
@@ -125,7 +125,7 @@ func printCreateFiles(cfg *config, w io.Writer, markdown bool) error {
 }
 
 func printEnv(cfg *config, w io.Writer, markdown bool) error {
-	if len(cfg.Env) == 0 {
+	if len(cfg.Env) == 0 && len(cfg.ConnectionEnv) == 0 {
 		return nil
 	}
 
@@ -133,6 +133,9 @@ func printEnv(cfg *config, w io.Writer, markdown bool) error {
 	for name, value := range cfg.Env {
 		envVars += fmt.Sprintf("* %s=%s\n", name, value)
 	}
+	for name, value := range cfg.ConnectionEnv {
+		envVars += fmt.Sprintf("* %s=%s\n", name, value)
+	}
 
 	_, err := fmt.Fprintf(
 		w,
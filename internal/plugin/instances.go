@@ -0,0 +1,144 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package plugin
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.jetpack.io/devbox/internal/devpkg"
+	"go.jetpack.io/devbox/internal/nix"
+	"go.jetpack.io/devbox/plugins"
+)
+
+// instance is a named copy of a package's plugin, such as a second
+// PostgreSQL server. It has its own virtenv, its env variables are prefixed
+// with its name, and its services have its name as a suffix.
+type instance struct {
+	*devpkg.Package
+	name string
+
+	// binDir is the package's bin directory in the Nix store, if the
+	// lockfile has it. The instance's hooks and services put it first in
+	// PATH, so they run the package's programs even if another version of
+	// the package is in the project.
+	binDir string
+}
+
+func (i *instance) CanonicalName() string {
+	return i.Package.CanonicalName() + "-" + i.name
+}
+
+// envPrefix returns the prefix of the instance's env variables, such as
+// LEGACY_ for the instance named legacy.
+func (i *instance) envPrefix() string {
+	return strings.ToUpper(strings.ReplaceAll(i.name, "-", "_")) + "_"
+}
+
+// envPrefix returns the prefix of a plugin's env variables, which is empty
+// unless the plugin is an instance.
+func envPrefix(pkg Includable) string {
+	if i, ok := pkg.(*instance); ok {
+		return i.envPrefix()
+	}
+	return ""
+}
+
+// withInstances returns the plugins of pkgs, with a plugin for each instance
+// of the packages that have instances.
+func (m *Manager) withInstances(pkgs []*devpkg.Package) []Includable {
+	result := []Includable{}
+	for _, pkg := range pkgs {
+		result = append(result, m.packagePlugins(pkg)...)
+	}
+	return result
+}
+
+// packagePlugins returns the package itself, or its instances if it has
+// any.
+func (m *Manager) packagePlugins(pkg *devpkg.Package) []Includable {
+	if len(pkg.Instances) == 0 {
+		return []Includable{pkg}
+	}
+	binDir := ""
+	if m.lockfile != nil {
+		if locked := m.lockfile.Packages[pkg.Raw]; locked != nil {
+			if sys := locked.Systems[nix.System()]; sys != nil && sys.StorePath != "" {
+				binDir = filepath.Join(sys.StorePath, "bin")
+			}
+		}
+	}
+	result := []Includable{}
+	for _, name := range pkg.Instances {
+		result = append(result, &instance{Package: pkg, name: name, binDir: binDir})
+	}
+	return result
+}
+
+func (i *instance) buildConfig(projectDir string) (*config, error) {
+	if i.DisablePlugin {
+		return nil, nil
+	}
+	content, err := plugins.BuiltInForPackage(i.Package.CanonicalName())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	cfg, err := buildConfig(i, projectDir, string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := i.envPrefix()
+	env := make(map[string]string, len(cfg.Env))
+	for k, v := range cfg.Env {
+		env[prefix+k] = v
+	}
+	connectionEnv := make(map[string]string, len(cfg.ConnectionEnv))
+	for k, v := range cfg.ConnectionEnv {
+		connectionEnv[prefix+k] = os.Expand(v, func(name string) string {
+			if _, ok := cfg.Env[name]; ok {
+				name = prefix + name
+			}
+			return "${" + name + "}"
+		})
+	}
+	cfg.Name = i.CanonicalName()
+	cfg.Env = env
+	cfg.ConnectionEnv = connectionEnv
+
+	// The hooks run in a subshell, so the env variables that the prelude
+	// sets for them don't change the shell's.
+	if hooks := cfg.Shell.InitHook.Cmds; len(hooks) > 0 {
+		cfg.Shell.InitHook.Cmds = []string{"(\n" + i.prelude(cfg) + "\n" + strings.Join(hooks, "\n") + "\n)"}
+	}
+	return cfg, nil
+}
+
+// prelude returns a shell command that sets the plugin's env variables, such
+// as PGPORT, to the values of the instance's, such as LEGACY_PGPORT, so the
+// plugin's scripts and services work unchanged.
+func (i *instance) prelude(cfg *config) string {
+	prefix := i.envPrefix()
+	exports := []string{}
+	for k := range cfg.Env {
+		name := strings.TrimPrefix(k, prefix)
+		exports = append(exports, name+`="$`+k+`"`)
+	}
+	slices.Sort(exports)
+	if i.binDir != "" {
+		exports = append(exports, `PATH="`+i.binDir+`:$PATH"`)
+	}
+	if len(exports) == 0 {
+		return ""
+	}
+	return "export " + strings.Join(exports, " ") + "; "
+}
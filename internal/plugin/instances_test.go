@@ -0,0 +1,46 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"go.jetpack.io/devbox/internal/devpkg"
+)
+
+func TestInstanceConfig(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	projectDir := t.TempDir()
+	inst := &instance{
+		Package: &devpkg.Package{Raw: "postgresql_13@latest", IsDevboxPackage: true},
+		name:    "legacy",
+		binDir:  "/nix/store/abc-postgresql-13.14/bin",
+	}
+	cfg, err := inst.buildConfig(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	virtenv := projectDir + "/.devbox/virtenv/postgresql_13-legacy"
+	if got := cfg.Env["LEGACY_PGDATA"]; got != virtenv+"/data" {
+		t.Errorf("got LEGACY_PGDATA=%q, want %q", got, virtenv+"/data")
+	}
+	if _, ok := cfg.Env["PGDATA"]; ok {
+		t.Error("got PGDATA in the instance's env, want only LEGACY_PGDATA")
+	}
+	wantURL := "postgresql:///postgres?host=${LEGACY_PGHOST}&port=${LEGACY_PGPORT}"
+	if got := cfg.ConnectionEnv["LEGACY_DATABASE_URL"]; got != wantURL {
+		t.Errorf("got LEGACY_DATABASE_URL=%q, want %q", got, wantURL)
+	}
+
+	wantPrelude := `export PGDATA="$LEGACY_PGDATA" PGHOST="$LEGACY_PGHOST" PGPORT="$LEGACY_PGPORT" ` +
+		`PATH="/nix/store/abc-postgresql-13.14/bin:$PATH"; `
+	if got := inst.prelude(cfg); got != wantPrelude {
+		t.Errorf("got prelude %q, want %q", got, wantPrelude)
+	}
+	if hooks := cfg.Shell.InitHook.Cmds; len(hooks) != 1 || !strings.Contains(hooks[0], wantPrelude) {
+		t.Errorf("got init hooks %q, want one hook that starts with the prelude", hooks)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/pkg/errors"
+	"github.com/tailscale/hujson"
 	"go.jetpack.io/devbox/internal/devpkg"
 
 	"go.jetpack.io/devbox/internal/conf"
@@ -39,7 +41,10 @@ type config struct {
 	CreateFiles map[string]string `json:"create_files"`
 	Packages    []string          `json:"__packages"`
 	Env         map[string]string `json:"env"`
-	Readme      string            `json:"readme"`
+	// ConnectionEnv has env variables, such as DATABASE_URL, that are
+	// composed from the final environment, including the user's env.
+	ConnectionEnv map[string]string `json:"connection_env"`
+	Readme        string            `json:"readme"`
 	// If true, we remove the package that triggered this plugin from the environment
 	// Useful when we want to replace with flake
 	RemoveTriggerPackage bool `json:"__remove_trigger_package,omitempty"`
@@ -47,18 +52,30 @@ type config struct {
 	Shell struct {
 		// InitHook contains commands that will run at shell startup.
 		InitHook shellcmd.Commands `json:"init_hook,omitempty"`
+		// Path has directories that devbox shell adds to the front of PATH.
+		Path []string `json:"path,omitempty"`
+		// Completions has a completion script to source for each shell,
+		// such as bash, zsh, or fish.
+		Completions map[string]string `json:"completions,omitempty"`
+		// RC has commands that devbox shell runs in bash and zsh after
+		// the init hooks, such as setting up completions or aliases.
+		RC []string `json:"rc,omitempty"`
 	} `json:"shell,omitempty"`
 }
 
 func (c *config) ProcessComposeYaml() (string, string) {
 	for file, contentPath := range c.CreateFiles {
-		if strings.HasSuffix(file, "process-compose.yaml") || strings.HasSuffix(file, "process-compose.yml") {
+		if isProcessComposeFile(file) {
 			return file, contentPath
 		}
 	}
 	return "", ""
 }
 
+func isProcessComposeFile(path string) bool {
+	return strings.HasSuffix(path, "process-compose.yaml") || strings.HasSuffix(path, "process-compose.yml")
+}
+
 func (c *config) Services() (services.Services, error) {
 	if file, _ := c.ProcessComposeYaml(); file != "" {
 		return services.FromProcessCompose(file)
@@ -76,7 +93,12 @@ func (m *Manager) Include(included string) error {
 }
 
 func (m *Manager) Create(pkg *devpkg.Package) error {
-	return m.create(pkg, m.lockfile.Packages[pkg.Raw])
+	for _, p := range m.packagePlugins(pkg) {
+		if err := m.create(p, m.lockfile.Packages[pkg.Raw]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *Manager) create(pkg Includable, locked *lock.Package) error {
@@ -114,7 +136,7 @@ func (m *Manager) create(pkg Includable, locked *lock.Package) error {
 			continue
 		}
 
-		if err = m.createFile(pkg, filePath, contentPath, virtenvPath); err != nil {
+		if err = m.createFile(pkg, cfg, filePath, contentPath, virtenvPath); err != nil {
 			return err
 		}
 
@@ -129,6 +151,7 @@ func (m *Manager) create(pkg Includable, locked *lock.Package) error {
 
 func (m *Manager) createFile(
 	pkg Includable,
+	cfg *config,
 	filePath, contentPath, virtenvPath string,
 ) error {
 	name := pkg.CanonicalName()
@@ -165,12 +188,20 @@ func (m *Manager) createFile(
 	}); err != nil {
 		return errors.WithStack(err)
 	}
+	contents := buf.Bytes()
+	if inst, ok := pkg.(*instance); ok && isProcessComposeFile(filePath) {
+		contents, err = services.InstanceProcessCompose(contents, "-"+inst.name, inst.prelude(cfg))
+		if err != nil {
+			return err
+		}
+	}
+
 	var fileMode fs.FileMode = 0o644
 	if strings.Contains(filePath, "bin/") {
 		fileMode = 0o755
 	}
 
-	if err := os.WriteFile(filePath, buf.Bytes(), fileMode); err != nil {
+	if err := os.WriteFile(filePath, contents, fileMode); err != nil {
 		return errors.WithStack(err)
 	}
 	if fileMode == 0o755 {
@@ -189,10 +220,7 @@ func (m *Manager) Env(
 	includes []string,
 	computedEnv map[string]string,
 ) (map[string]string, error) {
-	allPkgs := []Includable{}
-	for _, pkg := range pkgs {
-		allPkgs = append(allPkgs, pkg)
-	}
+	allPkgs := m.withInstances(pkgs)
 	for _, included := range includes {
 		input, err := m.ParseInclude(included)
 		if err != nil {
@@ -217,10 +245,30 @@ func (m *Manager) Env(
 	return conf.OSExpandEnvMap(env, computedEnv, m.ProjectDir()), nil
 }
 
+// ConnectionEnv returns the plugins' connection env variables, such as
+// DATABASE_URL, without expanding the variables they refer to.
+func (m *Manager) ConnectionEnv(pkgs []*devpkg.Package, includes []string) (map[string]string, error) {
+	allPkgs, err := m.pluginConfigs(pkgs, includes)
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]string{}
+	for _, p := range allPkgs {
+		maps.Copy(env, p.conf.ConnectionEnv)
+	}
+	return env, nil
+}
+
 func buildConfig(pkg Includable, projectDir, content string) (*config, error) {
 	cfg := &config{}
 	name := pkg.CanonicalName()
-	t, err := template.New(name + "-template").Parse(content)
+	t, err := template.New(name + "-template").Funcs(template.FuncMap{
+		// port allocates a port for the project, such as {{ port `PGPORT` 5432 }},
+		// so that services in different projects don't conflict.
+		"port": func(envVar string, preferred int) (int, error) {
+			return services.AllocatePort(projectDir, envPrefix(pkg)+envVar, preferred)
+		},
+	}).Parse(content)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -235,7 +283,13 @@ func buildConfig(pkg Includable, projectDir, content string) (*config, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	return cfg, errors.WithStack(json.Unmarshal(buf.Bytes(), cfg))
+	// Plugin configs may contain comments and trailing commas, just like
+	// devbox.json.
+	jsonb, err := hujson.Standardize(buf.Bytes())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return cfg, errors.WithStack(json.Unmarshal(jsonb, cfg))
 }
 
 func createDir(path string) error {
@@ -6,8 +6,12 @@ package plugin
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
 
 	"go.jetpack.io/devbox/internal/devpkg"
+	"go.jetpack.io/devbox/internal/lock"
 	"go.jetpack.io/devbox/internal/services"
 )
 
@@ -17,36 +21,90 @@ func (m *Manager) GetServices(
 ) (services.Services, error) {
 	allSvcs := services.Services{}
 
-	allPkgs := []Includable{}
+	allPkgs, err := m.pluginConfigs(pkgs, includes)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range allPkgs {
+		svcs, err := p.conf.Services()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading services in plugin \"%s\", skipping", p.conf.Name)
+			continue
+		}
+		for name, svc := range svcs {
+			svc.StateDir = filepath.Join(m.ProjectDir(), VirtenvPath, p.pkg.CanonicalName())
+			allSvcs[name] = svc
+		}
+	}
+
+	return allSvcs, nil
+}
+
+// ResetServiceState deletes the data of the plugin that provides a service,
+// by deleting the plugin's virtenv directory and creating the plugin's files
+// in it again. It returns false if no plugin provides the service.
+func (m *Manager) ResetServiceState(
+	pkgs []*devpkg.Package,
+	includes []string,
+	service string,
+) (bool, error) {
+	allPkgs, err := m.pluginConfigs(pkgs, includes)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range allPkgs {
+		svcs, err := p.conf.Services()
+		if err != nil {
+			continue
+		}
+		if _, ok := svcs[service]; !ok {
+			continue
+		}
+		dir := filepath.Join(m.ProjectDir(), VirtenvPath, p.pkg.CanonicalName())
+		if err := os.RemoveAll(dir); err != nil {
+			return true, errors.WithStack(err)
+		}
+		return true, m.create(p.pkg, p.locked)
+	}
+	return false, nil
+}
+
+// pluginConfig is a package or include that has a plugin, and its config.
+type pluginConfig struct {
+	pkg    Includable
+	locked *lock.Package
+	conf   *config
+}
+
+func (m *Manager) pluginConfigs(
+	pkgs []*devpkg.Package,
+	includes []string,
+) ([]pluginConfig, error) {
+	allPkgs := []pluginConfig{}
 	for _, pkg := range pkgs {
-		allPkgs = append(allPkgs, pkg)
+		for _, p := range m.packagePlugins(pkg) {
+			allPkgs = append(allPkgs, pluginConfig{pkg: p, locked: m.lockfile.Packages[pkg.Raw]})
+		}
 	}
 	for _, include := range includes {
 		name, err := m.ParseInclude(include)
 		if err != nil {
 			return nil, err
 		}
-		allPkgs = append(allPkgs, name)
+		allPkgs = append(allPkgs, pluginConfig{pkg: name, locked: m.lockfile.Packages[include]})
 	}
 
-	for _, pkg := range allPkgs {
-		conf, err := getConfigIfAny(pkg, m.ProjectDir())
+	result := []pluginConfig{}
+	for _, p := range allPkgs {
+		conf, err := getConfigIfAny(p.pkg, m.ProjectDir())
 		if err != nil {
 			return nil, err
 		}
 		if conf == nil {
 			continue
 		}
-
-		svcs, err := conf.Services()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error reading services in plugin \"%s\", skipping", conf.Name)
-			continue
-		}
-		for name, svc := range svcs {
-			allSvcs[name] = svc
-		}
+		p.conf = conf
+		result = append(result, p)
 	}
-
-	return allSvcs, nil
+	return result, nil
 }
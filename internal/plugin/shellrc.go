@@ -0,0 +1,56 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package plugin
+
+import (
+	"cmp"
+	"slices"
+
+	"go.jetpack.io/devbox/internal/devpkg"
+)
+
+// ShellFragment is what a plugin adds to the shellrc of devbox shell.
+type ShellFragment struct {
+	Plugin      string
+	Completions map[string]string
+	RC          []string
+}
+
+// ShellFragments returns the shellrc fragments of the plugins, sorted by
+// plugin name so the shellrc is the same every time.
+func (m *Manager) ShellFragments(pkgs []*devpkg.Package, includes []string) ([]ShellFragment, error) {
+	allPkgs, err := m.pluginConfigs(pkgs, includes)
+	if err != nil {
+		return nil, err
+	}
+	fragments := []ShellFragment{}
+	for _, p := range allPkgs {
+		shell := p.conf.Shell
+		if len(shell.Completions) == 0 && len(shell.RC) == 0 {
+			continue
+		}
+		fragments = append(fragments, ShellFragment{
+			Plugin:      p.conf.Name,
+			Completions: shell.Completions,
+			RC:          shell.RC,
+		})
+	}
+	slices.SortStableFunc(fragments, func(a, b ShellFragment) int { return cmp.Compare(a.Plugin, b.Plugin) })
+	return fragments, nil
+}
+
+// PathEntries returns the directories that the plugins add to the front of
+// the PATH, in order of the plugins' names.
+func (m *Manager) PathEntries(pkgs []*devpkg.Package, includes []string) ([]string, error) {
+	allPkgs, err := m.pluginConfigs(pkgs, includes)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(allPkgs, func(a, b pluginConfig) int { return cmp.Compare(a.conf.Name, b.conf.Name) })
+	var entries []string
+	for _, p := range allPkgs {
+		entries = append(entries, p.conf.Shell.Path...)
+	}
+	return entries, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package plugin
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tailscale/hujson"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/cachehash"
+)
+
+// urlPlugin is a plugin.json that is served over HTTPS. The files it creates
+// are relative to its URL.
+type urlPlugin struct {
+	raw  string
+	base *url.URL
+	name string
+}
+
+func newURLPlugin(rawURL string) (*urlPlugin, error) {
+	pluginURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, usererr.New("invalid plugin url %q: %v", rawURL, err)
+	}
+	if !strings.HasSuffix(pluginURL.Path, ".json") {
+		pluginURL = pluginURL.JoinPath("plugin.json")
+	}
+	plugin := &urlPlugin{raw: rawURL, base: pluginURL}
+
+	content, err := plugin.FileContent("plugin.json")
+	if err != nil {
+		return nil, err
+	}
+	content, err = hujson.Standardize(content)
+	if err != nil {
+		return nil, usererr.New("plugin %s isn't valid JSON: %v", rawURL, err)
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, usererr.New("plugin %s isn't valid JSON: %v", rawURL, err)
+	}
+	plugin.name, _ = m["name"].(string)
+	if !nameRegex.MatchString(plugin.name) {
+		return nil, usererr.New(
+			"plugin %s has an invalid name %q. Name must match %s",
+			rawURL, plugin.name, nameRegex,
+		)
+	}
+	return plugin, nil
+}
+
+func (p *urlPlugin) CanonicalName() string {
+	return p.name
+}
+
+func (p *urlPlugin) Hash() string {
+	h, _ := cachehash.Bytes([]byte(p.base.String()))
+	return h
+}
+
+// FileContent downloads a file next to the plugin.json. "plugin.json" is the
+// plugin itself, even if its URL has a different file name.
+func (p *urlPlugin) FileContent(subpath string) ([]byte, error) {
+	contentURL := p.base
+	if subpath != "plugin.json" {
+		contentURL = p.base.JoinPath("..", subpath)
+	}
+	res, err := http.Get(contentURL.String())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, usererr.New(
+			"failed to get %s for plugin %s (Status code %d).",
+			subpath, p.raw, res.StatusCode,
+		)
+	}
+	return io.ReadAll(res.Body)
+}
+
+func (p *urlPlugin) buildConfig(projectDir string) (*config, error) {
+	content, err := p.FileContent("plugin.json")
+	if err != nil {
+		return nil, err
+	}
+	return buildConfig(p, projectDir, string(content))
+}
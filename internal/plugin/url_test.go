@@ -0,0 +1,66 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package plugin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLPlugin(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(writePlugin(t))))
+	defer server.Close()
+
+	plugin, err := newURLPlugin(server.URL + "/my-plugin")
+	require.NoError(t, err)
+	assert.Equal(t, "my-plugin", plugin.CanonicalName())
+
+	content, err := plugin.FileContent("config/my-plugin.conf")
+	require.NoError(t, err)
+	assert.Equal(t, "port 1234\n", string(content))
+}
+
+func TestGitPlugin(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := writePlugin(t)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "plugin"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	plugin, err := newGitPlugin("git+file://" + repo + "?dir=my-plugin")
+	require.NoError(t, err)
+	assert.Equal(t, "my-plugin", plugin.CanonicalName())
+
+	content, err := plugin.FileContent("config/my-plugin.conf")
+	require.NoError(t, err)
+	assert.Equal(t, "port 1234\n", string(content))
+}
+
+// writePlugin writes a plugin to the my-plugin directory of a new temporary
+// directory, and returns the temporary directory.
+func writePlugin(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"my-plugin/plugin.json":           `{"name": "my-plugin", "version": "0.0.1"}`,
+		"my-plugin/config/my-plugin.conf": "port 1234\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
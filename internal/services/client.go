@@ -7,9 +7,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"syscall"
+	"time"
 
 	"github.com/f1bonacc1/process-compose/src/types"
 )
@@ -19,7 +22,23 @@ type processStates = types.ProcessStates
 type Process struct {
 	Name     string
 	Status   string
+	Health   string
 	ExitCode int
+	Pid      int
+	Restarts int
+	// Uptime is how long the process has been running, as process-compose
+	// formats it, such as "2m3s".
+	Uptime string
+}
+
+// IsActive reports whether the process is running or starting.
+func (p Process) IsActive() bool {
+	switch p.Status {
+	case types.ProcessStateRunning, types.ProcessStateLaunching, types.ProcessStateLaunched,
+		types.ProcessStateRestarting, types.ProcessStateTerminating:
+		return true
+	}
+	return false
 }
 
 func StartServices(ctx context.Context, w io.Writer, serviceName, projectDir string) error {
@@ -56,9 +75,41 @@ func StopServices(ctx context.Context, serviceName, projectDir string, w io.Writ
 	}
 }
 
-func RestartServices(ctx context.Context, serviceName, projectDir string, w io.Writer) error {
-	path := fmt.Sprintf("/process/restart/%s", serviceName)
+// RestartServices stops a service gracefully, with the signal or command in
+// its shutdown config, and starts it again. If the service is still running
+// after timeout, it's killed.
+func RestartServices(ctx context.Context, serviceName, projectDir string, timeout time.Duration, w io.Writer) error {
+	proc, err := getProcess(ctx, projectDir, serviceName)
+	if err != nil {
+		return err
+	}
+	if proc.IsActive() {
+		path := fmt.Sprintf("/process/stop/%s", serviceName)
+		body, status, err := clientRequest(path, http.MethodPatch, projectDir)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("error stopping service %s: %s", serviceName, body)
+		}
+		stopped, err := waitStopped(ctx, projectDir, serviceName, timeout)
+		if err != nil {
+			return err
+		}
+		if !stopped && proc.Pid > 0 {
+			fmt.Fprintf(w, "Service %s didn't stop after %s, killing it.\n", serviceName, timeout)
+			// process-compose starts each process in its own process
+			// group, so this kills the processes it started too.
+			if err := syscall.Kill(-proc.Pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+				return fmt.Errorf("error killing service %s: %w", serviceName, err)
+			}
+			if _, err := waitStopped(ctx, projectDir, serviceName, timeout); err != nil {
+				return err
+			}
+		}
+	}
 
+	path := fmt.Sprintf("/process/start/%s", serviceName)
 	body, status, err := clientRequest(path, http.MethodPost, projectDir)
 	if err != nil {
 		return err
@@ -73,6 +124,43 @@ func RestartServices(ctx context.Context, serviceName, projectDir string, w io.W
 	}
 }
 
+// waitStopped polls the service until it isn't active, and returns false if
+// it's still active after timeout.
+func waitStopped(ctx context.Context, projectDir, serviceName string, timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		proc, err := getProcess(ctx, projectDir, serviceName)
+		if err != nil {
+			return false, err
+		}
+		if !proc.IsActive() {
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// getProcess returns the state of one of the project's processes.
+func getProcess(ctx context.Context, projectDir, serviceName string) (Process, error) {
+	procs, err := ListServices(ctx, projectDir, io.Discard)
+	if err != nil {
+		return Process{}, err
+	}
+	for _, p := range procs {
+		if p.Name == serviceName {
+			return p, nil
+		}
+	}
+	return Process{}, fmt.Errorf("process-compose isn't running service %s", serviceName)
+}
+
 func ListServices(ctx context.Context, projectDir string, w io.Writer) ([]Process, error) {
 	path := "/processes"
 	results := []Process{}
@@ -93,7 +181,11 @@ func ListServices(ctx context.Context, projectDir string, w io.Writer) ([]Proces
 			results = append(results, Process{
 				Name:     process.Name,
 				Status:   process.Status,
+				Health:   process.Health,
 				ExitCode: process.ExitCode,
+				Pid:      process.Pid,
+				Restarts: process.Restarts,
+				Uptime:   process.SystemTime,
 			})
 		}
 		return results, nil
@@ -60,6 +60,56 @@ func NamesFromProcessCompose(content []byte) ([]string, error) {
 	return names, nil
 }
 
+// InstanceProcessCompose rewrites a plugin's process-compose file for a named
+// instance of the plugin. It adds suffix to the name of each process, and
+// runs prelude, which sets the instance's env variables, before each of its
+// commands.
+func InstanceProcessCompose(content []byte, suffix, prelude string) ([]byte, error) {
+	file := map[string]any{}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	procs, _ := file["processes"].(map[string]any)
+	renamed := map[string]any{}
+	for name, p := range procs {
+		renamed[name+suffix] = p
+		proc, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		prependCommand(proc, prelude)
+		for _, probe := range []string{"readiness_probe", "liveness_probe"} {
+			if probe, ok := proc[probe].(map[string]any); ok {
+				if exec, ok := probe["exec"].(map[string]any); ok {
+					prependCommand(exec, prelude)
+				}
+			}
+		}
+		if shutdown, ok := proc["shutdown"].(map[string]any); ok {
+			prependCommand(shutdown, prelude)
+		}
+		if deps, ok := proc["depends_on"].(map[string]any); ok {
+			renamedDeps := map[string]any{}
+			for dep, condition := range deps {
+				if _, ok := procs[dep]; ok {
+					dep += suffix
+				}
+				renamedDeps[dep] = condition
+			}
+			proc["depends_on"] = renamedDeps
+		}
+	}
+	file["processes"] = renamed
+	out, err := yaml.Marshal(file)
+	return out, errors.WithStack(err)
+}
+
+func prependCommand(m map[string]any, prelude string) {
+	if cmd, ok := m["command"].(string); ok && cmd != "" {
+		m["command"] = prelude + cmd
+	}
+}
+
 func lookupProcessCompose(projectDir, path string) string {
 	if path == "" {
 		path = projectDir
@@ -0,0 +1,49 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import "testing"
+
+func TestInstanceProcessCompose(t *testing.T) {
+	got, err := InstanceProcessCompose([]byte(`version: "0.5"
+processes:
+  mysql:
+    command: mysqld
+    readiness_probe:
+      exec:
+        command: mysqladmin ping
+    shutdown:
+      command: mysqladmin shutdown
+    depends_on:
+      mysql_logs:
+        condition: process_started
+      other:
+        condition: process_healthy
+  mysql_logs:
+    command: tail -f mysql.log
+`), "-legacy", "export A=1; ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `processes:
+    mysql-legacy:
+        command: export A=1; mysqld
+        depends_on:
+            mysql_logs-legacy:
+                condition: process_started
+            other:
+                condition: process_healthy
+        readiness_probe:
+            exec:
+                command: export A=1; mysqladmin ping
+        shutdown:
+            command: export A=1; mysqladmin shutdown
+    mysql_logs-legacy:
+        command: export A=1; tail -f mysql.log
+version: "0.5"
+`
+	if string(got) != want {
+		t.Errorf("got process-compose file:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/alessio/shellescape"
+	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+// configProcessComposeFile is where devbox writes the process-compose file for
+// the services in devbox.json, relative to the project directory.
+const configProcessComposeFile = ".devbox/gen/services/process-compose.yaml"
+
+type processComposeFile struct {
+	Version   string                    `yaml:"version"`
+	Processes map[string]processCompose `yaml:"processes"`
+}
+
+type processCompose struct {
+	Command        string                       `yaml:"command"`
+	WorkingDir     string                       `yaml:"working_dir"`
+	Environment    []string                     `yaml:"environment,omitempty"`
+	DependsOn      map[string]processDependency `yaml:"depends_on,omitempty"`
+	ReadinessProbe *health.Probe                `yaml:"readiness_probe,omitempty"`
+}
+
+type processDependency struct {
+	Condition string `yaml:"condition"`
+}
+
+// FromConfig writes a process-compose file for the services defined in
+// devbox.json, including the reverse proxy, and returns them. It returns nil if
+// there aren't any.
+func FromConfig(projectDir string, cfg *devconfig.Config) (Services, error) {
+	defs := maps.Clone(cfg.Services)
+	if cfg.Proxy != nil && len(cfg.Proxy.Routes) > 0 {
+		if _, ok := defs[proxyServiceName]; ok {
+			return nil, usererr.New(
+				"The service %q in devbox.json has the same name as the proxy's service. Rename it to use the proxy.",
+				proxyServiceName,
+			)
+		}
+		proxy, err := writeProxyConfig(projectDir, cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		if defs == nil {
+			defs = map[string]*devconfig.Service{}
+		}
+		defs[proxyServiceName] = proxy
+	}
+	if len(defs) == 0 {
+		return nil, nil
+	}
+	contents, err := processComposeYAML(projectDir, defs)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(projectDir, configProcessComposeFile)
+	if err := writeIfChanged(path, contents); err != nil {
+		return nil, err
+	}
+
+	svcs := Services{}
+	for name := range defs {
+		svcs[name] = Service{Name: name, ProcessComposePath: path}
+	}
+	return svcs, nil
+}
+
+// writeIfChanged leaves an unchanged file alone, so a running process-compose
+// doesn't see it change.
+func writeIfChanged(path string, contents []byte) error {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, contents) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.WriteFile(path, contents, 0o644))
+}
+
+func processComposeYAML(projectDir string, defs map[string]*devconfig.Service) ([]byte, error) {
+	file := processComposeFile{Version: "0.5", Processes: map[string]processCompose{}}
+	for name, def := range defs {
+		if def == nil || def.Command == "" {
+			return nil, usererr.New("The service %q in devbox.json doesn't have a command.", name)
+		}
+		dir := def.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectDir, dir)
+		}
+		proc := processCompose{Command: def.Command, WorkingDir: dir}
+		if def.Schedule != "" {
+			command, err := scheduledCommand(name, def)
+			if err != nil {
+				return nil, err
+			}
+			proc.Command = command
+		}
+		for k, v := range def.Env {
+			proc.Environment = append(proc.Environment, k+"="+v)
+		}
+		slices.Sort(proc.Environment)
+		for _, dep := range def.DependsOn {
+			if proc.DependsOn == nil {
+				proc.DependsOn = map[string]processDependency{}
+			}
+			condition := types.ProcessConditionStarted
+			if defs[dep] != nil && defs[dep].Ready != nil {
+				condition = types.ProcessConditionHealthy
+			}
+			proc.DependsOn[dep] = processDependency{Condition: condition}
+		}
+		if def.Ready != nil {
+			probe, err := readinessProbe(name, def.Ready)
+			if err != nil {
+				return nil, err
+			}
+			proc.ReadinessProbe = probe
+		}
+		file.Processes[name] = proc
+	}
+	out, err := yaml.Marshal(file)
+	return out, errors.WithStack(err)
+}
+
+// devboxExecutable returns the path of the devbox binary that runs scheduled
+// services. Tests replace it.
+var devboxExecutable = os.Executable
+
+// scheduledCommand returns the command that runs a scheduled service. The
+// service stays running, and devbox runs its command at each scheduled time.
+func scheduledCommand(name string, def *devconfig.Service) (string, error) {
+	if _, err := ParseSchedule(def.Schedule); err != nil {
+		return "", err
+	}
+	if def.Ready != nil {
+		return "", usererr.New("The service %q in devbox.json has a schedule, so it can't have a ready check.", name)
+	}
+	exe, err := devboxExecutable()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return fmt.Sprintf(
+		"%s services run-scheduled --schedule %s -- %s",
+		shellescape.Quote(exe), shellescape.Quote(def.Schedule), shellescape.Quote(def.Command),
+	), nil
+}
+
+// readinessProbe converts a service's ready check to a process-compose probe.
+// Bash checks TCP ports, since process-compose can't.
+func readinessProbe(name string, ready *devconfig.ReadyCheck) (*health.Probe, error) {
+	probe := &health.Probe{PeriodSeconds: 1, FailureThreshold: 120}
+	switch {
+	case ready.Command != "":
+		probe.Exec = &health.ExecProbe{Command: ready.Command}
+	case ready.HTTP != "":
+		u, err := url.Parse(ready.HTTP)
+		if err != nil || u.Hostname() == "" {
+			return nil, usererr.New("The ready check of service %q has an invalid URL %q.", name, ready.HTTP)
+		}
+		port, _ := strconv.Atoi(u.Port())
+		if port == 0 {
+			port = 80
+			if u.Scheme == "https" {
+				port = 443
+			}
+		}
+		probe.HttpGet = &health.HttpProbe{Scheme: u.Scheme, Host: u.Hostname(), Port: port, Path: u.RequestURI()}
+	case ready.Port != 0:
+		probe.Exec = &health.ExecProbe{
+			Command: fmt.Sprintf("bash -c 'exec 3<>/dev/tcp/127.0.0.1/%d'", ready.Port),
+		}
+	default:
+		return nil, usererr.New("The ready check of service %q needs a port, http or command.", name)
+	}
+	return probe, nil
+}
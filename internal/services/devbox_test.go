@@ -0,0 +1,120 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+func TestProcessComposeYAML(t *testing.T) {
+	got, err := processComposeYAML("/project", map[string]*devconfig.Service{
+		"web": {
+			Command:   "npm start",
+			Env:       map[string]string{"PORT": "3000", "DEBUG": "1"},
+			DependsOn: []string{"api", "postgresql"},
+		},
+		"worker": {
+			Command:   "./worker",
+			Dir:       "cmd/worker",
+			DependsOn: []string{"web"},
+			Ready:     &devconfig.ReadyCheck{HTTP: "http://localhost:3000/health"},
+		},
+		"api": {Command: "./api", Ready: &devconfig.ReadyCheck{Port: 4000}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `version: "0.5"
+processes:
+    api:
+        command: ./api
+        working_dir: /project
+        readiness_probe:
+            exec:
+                command: bash -c 'exec 3<>/dev/tcp/127.0.0.1/4000'
+            period_seconds: 1
+            failure_threshold: 120
+    web:
+        command: npm start
+        working_dir: /project
+        environment:
+            - DEBUG=1
+            - PORT=3000
+        depends_on:
+            api:
+                condition: process_healthy
+            postgresql:
+                condition: process_started
+    worker:
+        command: ./worker
+        working_dir: /project/cmd/worker
+        depends_on:
+            web:
+                condition: process_started
+        readiness_probe:
+            http_get:
+                host: localhost
+                path: /health
+                scheme: http
+                port: 3000
+            period_seconds: 1
+            failure_threshold: 120
+`
+	if string(got) != want {
+		t.Errorf("got process-compose file:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestProcessComposeYAMLNoCommand(t *testing.T) {
+	_, err := processComposeYAML("/project", map[string]*devconfig.Service{"web": {}})
+	if err == nil {
+		t.Error("got nil error for a service without a command")
+	}
+}
+
+func TestScheduledCommand(t *testing.T) {
+	devboxExecutable = func() (string, error) { return "/bin/devbox", nil }
+	got, err := scheduledCommand("refresh", &devconfig.Service{
+		Command:  "echo 'refreshing' && ./refresh",
+		Schedule: "*/5 * * * *",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `/bin/devbox services run-scheduled --schedule '*/5 * * * *' -- 'echo '"'"'refreshing'"'"' && ./refresh'`
+	if got != want {
+		t.Errorf("got command %s, want %s", got, want)
+	}
+
+	_, err = scheduledCommand("refresh", &devconfig.Service{Command: "./refresh", Schedule: "every 5 minutes"})
+	if err == nil {
+		t.Error("got nil error for a service with an invalid schedule")
+	}
+}
+
+func TestCaddyfile(t *testing.T) {
+	got := caddyfile(&devconfig.Proxy{Routes: map[string]string{
+		"app.localhost": "3000",
+		"api.localhost": "localhost:4000",
+	}})
+	want := `# Generated by devbox from the proxy in devbox.json. Don't edit.
+{
+	admin off
+	auto_https off
+}
+
+http://api.localhost:8080 {
+	reverse_proxy localhost:4000
+}
+
+http://app.localhost:8080 {
+	reverse_proxy 127.0.0.1:3000
+}
+`
+	if got != want {
+		t.Errorf("got Caddyfile:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// followWindow is how many of the latest lines FollowLogs fetches each time
+// it polls process-compose.
+const followWindow = 200
+
+// ProcessLogs returns the last limit lines that a service wrote to stdout and
+// stderr, as process-compose keeps them.
+func ProcessLogs(projectDir, serviceName string, limit int) ([]string, error) {
+	path := fmt.Sprintf("/process/logs/%s/0/%d", serviceName, limit)
+	body, status, err := clientRequest(path, http.MethodGet, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unable to get logs of service %s: %s", serviceName, body)
+	}
+	logs := struct {
+		Logs []string `json:"logs"`
+	}{}
+	if err := json.Unmarshal([]byte(body), &logs); err != nil {
+		return nil, err
+	}
+	return logs.Logs, nil
+}
+
+// FollowLogs writes the last tail lines of each service's logs to w, and then
+// the lines they write until ctx is done. Lines are prefixed with the service's
+// name if there's more than one service.
+func FollowLogs(
+	ctx context.Context, w io.Writer, projectDir string, serviceNames []string, tail int, follow bool,
+) error {
+	printed := map[string][]string{}
+	poll := func(limit int) error {
+		for _, name := range serviceNames {
+			logs, err := ProcessLogs(projectDir, name, limit)
+			if err != nil {
+				return err
+			}
+			for _, line := range newLines(printed[name], logs) {
+				if len(serviceNames) > 1 {
+					fmt.Fprintf(w, "%s | ", name)
+				}
+				fmt.Fprintln(w, line)
+			}
+			printed[name] = logs
+		}
+		return nil
+	}
+
+	if err := poll(tail); err != nil || !follow {
+		return err
+	}
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := poll(followWindow); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Attach writes the last lines of a service's logs to w, and then the lines it
+// writes until it stops or ctx is done. It returns the service's final state
+// if it stopped.
+func Attach(ctx context.Context, w io.Writer, projectDir, serviceName string, tail int) (*Process, error) {
+	var printed []string
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for limit := tail; ; limit = followWindow {
+		// Check the state before reading the logs, so the last lines of a
+		// service that stopped are always printed.
+		proc, err := getProcess(ctx, projectDir, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := ProcessLogs(projectDir, serviceName, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range newLines(printed, logs) {
+			fmt.Fprintln(w, line)
+		}
+		printed = logs
+		if !proc.IsActive() {
+			return &proc, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newLines returns the lines at the end of cur that weren't in prev, which is
+// an earlier window of the same logs.
+func newLines(prev, cur []string) []string {
+	for overlap := min(len(prev), len(cur)); overlap > 0; overlap-- {
+		if slices.Equal(prev[len(prev)-overlap:], cur[:overlap]) {
+			return cur[overlap:]
+		}
+	}
+	return cur
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewLines(t *testing.T) {
+	testCases := []struct {
+		name      string
+		prev, cur []string
+		want      []string
+	}{
+		{"first poll", nil, []string{"a", "b"}, []string{"a", "b"}},
+		{"no new lines", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"new lines", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"window moved", []string{"a", "b", "c"}, []string{"c", "d", "e"}, []string{"d", "e"}},
+		{"no overlap", []string{"a", "b"}, []string{"x", "y"}, []string{"x", "y"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := newLines(tc.prev, tc.cur); !slices.Equal(got, tc.want) {
+				t.Errorf("got new lines %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
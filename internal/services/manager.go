@@ -128,6 +128,7 @@ func StartProcessManager(
 	if ProcessManagerIsRunning(projectDir) {
 		return fmt.Errorf("process-compose is already running. To stop it, run `devbox services stop`")
 	}
+	WarnPortsInUse(w, projectDir)
 
 	// Get the file and lock it right at the start
 
@@ -171,7 +172,7 @@ func StartProcessManager(
 	if processComposeBackground {
 		flags = append(flags, "-t=false")
 		cmd := exec.Command(processComposeBinPath, flags...)
-		return runProcessManagerInBackground(cmd, config, port, projectDir)
+		return runProcessManagerInBackground(cmd, config, port, projectDir, w)
 	}
 
 	cmd := exec.Command(processComposeBinPath, flags...)
@@ -218,14 +219,22 @@ func runProcessManagerInForeground(cmd *exec.Cmd, config *globalProcessComposeCo
 	return writeGlobalProcessComposeJSON(config, configFile)
 }
 
-func runProcessManagerInBackground(cmd *exec.Cmd, config *globalProcessComposeConfig, port int, projectDir string) error {
-	logfile, err := os.OpenFile(processComposeLogfile, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_TRUNC, 0o664)
+// runProcessManagerInBackground starts process-compose as a daemon of the
+// project. It runs in its own session, so it keeps running after the shell
+// or terminal that started it exits, and `devbox services stop` stops it from
+// any shell.
+func runProcessManagerInBackground(cmd *exec.Cmd, config *globalProcessComposeConfig, port int, projectDir string, w io.Writer) error {
+	logPath := filepath.Join(projectDir, processComposeLogfile)
+	logfile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_TRUNC, 0o664)
 	if err != nil {
 		return fmt.Errorf("failed to open process-compose log file: %w", err)
 	}
+	defer logfile.Close()
 
+	cmd.Stdin = nil
 	cmd.Stdout = logfile
 	cmd.Stderr = logfile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
 
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start process-compose: %w", err)
@@ -243,6 +252,12 @@ func runProcessManagerInBackground(cmd *exec.Cmd, config *globalProcessComposeCo
 		return fmt.Errorf("failed to write global process-compose config: %w", err)
 	}
 
+	// The daemon outlives this process, so don't wait for it.
+	if err := cmd.Process.Release(); err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Fprintf(w, "Services are running in the background, and keep running after you close this shell. "+
+		"Stop them with `devbox services stop`. Process-compose logs to %s.\n", logPath)
 	return nil
 }
 
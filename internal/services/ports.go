@@ -0,0 +1,256 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/ux"
+	"go.jetpack.io/devbox/internal/xdg"
+)
+
+// maxPortOffset is how far above a service's preferred port AllocatePort
+// looks for a free one.
+const maxPortOffset = 100
+
+// allocatedPorts caches the ports that this process already allocated, by
+// project directory and name, since plugins are rendered many times per
+// command.
+var allocatedPorts sync.Map
+
+// portAssignments maps each project directory to the ports allocated to it, by
+// name (usually the env variable the port is exported as).
+type portAssignments map[string]map[string]int
+
+// AllocatePort returns the port for a project's service, such as PGPORT. The
+// first time, it picks preferred if it's free and no other project has it, or
+// the next port that is. The assignment is saved in devbox's global data, so
+// the project keeps the same port, and other projects don't get it.
+func AllocatePort(projectDir, name string, preferred int) (int, error) {
+	key := projectDir + "\x00" + name
+	if port, ok := allocatedPorts.Load(key); ok {
+		return port.(int), nil
+	}
+
+	var port int
+	err := updatePortAssignments(func(assignments portAssignments) bool {
+		if p, ok := assignments[projectDir][name]; ok {
+			port = p
+			return false
+		}
+		taken := map[int]bool{}
+		for dir, ports := range assignments {
+			if dir != projectDir {
+				for _, p := range ports {
+					taken[p] = true
+				}
+			}
+		}
+		port = preferred
+		for p := preferred; p <= preferred+maxPortOffset; p++ {
+			if !taken[p] && !PortInUse(p) {
+				port = p
+				break
+			}
+		}
+		if assignments[projectDir] == nil {
+			assignments[projectDir] = map[string]int{}
+		}
+		assignments[projectDir][name] = port
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	allocatedPorts.Store(key, port)
+	return port, nil
+}
+
+// ProjectPorts returns the ports allocated to a project, by name.
+func ProjectPorts(projectDir string) (map[string]int, error) {
+	file, err := os.Open(portAssignmentsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer file.Close()
+	if err := lockFile(file); err != nil {
+		return nil, err
+	}
+	return readPortAssignments(file)[projectDir], nil
+}
+
+// WarnPortsInUse warns about the project's ports that something is already
+// listening on, which keeps its services from starting. A port is the value
+// of the env variable it's exported as, so it includes the ports that the user
+// set themselves.
+func WarnPortsInUse(w io.Writer, projectDir string) {
+	ports, err := ProjectPorts(projectDir)
+	if err != nil {
+		return
+	}
+	names := lo.Keys(ports)
+	slices.Sort(names)
+	for _, name := range names {
+		port := ports[name]
+		if v, err := strconv.Atoi(os.Getenv(name)); err == nil {
+			port = v
+		}
+		if PortInUse(port) {
+			ux.Fwarning(w,
+				"port %d (%s) is already in use, so the service that uses it may fail to start. "+
+					"Stop whatever is listening on it, or set %s to a different port in the env of your devbox.json.\n",
+				port, name, name)
+		}
+	}
+}
+
+// PortInUse reports whether something listens on the port on 127.0.0.1.
+func PortInUse(port int) bool {
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return true
+	}
+	l.Close()
+	return false
+}
+
+// portAssignmentsPath returns the path of the file with the ports that are
+// allocated to every project.
+func portAssignmentsPath() string {
+	return xdg.DataSubpath(filepath.Join("devbox", "global", "ports.json"))
+}
+
+// updatePortAssignments reads the global port assignments and calls update,
+// while holding a lock on the file. If update changes them, it saves them,
+// and removes the projects that no longer exist.
+func updatePortAssignments(update func(portAssignments) (changed bool)) error {
+	path := portAssignmentsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o664)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer file.Close()
+	if err := lockFile(file); err != nil {
+		return err
+	}
+
+	assignments := readPortAssignments(file)
+	if !update(assignments) {
+		return nil
+	}
+	for projectDir := range assignments {
+		if _, err := os.Stat(projectDir); errors.Is(err, os.ErrNotExist) {
+			delete(assignments, projectDir)
+		}
+	}
+
+	data, err := json.MarshalIndent(assignments, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := file.Truncate(0); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = file.WriteAt(data, 0)
+	return errors.WithStack(err)
+}
+
+// readPortAssignments reads the port assignments from a locked file.
+func readPortAssignments(file *os.File) portAssignments {
+	assignments := portAssignments{}
+	if data, err := io.ReadAll(file); err == nil && len(data) > 0 {
+		// A corrupt file starts over rather than failing every command.
+		_ = json.Unmarshal(data, &assignments)
+	}
+	return assignments
+}
+
+// ListeningPorts returns the TCP ports that each process, or a process that
+// it started, listens on, by pid. It uses lsof and ps, so it returns nil if
+// they aren't installed.
+func ListeningPorts(pids []int) map[int][]int {
+	// lsof exits with 1 when nothing is listening.
+	lsof, err := exec.Command("lsof", "-nP", "-iTCP", "-sTCP:LISTEN", "-Fpn").Output()
+	if err != nil && len(lsof) == 0 {
+		return nil
+	}
+	ps, err := exec.Command("ps", "-A", "-o", "pid=,ppid=").Output()
+	if err != nil {
+		return nil
+	}
+	listening, children := parseLsof(lsof), parsePs(ps)
+
+	result := map[int][]int{}
+	for _, pid := range pids {
+		var ports []int
+		queue := []int{pid}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			ports = append(ports, listening[p]...)
+			queue = append(queue, children[p]...)
+		}
+		slices.Sort(ports)
+		result[pid] = slices.Compact(ports)
+	}
+	return result
+}
+
+// parseLsof parses the output of lsof -Fpn into the ports that each process
+// listens on.
+func parseLsof(out []byte) map[int][]int {
+	ports := map[int][]int{}
+	pid := 0
+	for _, line := range strings.Split(string(out), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(line[1:])
+		case 'n':
+			i := strings.LastIndex(line, ":")
+			if port, err := strconv.Atoi(line[i+1:]); err == nil && pid > 0 {
+				ports[pid] = append(ports[pid], port)
+			}
+		}
+	}
+	return ports
+}
+
+// parsePs parses the output of ps -o pid=,ppid= into the children of each
+// process.
+func parsePs(out []byte) map[int][]int {
+	children := map[int][]int{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 == nil && err2 == nil {
+			children[ppid] = append(children[ppid], pid)
+		}
+	}
+	return children
+}
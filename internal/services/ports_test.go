@@ -0,0 +1,115 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAllocatePort(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	projectA, projectB := t.TempDir(), t.TempDir()
+
+	// Something else is listening on the preferred port.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	preferred := l.Addr().(*net.TCPAddr).Port
+
+	portA, err := AllocatePort(projectA, "PGPORT", preferred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if portA == preferred {
+		t.Errorf("got port %d, which is in use", portA)
+	}
+	l.Close()
+
+	// Another project doesn't get the first project's port, even though
+	// nothing is listening on it.
+	portB, err := AllocatePort(projectB, "PGPORT", portA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if portB == portA {
+		t.Errorf("got port %d for both projects", portA)
+	}
+
+	// The first project keeps its port.
+	allocatedPorts = sync.Map{}
+	again, err := AllocatePort(projectA, "PGPORT", preferred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != portA {
+		t.Errorf("got port %d the second time, want %d", again, portA)
+	}
+}
+
+func TestPortAssignmentsReadOnly(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	allocatedPorts = sync.Map{}
+	project := t.TempDir()
+
+	if ports, err := ProjectPorts(project); err != nil || ports != nil {
+		t.Errorf("got ports %v, error %v before any allocation, want none", ports, err)
+	}
+	if _, err := os.Stat(portAssignmentsPath()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got stat error %v, want ProjectPorts not to create ports.json", err)
+	}
+
+	port, err := AllocatePort(project, "PGPORT", 5432)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(portAssignmentsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Make a rewrite visible in the modification time.
+	old := before.ModTime().Add(-time.Hour)
+	if err := os.Chtimes(portAssignmentsPath(), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	allocatedPorts = sync.Map{}
+	if again, err := AllocatePort(project, "PGPORT", 5432); err != nil || again != port {
+		t.Errorf("got port %d, error %v, want %d", again, err, port)
+	}
+	if ports, err := ProjectPorts(project); err != nil || ports["PGPORT"] != port {
+		t.Errorf("got ports %v, error %v, want PGPORT %d", ports, err, port)
+	}
+	after, err := os.Stat(portAssignmentsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(old) {
+		t.Error("got ports.json rewritten by calls that didn't allocate a port")
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	out := "p100\nf5\nn127.0.0.1:5432\nf6\nn[::1]:5432\np200\nf3\nn*:8080\n"
+	got := parseLsof([]byte(out))
+	want := map[int][]int{100: {5432, 5432}, 200: {8080}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParsePs(t *testing.T) {
+	out := "    1     0\n  100     1\n  101   100\n  102   100\n"
+	got := parsePs([]byte(out))
+	want := map[int][]int{0: {1}, 1: {100}, 100: {101, 102}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
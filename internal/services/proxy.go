@@ -0,0 +1,55 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+const (
+	proxyServiceName = "proxy"
+	proxyCaddyfile   = ".devbox/gen/services/Caddyfile"
+	defaultProxyPort = 8080
+)
+
+// writeProxyConfig writes the Caddyfile for the reverse proxy in devbox.json
+// and returns the service that runs it.
+func writeProxyConfig(projectDir string, proxy *devconfig.Proxy) (*devconfig.Service, error) {
+	path := filepath.Join(projectDir, proxyCaddyfile)
+	if err := writeIfChanged(path, []byte(caddyfile(proxy))); err != nil {
+		return nil, err
+	}
+	return &devconfig.Service{
+		Command: fmt.Sprintf("caddy run --adapter caddyfile --config %q", path),
+	}, nil
+}
+
+func caddyfile(proxy *devconfig.Proxy) string {
+	port := proxy.Port
+	if port == 0 {
+		port = defaultProxyPort
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("# Generated by devbox from the proxy in devbox.json. Don't edit.\n")
+	sb.WriteString("{\n\tadmin off\n\tauto_https off\n}\n")
+	hosts := lo.Keys(proxy.Routes)
+	slices.Sort(hosts)
+	for _, host := range hosts {
+		upstream := proxy.Routes[host]
+		if _, err := strconv.Atoi(upstream); err == nil {
+			upstream = "127.0.0.1:" + upstream
+		}
+		fmt.Fprintf(&sb, "\nhttp://%s:%d {\n\treverse_proxy %s\n}\n", host, port, upstream)
+	}
+	return sb.String()
+}
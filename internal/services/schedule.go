@@ -0,0 +1,217 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+)
+
+// Schedule is when a scheduled service runs its command. It's either a cron
+// expression, such as "*/5 * * * *", or an interval, such as "@every 30s".
+type Schedule struct {
+	every time.Duration
+
+	// The fields of a cron expression, as bit sets of the values that
+	// match.
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record whether the day of the month or the day of
+	// the week is a "*". When neither is, cron runs on days that match
+	// either of them.
+	domAny, dowAny bool
+}
+
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// ParseSchedule parses a cron expression with five fields (minute, hour, day
+// of month, month and day of week), a macro such as @hourly, or an interval
+// such as "@every 10m".
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if interval, ok := strings.CutPrefix(expr, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || every < time.Second {
+			return nil, usererr.New("The schedule %q needs an interval of at least 1s, such as \"@every 30s\".", expr)
+		}
+		return &Schedule{every: every}, nil
+	}
+	if macro, ok := scheduleMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, usererr.New(
+			"The schedule %q isn't a cron expression. It needs 5 fields: minute, hour, day of month, month and day of week.",
+			expr,
+		)
+	}
+	s := &Schedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, usererr.New("The minute of the schedule %q is invalid: %v", expr, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, usererr.New("The hour of the schedule %q is invalid: %v", expr, err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, usererr.New("The day of month of the schedule %q is invalid: %v", expr, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, usererr.New("The month of the schedule %q is invalid: %v", expr, err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, usererr.New("The day of week of the schedule %q is invalid: %v", expr, err)
+	}
+	// Both 0 and 7 are Sunday.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges such as
+// 1-5, and steps such as */15 or 0-30/10. names are the names of the values
+// starting at min, such as jan for months.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseCronValue(loStr, min, max, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseCronValue(hiStr, min, max, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("range %q ends before it starts", rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("%q isn't a number from %d to %d", s, min, max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that the schedule runs.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Every schedule matches at least once in 8 years, since a day of the
+	// month that's only in some months, such as the 29th of February,
+	// comes around in leap years.
+	end := t.AddDate(8, 0, 0)
+	for t.Before(end) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// RunScheduled runs command with sh every time the schedule comes around,
+// until ctx is done. It runs the command in the foreground, so a run that
+// lasts past the next scheduled time delays it instead of overlapping.
+// The command's output and a line for each run go to w.
+func RunScheduled(ctx context.Context, w io.Writer, expr, command string) error {
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return err
+	}
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return usererr.New("The schedule %q never runs.", expr)
+		}
+		fmt.Fprintf(w, "Next run at %s\n", next.Format(time.DateTime))
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stdout = w
+		cmd.Stderr = w
+		start := time.Now()
+		if err := cmd.Run(); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(w, "Run failed after %s: %v\n", time.Since(start).Round(time.Millisecond), err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, time.January, 31, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"*/5 * * * *", time.Date(2024, time.January, 31, 10, 10, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 8, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * 0", time.Date(2024, time.February, 4, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"15,45 */6 * * *", time.Date(2024, time.January, 31, 12, 15, 0, 0, time.UTC)},
+		{"@every 90s", now.Add(90 * time.Second)},
+	}
+	for _, test := range tests {
+		s, err := ParseSchedule(test.schedule)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) got error: %v", test.schedule, err)
+			continue
+		}
+		if got := s.Next(now); !got.Equal(test.want) {
+			t.Errorf("ParseSchedule(%q).Next() = %v, want %v", test.schedule, got, test.want)
+		}
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, schedule := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "@every 1ms", "@often"} {
+		if _, err := ParseSchedule(schedule); err == nil {
+			t.Errorf("ParseSchedule(%q) got nil error", schedule)
+		}
+	}
+}
@@ -8,4 +8,9 @@ type Services map[string]Service // name -> Service
 type Service struct {
 	Name               string
 	ProcessComposePath string
+
+	// StateDir is the directory where the plugin that provides the
+	// service keeps its data, such as .devbox/virtenv/postgresql. It's
+	// empty for services that aren't from a plugin.
+	StateDir string
 }
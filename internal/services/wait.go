@@ -0,0 +1,68 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+)
+
+// WaitReady blocks until process-compose reports that the services are ready,
+// or until timeout. A service without a readiness probe is ready once it's
+// running.
+func WaitReady(ctx context.Context, w io.Writer, projectDir string, serviceNames []string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	fmt.Fprintf(w, "Waiting for services to be ready: %s\n", strings.Join(serviceNames, ", "))
+	var waiting []string
+	for {
+		procs, err := ListServices(ctx, projectDir, w)
+		if err != nil {
+			return err
+		}
+		waiting = waiting[:0]
+		for _, name := range serviceNames {
+			i := slices.IndexFunc(procs, func(p Process) bool { return p.Name == name })
+			if i < 0 || !isReady(procs[i]) {
+				waiting = append(waiting, name)
+			}
+		}
+		if len(waiting) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return usererr.New(
+				"These services weren't ready after %s: %s. Run `devbox services logs %s` to see why.",
+				timeout, strings.Join(waiting, ", "), strings.Join(waiting, " "),
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+func isReady(p Process) bool {
+	switch p.Health {
+	case types.ProcessHealthReady:
+		return true
+	case types.ProcessHealthNotReady:
+		return false
+	}
+	// Daemons, such as pg_ctl start, have completed when their server is
+	// running.
+	return p.Status == types.ProcessStateRunning ||
+		p.Status == types.ProcessStateLaunched ||
+		(p.Status == types.ProcessStateCompleted && p.ExitCode == 0)
+}
@@ -3,6 +3,7 @@ package shellgen
 import (
 	"context"
 	"errors"
+	"net/url"
 	"runtime/trace"
 	"slices"
 	"strings"
@@ -44,7 +45,23 @@ func (f *flakeInput) URLWithCaching() string {
 		return f.URL
 	}
 	hash := nix.HashFromNixPkgsURL(f.URL)
-	return getNixpkgsInfo(hash).URL
+	ref := getNixpkgsInfo(hash).URL
+	// Mirrors serve tarballs, so only GitHub URLs are pinned to the narHash.
+	if narHash := f.narHash(); narHash != "" && strings.HasPrefix(ref, "github:") {
+		ref += "?narHash=" + url.QueryEscape(narHash)
+	}
+	return ref
+}
+
+// narHash returns the narHash that devbox.lock records for the input's
+// packages, if any.
+func (f *flakeInput) narHash() string {
+	for _, pkg := range f.Packages {
+		if narHash := pkg.NarHash(); narHash != "" {
+			return narHash
+		}
+	}
+	return ""
 }
 
 func (f *flakeInput) PkgImportName() string {
@@ -133,7 +150,7 @@ func (f *flakeInput) BuildInputs() ([]string, error) {
 // i.e. have a commit hash and always resolve to the same package/version.
 // Note: inputs returned by this function include plugin packages. (php only for now)
 // It's not entirely clear we always want to add plugin packages to the top level
-func flakeInputs(ctx context.Context, packages []*devpkg.Package) []flakeInput {
+func flakeInputs(ctx context.Context, packages []*devpkg.Package, useBinaryCache bool) []flakeInput {
 	defer trace.StartRegion(ctx, "flakeInputs").End()
 
 	var flakeInputs keyedSlice
@@ -145,7 +162,7 @@ func flakeInputs(ctx context.Context, packages []*devpkg.Package) []flakeInput {
 
 		// Don't include cached packages (like local or remote flakes)
 		// that can be fetched from a Binary Cache Store.
-		if featureflag.RemoveNixpkgs.Enabled() {
+		if useBinaryCache && featureflag.RemoveNixpkgs.Enabled() {
 			// TODO(savil): return error?
 			cached, err := pkg.IsInBinaryCache()
 			if err != nil {
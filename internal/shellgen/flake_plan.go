@@ -14,11 +14,24 @@ import (
 // flakePlan contains the data to populate the top level flake.nix file
 // that builds the devbox environment
 type flakePlan struct {
-	BinaryCache string
-	NixpkgsInfo *NixpkgsInfo
-	Packages    []*devpkg.Package
-	FlakeInputs []flakeInput
-	System      string
+	BinaryCache    string
+	UseBinaryCache bool
+	NixpkgsInfo    *NixpkgsInfo
+	Packages       []*devpkg.Package
+	FlakeInputs    []flakeInput
+	System         string
+
+	// PermittedInsecure are insecure packages allowed by the nixpkgs config
+	// in devbox.json, in addition to any allowed by individual packages.
+	PermittedInsecure []string
+
+	// AllowUnfree is the nixpkgs allowUnfree config from devbox.json.
+	AllowUnfree bool
+
+	// Overlays are the paths of the nixpkgs overlay files, relative to the
+	// flake directory. They're set by GenerateForPrintEnv when it writes
+	// the files.
+	Overlays []string
 }
 
 func newFlakePlan(ctx context.Context, devbox devboxer) (*flakePlan, error) {
@@ -48,7 +61,10 @@ func newFlakePlan(ctx context.Context, devbox devboxer) (*flakePlan, error) {
 		return nil, err
 	}
 
-	flakeInputs := flakeInputs(ctx, packages)
+	// Packages fetched from the binary cache wouldn't have the overlays
+	// applied, so they must be built from nixpkgs instead.
+	useBinaryCache := len(devbox.Config().NixpkgsOverlays()) == 0
+	flakeInputs := flakeInputs(ctx, packages, useBinaryCache)
 	nixpkgsInfo := getNixpkgsInfo(devbox.Config().NixPkgsCommitHash())
 
 	// This is an optimization. Try to reuse the nixpkgs info from the flake
@@ -61,11 +77,15 @@ func newFlakePlan(ctx context.Context, devbox devboxer) (*flakePlan, error) {
 	}
 
 	return &flakePlan{
-		BinaryCache: devpkg.BinaryCache,
-		FlakeInputs: flakeInputs,
-		NixpkgsInfo: nixpkgsInfo,
-		Packages:    packages,
-		System:      nix.System(),
+		BinaryCache:    devpkg.BinaryCache,
+		UseBinaryCache: useBinaryCache,
+		FlakeInputs:    flakeInputs,
+		NixpkgsInfo:    nixpkgsInfo,
+		Packages:       packages,
+		System:         nix.System(),
+
+		PermittedInsecure: devbox.Config().PermittedInsecurePackages(),
+		AllowUnfree:       devbox.Config().AllowUnfree(),
 	}, nil
 }
 
@@ -39,6 +39,11 @@ func GenerateForPrintEnv(ctx context.Context, devbox devboxer) error {
 
 	outPath := genPath(devbox)
 
+	plan.Overlays, err = writeOverlays(FlakePath(devbox), devbox.ProjectDir(), devbox.Config().NixpkgsOverlays())
+	if err != nil {
+		return err
+	}
+
 	// Preserving shell.nix to avoid breaking old-style .envrc users
 	err = writeFromTemplate(outPath, plan, "shell.nix", "shell.nix")
 	if err != nil {
@@ -222,6 +227,7 @@ func makeFlakeFile(d devboxer, outPath string, plan *flakePlan) error {
 	if plan.needsGlibcPatch() {
 		cmd.Args = append(cmd.Args, "glibc-patch/flake.nix")
 	}
+	cmd.Args = append(cmd.Args, plan.Overlays...)
 	if debug.IsEnabled() {
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = os.Stdout
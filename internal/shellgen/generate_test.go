@@ -41,8 +41,11 @@ func TestWriteFromTemplate(t *testing.T) {
 			NixpkgsInfo struct {
 				URL string
 			}
-			FlakeInputs []flakeInput
-		}{}
+			FlakeInputs       []flakeInput
+			PermittedInsecure []string
+			AllowUnfree       bool
+			Overlays          []string
+		}{AllowUnfree: true}
 		err = writeFromTemplate(dir, emptyPlan, "flake.nix", "flake.nix")
 		if err != nil {
 			t.Fatal("got error writing flake template:", err)
@@ -86,7 +89,10 @@ var (
 		NixpkgsInfo struct {
 			URL string
 		}
-		FlakeInputs []flakeInput
+		FlakeInputs       []flakeInput
+		PermittedInsecure []string
+		AllowUnfree       bool
+		Overlays          []string
 	}{
 		NixpkgsInfo: struct {
 			URL string
@@ -123,6 +129,9 @@ var (
 				},
 			},
 		},
+		Overlays:          []string{"overlays/0.nix"},
+		PermittedInsecure: []string{"openssl-1.1.1w"},
+		AllowUnfree:       true,
 	}
 )
 
@@ -135,6 +144,13 @@ func (*lockmock) Resolve(pkg string) (*lock.Package, error) {
 	}, nil
 }
 
+func (*lockmock) ResolveChannel(pkg, channel string) (*lock.Package, error) {
+	return &lock.Package{
+		Resolved: "github:NixOS/nixpkgs/b22db301217578a8edfccccf5cedafe5fc54e78b#" + pkg,
+		Channel:  channel,
+	}, nil
+}
+
 func (*lockmock) Get(pkg string) *lock.Package {
 	return nil
 }
@@ -0,0 +1,67 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package shellgen
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+	"go.jetpack.io/devbox/internal/boxcli/usererr"
+	"go.jetpack.io/devbox/internal/devconfig"
+)
+
+// overlaysDir is the directory in the generated flake that holds the nixpkgs
+// overlays from devbox.json. Nix doesn't let a flake import files outside of
+// it, so overlay files are copied here instead of imported in place.
+const overlaysDir = "overlays"
+
+// writeOverlays writes the project's nixpkgs overlays to the flake directory
+// and returns their paths relative to it, in the order they're applied.
+// Overlays that devbox.json no longer has are removed.
+func writeOverlays(flakeDir, projectDir string, overlays []string) ([]string, error) {
+	dir := filepath.Join(flakeDir, overlaysDir)
+	paths := make([]string, 0, len(overlays))
+	for i, overlay := range overlays {
+		src := []byte(overlay)
+		if devconfig.IsOverlayFile(overlay) {
+			path := overlay
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(projectDir, path)
+			}
+			var err error
+			src, err = os.ReadFile(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, usererr.New("The nixpkgs overlay %s doesn't exist.", overlay)
+			}
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		name := fmt.Sprintf("%d.nix", i)
+		if err := overwriteFileIfChanged(filepath.Join(dir, name), src, 0o644); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		paths = append(paths, overlaysDir+"/"+name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return paths, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, entry := range entries {
+		if !slices.Contains(paths, overlaysDir+"/"+entry.Name()) {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package shellgen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWriteOverlays(t *testing.T) {
+	projectDir := t.TempDir()
+	flakeDir := t.TempDir()
+	fileOverlay := "final: prev: { hello = prev.hello.overrideAttrs { doCheck = false; }; }"
+	if err := os.WriteFile(filepath.Join(projectDir, "overlay.nix"), []byte(fileOverlay), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inlineOverlay := "final: prev: { go = prev.go_1_21; }"
+
+	paths, err := writeOverlays(flakeDir, projectDir, []string{"./overlay.nix", inlineOverlay})
+	if err != nil {
+		t.Fatal("got writeOverlays error:", err)
+	}
+	wantPaths := []string{"overlays/0.nix", "overlays/1.nix"}
+	if !slices.Equal(paths, wantPaths) {
+		t.Errorf("got paths %v, want %v", paths, wantPaths)
+	}
+	for i, want := range []string{fileOverlay, inlineOverlay} {
+		got, err := os.ReadFile(filepath.Join(flakeDir, wantPaths[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("got %s = %q, want %q", wantPaths[i], got, want)
+		}
+	}
+
+	t.Run("RemovesOldOverlays", func(t *testing.T) {
+		if _, err := writeOverlays(flakeDir, projectDir, []string{inlineOverlay}); err != nil {
+			t.Fatal("got writeOverlays error:", err)
+		}
+		if _, err := os.Stat(filepath.Join(flakeDir, "overlays/1.nix")); !os.IsNotExist(err) {
+			t.Errorf("got overlays/1.nix stat error %v, want it removed", err)
+		}
+	})
+	t.Run("MissingFile", func(t *testing.T) {
+		if _, err := writeOverlays(flakeDir, projectDir, []string{"missing.nix"}); err == nil {
+			t.Error("got nil error for a missing overlay file")
+		}
+	})
+}
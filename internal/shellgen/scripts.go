@@ -10,9 +10,11 @@ import (
 
 	_ "embed"
 
+	"github.com/alessio/shellescape"
 	"github.com/pkg/errors"
 	"go.jetpack.io/devbox/internal/boxcli/featureflag"
 	"go.jetpack.io/devbox/internal/debug"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
 	"go.jetpack.io/devbox/internal/devconfig"
 	"go.jetpack.io/devbox/internal/devpkg"
 	"go.jetpack.io/devbox/internal/lock"
@@ -44,6 +46,7 @@ type devboxer interface {
 	PluginManager() *plugin.Manager
 	ProjectDir() string
 	ProjectDirHash() string
+	TemplateVars() devconfig.TemplateVars
 }
 
 // WriteScriptsToFiles writes scripts defined in devbox.json into files inside .devbox/gen/scripts.
@@ -70,7 +73,8 @@ func WriteScriptsToFiles(devbox devboxer) error {
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	hooks := strings.Join(append(pluginHooks, devbox.Config().InitHook().String()), "\n\n")
+	vars := devbox.TemplateVars()
+	hooks := strings.Join(append(pluginHooks, vars.Expand(initHookScript(devbox.Config().InitHook()))), "\n\n")
 	// always write it, even if there are no hooks, because scripts will source it.
 	err = writeRawInitHookFile(devbox, hooks)
 	if err != nil {
@@ -86,7 +90,7 @@ func WriteScriptsToFiles(devbox devboxer) error {
 
 	// Write scripts to files.
 	for name, body := range devbox.Config().Scripts() {
-		scriptBody, err := ScriptBody(devbox, body.String())
+		scriptBody, err := ScriptBody(devbox, vars.Expand(body.String()))
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -111,6 +115,59 @@ func WriteScriptsToFiles(devbox devboxer) error {
 	return nil
 }
 
+// initHookScript renders the init hook as a script. When the hook is a list
+// of commands, each complete command reports an error if it fails so that it's
+// easy to tell which step of a multi-step hook went wrong. The rest of the
+// hook still runs, just like it does when the commands are joined with
+// newlines.
+func initHookScript(hook *shellcmd.Commands) string {
+	if hook == nil || hook.MarshalAs != shellcmd.CmdArray || len(hook.Cmds) < 2 {
+		return hook.String()
+	}
+	// Array elements don't have to be complete commands. For example, an
+	// if-statement may be split across multiple elements. In that case,
+	// fall back to joining the commands.
+	for _, cmd := range hook.Cmds {
+		if !isCompleteCommand(cmd) {
+			return hook.String()
+		}
+	}
+
+	// The hooks file is sourced by POSIX shells and fish, so only use syntax
+	// that works in both.
+	lines := make([]string, len(hook.Cmds))
+	for i, cmd := range hook.Cmds {
+		msg := fmt.Sprintf("Error: devbox init_hook command %d failed: %s", i+1, cmd)
+		lines[i] = fmt.Sprintf("%s || echo %s >&2", cmd, shellescape.Quote(msg))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isCompleteCommand reports whether cmd looks like a complete, single-line
+// shell command that can be followed by "|| ...".
+func isCompleteCommand(cmd string) bool {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" || strings.ContainsAny(cmd, "\n#") || strings.Contains(cmd, "<<") {
+		return false
+	}
+	// An unbalanced quote means the command continues in the next element.
+	if strings.Count(cmd, `"`)%2 != 0 || strings.Count(cmd, "'")%2 != 0 {
+		return false
+	}
+	for _, suffix := range []string{"\\", "|", "&", ";", "{", "(", " then", " do", " else", " in"} {
+		if strings.HasSuffix(cmd, suffix) {
+			return false
+		}
+	}
+	first, _, _ := strings.Cut(cmd, " ")
+	switch first {
+	case "if", "then", "elif", "else", "fi", "for", "while", "until", "do", "done",
+		"case", "esac", "function", "}", ")", "begin", "end", "switch":
+		return false
+	}
+	return true
+}
+
 func writeRawInitHookFile(devbox devboxer, body string) (err error) {
 	script, err := createScriptFile(devbox, rawHooksFilename)
 	if err != nil {
@@ -0,0 +1,77 @@
+// Copyright 2023 Jetpack Technologies Inc and contributors. All rights reserved.
+// Use of this source code is governed by the license in the LICENSE file.
+
+package shellgen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.jetpack.io/devbox/internal/devbox/shellcmd"
+)
+
+func TestInitHookScript(t *testing.T) {
+	testCases := []struct {
+		name string
+		hook *shellcmd.Commands
+		want string
+	}{
+		{
+			name: "nil",
+			hook: nil,
+			want: "",
+		},
+		{
+			name: "string",
+			hook: &shellcmd.Commands{MarshalAs: shellcmd.CmdString, Cmds: []string{"echo one\necho two"}},
+			want: "echo one\necho two",
+		},
+		{
+			name: "single command",
+			hook: &shellcmd.Commands{Cmds: []string{"echo one"}},
+			want: "echo one",
+		},
+		{
+			name: "list",
+			hook: &shellcmd.Commands{Cmds: []string{"echo one", "export PS1='devbox> '"}},
+			want: `echo one || echo 'Error: devbox init_hook command 1 failed: echo one' >&2
+export PS1='devbox> ' || echo 'Error: devbox init_hook command 2 failed: export PS1='"'"'devbox> '"'"'' >&2`,
+		},
+		{
+			name: "list with multi-element statement",
+			hook: &shellcmd.Commands{Cmds: []string{"if [ -f .env ]; then", "  . .env", "fi"}},
+			want: "if [ -f .env ]; then\n  . .env\nfi",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, initHookScript(tc.hook)); diff != "" {
+				t.Errorf("wrong init hook script (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInitHookScriptReportsFailedCommand(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	script := initHookScript(&shellcmd.Commands{Cmds: []string{"true", "false", "echo done"}})
+	path := filepath.Join(t.TempDir(), "hooks.sh")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command(sh, path).CombinedOutput()
+	if err != nil {
+		t.Fatalf("got error running hooks: %v\n%s", err, out)
+	}
+	want := "Error: devbox init_hook command 2 failed: false\ndone\n"
+	if got := string(out); got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
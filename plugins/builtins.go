@@ -26,15 +26,23 @@ type BuiltIn struct{}
 
 var builtInMap = map[*regexp.Regexp]string{
 	regexp.MustCompile(`^(apache|apacheHttpd)$`):                       "apacheHttpd",
+	regexp.MustCompile(`^(elasticsearch[0-9]*|opensearch)$`):           "elasticsearch",
 	regexp.MustCompile(`^(gradle|gradle_[0-9])$`):                      "gradle",
 	regexp.MustCompile(`^(ghc|haskell\.compiler\.(.*))$`):              "haskell",
+	regexp.MustCompile(`^apacheKafka(_[0-9_]+)?$`):                     "kafka",
+	regexp.MustCompile(`^localstack$`):                                 "localstack",
+	regexp.MustCompile(`^(mailhog|mailpit)$`):                          "mailhog",
 	regexp.MustCompile(`^mariadb(-embedded)?_?[0-9]*$`):                "mariadb",
+	regexp.MustCompile(`^minio$`):                                      "minio",
+	regexp.MustCompile(`^mongodb(-ce|-[0-9]+_[0-9]+)?$`):               "mongodb",
 	regexp.MustCompile(`^mysql?[0-9]*$`):                               "mysql",
+	regexp.MustCompile(`^nodejs(-slim)?(_[0-9]+|_latest)?$`):           "nodejs",
 	regexp.MustCompile(`^php[0-9]*$`):                                  "php",
 	regexp.MustCompile(`^python3[0-9]*Packages.pip$`):                  "pip",
 	regexp.MustCompile(`^(\w*\.)?poetry$`):                             "poetry",
 	regexp.MustCompile(`^postgresql(_[0-9]+)?$`):                       "postgresql",
 	regexp.MustCompile(`^python[0-9]*(Full|Minimal|-full|-minimal)?$`): "python",
+	regexp.MustCompile(`^rabbitmq-server$`):                            "rabbitmq",
 	regexp.MustCompile(`^redis$`):                                      "redis",
 	regexp.MustCompile(`^j?ruby([0-9_]*[0-9]+)?$`):                     "ruby",
 }
@@ -11,15 +11,29 @@ func TestBuiltInMap(t *testing.T) {
 		"php":                                    "php",
 		"php81":                                  "php",
 		"php82":                                  "php",
+		"elasticsearch":                          "elasticsearch",
+		"elasticsearch7":                         "elasticsearch",
+		"opensearch":                             "elasticsearch",
 		"gradle":                                 "gradle",
 		"gradle_7":                               "gradle",
 		"ghc":                                    "haskell",
 		"haskell.compiler.abc":                   "haskell",
 		"haskell.compiler.native-bignum.ghcHEAD": "haskell",
 		"haskell.compiler.native-bignum.ghc962":  "haskell",
+		"apacheKafka":                            "kafka",
+		"apacheKafka_3_7":                        "kafka",
+		"localstack":                             "localstack",
+		"mailhog":                                "mailhog",
+		"mailpit":                                "mailhog",
 		"mariadb":                                "mariadb",
 		"mariadb_1011":                           "mariadb",
 		"mariadb-embedded":                       "mariadb",
+		"minio":                                  "minio",
+		"minio-client":                           "",
+		"mongodb":                                "mongodb",
+		"mongodb-ce":                             "mongodb",
+		"mongodb-7_0":                            "mongodb",
+		"mongodb-tools":                          "",
 		"mysql":                                  "mysql",
 		"mysql80":                                "mysql",
 		"python3Packages.pip":                    "pip",
@@ -29,6 +43,13 @@ func TestBuiltInMap(t *testing.T) {
 		"python2Minimal":                         "python",
 		"python-full":                            "python",
 		"python-minimal":                         "python",
+		"python-venv":                            "",
+		"nodejs":                                 "nodejs",
+		"nodejs_20":                              "nodejs",
+		"nodejs-slim_18":                         "nodejs",
+		"nodejs_latest":                          "nodejs",
+		"nodePackages.pnpm":                      "",
+		"rabbitmq-server":                        "rabbitmq",
 		"redis":                                  "redis",
 		"ruby":                                   "ruby",
 		"ruby_21":                                "ruby",
@@ -0,0 +1,21 @@
+#! bash
+set -e
+
+if command -v opensearch > /dev/null; then
+    bin=opensearch
+    export OPENSEARCH_PATH_CONF="$ES_PATH_CONF"
+    export OPENSEARCH_JAVA_OPTS="${OPENSEARCH_JAVA_OPTS:-$ES_JAVA_OPTS}"
+else
+    bin=elasticsearch
+fi
+
+# The node needs a writable config directory that also has jvm.options and
+# log4j2.properties, so start with the defaults from the package.
+if [ ! -f "$ES_PATH_CONF/jvm.options" ]; then
+    mkdir -p "$ES_PATH_CONF"
+    cp -R "$(dirname "$(readlink -f "$(command -v "$bin")")")/../config/." "$ES_PATH_CONF"
+    chmod -R u+w "$ES_PATH_CONF"
+fi
+cp "$ES_CONFIG" "$ES_PATH_CONF/$bin.yml"
+
+exec "$bin" -E path.data="$ES_DATA" -E path.logs="{{ .Virtenv }}/logs" -E http.port="$ES_PORT"
@@ -0,0 +1,21 @@
+#! bash
+set -e
+
+# Kafka doesn't read log.dirs or its ports from the environment, so add them
+# to a copy of the project's config.
+config="{{ .Virtenv }}/server.properties"
+{
+    cat "$KAFKA_CONFIG"
+    echo
+    echo "log.dirs=$KAFKA_DATA"
+    echo "controller.quorum.voters=1@127.0.0.1:$KAFKA_CONTROLLER_PORT"
+    echo "listeners=PLAINTEXT://127.0.0.1:$KAFKA_PORT,CONTROLLER://127.0.0.1:$KAFKA_CONTROLLER_PORT"
+    echo "advertised.listeners=PLAINTEXT://127.0.0.1:$KAFKA_PORT"
+} > "$config"
+
+# KRaft brokers need a formatted data directory before their first start.
+if [ ! -f "$KAFKA_DATA/meta.properties" ]; then
+    kafka-storage.sh format --ignore-formatted -t "$(kafka-storage.sh random-uuid)" -c "$config"
+fi
+
+exec kafka-server-start.sh "$config"
@@ -0,0 +1,6 @@
+#! bash
+
+if command -v mailpit > /dev/null; then
+    exec mailpit
+fi
+exec MailHog
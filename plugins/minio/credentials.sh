@@ -0,0 +1,17 @@
+# Generates credentials for the project's MinIO server the first time it's
+# sourced, and exports them. Credentials set in devbox.json take precedence.
+
+if [ -z "${MINIO_ROOT_USER:-}" ] || [ -z "${MINIO_ROOT_PASSWORD:-}" ]; then
+    if [ ! -f "{{ .Virtenv }}/credentials" ]; then
+        (
+            umask 077
+            echo "MINIO_ROOT_USER=devbox-$(LC_ALL=C tr -dc 'a-z0-9' < /dev/urandom | head -c 8)"
+            echo "MINIO_ROOT_PASSWORD=$(LC_ALL=C tr -dc 'A-Za-z0-9' < /dev/urandom | head -c 32)"
+        ) > "{{ .Virtenv }}/credentials"
+    fi
+    . "{{ .Virtenv }}/credentials"
+fi
+
+export MINIO_ROOT_USER MINIO_ROOT_PASSWORD
+export AWS_ACCESS_KEY_ID="$MINIO_ROOT_USER"
+export AWS_SECRET_ACCESS_KEY="$MINIO_ROOT_PASSWORD"
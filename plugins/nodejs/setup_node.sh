@@ -0,0 +1,10 @@
+# Sourced by the init hook, so corepack's yarn and pnpm are on PATH in devbox
+# shell and devbox run.
+
+if [ "$DEVBOX_COREPACK_ENABLED" = "true" ] || [ "$DEVBOX_COREPACK_ENABLED" = "1" ]; then
+    if [ ! -d "{{ .Virtenv }}/corepack-bin" ]; then
+        mkdir -p "{{ .Virtenv }}/corepack-bin"
+        corepack enable --install-directory "{{ .Virtenv }}/corepack-bin"
+    fi
+    export PATH="{{ .Virtenv }}/corepack-bin:$PATH"
+fi
@@ -0,0 +1,8 @@
+#! bash
+
+# Initialize the database the first time the project's shell starts. An empty
+# PGDATA (which the plugin creates) doesn't have a PG_VERSION file yet.
+if [ ! -f "$PGDATA/PG_VERSION" ] && command -v initdb > /dev/null; then
+    initdb --auth=trust --encoding=UTF8 > "$PGHOST/initdb.log" 2>&1 ||
+        echo "postgresql: initdb failed, see $PGHOST/initdb.log" >&2
+fi
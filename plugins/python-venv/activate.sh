@@ -0,0 +1,18 @@
+# Sourced by the init hook, so the virtual environment is active in devbox
+# shell and devbox run.
+
+case "$VENV_DIR" in
+/*) ;;
+*) VENV_DIR="$DEVBOX_PROJECT_ROOT/$VENV_DIR" ;;
+esac
+export VENV_DIR
+
+if [ ! -x "$VENV_DIR/bin/python" ]; then
+    echo "Creating a virtual environment in $VENV_DIR" >&2
+    python3 -m venv "$VENV_DIR"
+fi
+
+. "$VENV_DIR/bin/activate"
+export PATH="{{ .Virtenv }}/bin:$PATH"
+
+venv-sync --if-changed
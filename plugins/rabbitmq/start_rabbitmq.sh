@@ -0,0 +1,9 @@
+#! bash
+set -e
+
+# RabbitMQ doesn't read the management UI's port from the environment, so add
+# it to a copy of the project's config.
+config="{{ .Virtenv }}/rabbitmq.conf"
+{ cat "$RABBITMQ_CONFIG_FILE"; echo; echo "management.tcp.port = $RABBITMQ_MANAGEMENT_PORT"; } > "$config"
+
+RABBITMQ_CONFIG_FILE="$config" exec rabbitmq-server